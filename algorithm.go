@@ -0,0 +1,61 @@
+package hashio
+
+// Algorithm names a hash algorithm known to this package. It is an alias for
+// string so the constants below can be used anywhere a hash name is accepted,
+// such as the keys of the map passed to NewHashReader or the name argument of
+// HashReader.Hash, while still letting APIs document that they expect one of
+// them.
+//
+// The constants are only a convenience: being an alias, Algorithm is no
+// different from string to the compiler, so a literal such as "sha-256" is
+// accepted as well and a typo in one still only surfaces when the digest is
+// requested. Using the constants avoids writing the names out.
+type Algorithm = string
+
+// Algorithms with built in support. The values are the names used by
+// StdCryptoHashes and the With* options.
+const (
+	SHA256 Algorithm = "sha256"
+	SHA1   Algorithm = "sha1"
+	MD5    Algorithm = "md5"
+	SHA384 Algorithm = "sha384"
+	SHA512 Algorithm = "sha512"
+	// SHA512_256 is SHA-512/256, SHA-512 truncated to 256 bits with its own
+	// initial values. On 64-bit platforms it is faster than SHA-256.
+	SHA512_256 Algorithm = "sha512-256"
+	SHA3_256   Algorithm = "sha3-256"
+	SHA3_384   Algorithm = "sha3-384"
+	SHA3_512   Algorithm = "sha3-512"
+	// SHAKE128 and SHAKE256 are the SHA-3 extendable output functions. Their
+	// hashes implement XOF; see XOFHash.
+	SHAKE128 Algorithm = "shake128"
+	SHAKE256 Algorithm = "shake256"
+	BLAKE3   Algorithm = "blake3"
+	// BLAKE2b_256, BLAKE2b_384, BLAKE2b_512 and BLAKE2s_256 are unkeyed BLAKE2
+	// hashes. For a keyed BLAKE2 MAC or another digest size, pass a hash from
+	// the blake2 package to WithHasher instead.
+	BLAKE2b_256 Algorithm = "blake2b-256"
+	BLAKE2b_384 Algorithm = "blake2b-384"
+	BLAKE2b_512 Algorithm = "blake2b-512"
+	BLAKE2s_256 Algorithm = "blake2s-256"
+	// CRC32 (IEEE), CRC32C (Castagnoli), CRC64ISO, CRC64ECMA and Adler32 are
+	// checksums, not cryptographic hashes: they detect accidental corruption
+	// but not tampering. See HashReader.Sum32 and HashReader.Sum64.
+	CRC32     Algorithm = "crc32"
+	CRC32C    Algorithm = "crc32c"
+	CRC64ISO  Algorithm = "crc64-iso"
+	CRC64ECMA Algorithm = "crc64-ecma"
+	Adler32   Algorithm = "adler32"
+	// XXH64, XXH3 (its 64-bit variant), FNV1a32, FNV1a64 and FNV1a128 are fast
+	// non-cryptographic hashes, suited to sharding keys and hash tables. See
+	// HashReader.Sum64.
+	XXH64    Algorithm = "xxh64"
+	XXH3     Algorithm = "xxh3"
+	FNV1a32  Algorithm = "fnv1a-32"
+	FNV1a64  Algorithm = "fnv1a-64"
+	FNV1a128 Algorithm = "fnv1a-128"
+	// SHA256Tree is the SHA-256 tree hash of Amazon S3 Glacier. See TreeHash.
+	SHA256Tree Algorithm = "sha256-tree"
+	// SHA256d is SHA-256 applied twice, as in Bitcoin. See NewSHA256d.
+	SHA256d Algorithm = "sha256d"
+)
@@ -0,0 +1,48 @@
+package hashio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mikewiacek/hashio/blake2"
+)
+
+func ExampleAlgorithm() {
+	reader := NewReader(strings.NewReader("hello I am happy"), WithSHA256())
+	if _, err := io.ReadAll(reader); err != nil {
+		// handle error
+	}
+
+	fmt.Println(reader.HexHash(SHA256))
+
+	// Output: 1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a
+}
+
+func TestBLAKE2(t *testing.T) {
+	mac, err := blake2.NewB(32, []byte("secret"))
+	if err != nil {
+		t.Fatalf("blake2.NewB(): %v", err)
+	}
+	hashers, err := hashersByName([]string{SHA256, BLAKE2b_256, BLAKE2b_384, BLAKE2b_512, BLAKE2s_256})
+	if err != nil {
+		t.Fatalf("hashersByName(): %v", err)
+	}
+	// Keyed hashes are passed like any other hash.Hash.
+	hashers["blake2b-mac"] = mac
+	r := NewHashReader(strings.NewReader("abc"), hashers)
+	io.ReadAll(r)
+	for name, want := range map[string]string{
+		SHA256:        "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		BLAKE2b_256:   "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		BLAKE2b_384:   "6f56a82c8e7ef526dfe182eb5212f7db9df1317e57815dbda46083fc30f54ee6c66ba83be64b302d7cba6ce15bb556f4",
+		BLAKE2b_512:   "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		BLAKE2s_256:   "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982",
+		"blake2b-mac": "e23c35713e7249f369b7c6f60291c0af9d6ac0231d80f46e13b1313fe7f4a4d5",
+	} {
+		if got := r.HexHash(name); got != want {
+			t.Errorf("HashReader.HexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+}
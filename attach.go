@@ -0,0 +1,127 @@
+package hashio
+
+import (
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+)
+
+// AddHasher attaches hasher to h under name, mid-stream: it is passed the data
+// read from the current offset on, so its digest covers that part of the
+// stream only, such as the trailer of a format whose body is hashed with
+// another algorithm. hasher isn't reset, and from then on is treated like the
+// hashes passed to NewHashReader, including by Reset.
+//
+// An error is returned if a hash named name is already attached.
+func (h *HashReader) AddHasher(name string, hasher hash.Hash) error {
+	m, hw, err := attach(h.hashers, h.hw, name, hasher)
+	if err != nil {
+		return err
+	}
+	h.hashers, h.hw = m, hw
+	return nil
+}
+
+// RemoveHasher detaches the hash identified by name from h and returns its
+// digest, which covers the data read up to the current offset. An
+// *UnknownHashError is returned if no such hash is attached. The hash is
+// detached even if the wrapped io.Reader failed, in which case an error
+// wrapping ErrStreamFailed is returned instead of the digest.
+func (h *HashReader) RemoveHasher(name string) ([]byte, error) {
+	m, hw, sum, err := detach(h.hashers, h.hw, name)
+	if err != nil {
+		return nil, err
+	}
+	h.hashers, h.hw = m, hw
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	return sum, nil
+}
+
+// AddHasher attaches hasher to h under name, mid-stream: it is passed the data
+// written from the current offset on, so its digest covers that part of the
+// stream only, such as the trailer of a format whose body is hashed with
+// another algorithm. hasher isn't reset, and from then on is treated like the
+// hashes passed to NewHashWriter, including by Reset.
+//
+// An error is returned if a hash named name is already attached.
+func (h *HashWriter) AddHasher(name string, hasher hash.Hash) error {
+	m, hw, err := attach(h.hashers, h.hw, name, hasher)
+	if err != nil {
+		return err
+	}
+	h.hashers, h.hw = m, hw
+	return nil
+}
+
+// RemoveHasher detaches the hash identified by name from h and returns its
+// digest, which covers the data written up to the current offset. An
+// *UnknownHashError is returned if no such hash is attached. The hash is
+// detached even if the wrapped io.Writer failed, in which case an error
+// wrapping ErrStreamFailed is returned instead of the digest.
+func (h *HashWriter) RemoveHasher(name string) ([]byte, error) {
+	m, hw, sum, err := detach(h.hashers, h.hw, name)
+	if err != nil {
+		return nil, err
+	}
+	h.hashers, h.hw = m, hw
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	return sum, nil
+}
+
+// attach returns a copy of hashers with h added under name, and the writer
+// feeding them, built like hw. The map is copied since it may belong to the
+// caller of NewHashReader or NewHashWriter.
+func attach(hashers map[string]hash.Hash, hw io.Writer, name string, h hash.Hash) (map[string]hash.Hash, io.Writer, error) {
+	if h == nil {
+		return nil, nil, fmt.Errorf("hashio: nil hash attached as %q", name)
+	}
+	if _, ok := hashers[name]; ok {
+		return nil, nil, fmt.Errorf("hashio: hash %q is already attached", name)
+	}
+	if p, ok := hw.(*pipeline); ok {
+		h = pipelinedHash{h, p}
+	}
+	m := maps.Clone(hashers)
+	m[name] = h
+	return m, rewire(m, hw), nil
+}
+
+// detach returns a copy of hashers without the hash identified by name, the
+// writer feeding them, built like hw, and the digest of the hash.
+func detach(hashers map[string]hash.Hash, hw io.Writer, name string) (map[string]hash.Hash, io.Writer, []byte, error) {
+	h, err := lookup(hashers, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m := maps.Clone(hashers)
+	delete(m, name)
+	return m, rewire(m, hw), h.Sum(nil), nil
+}
+
+// rewire returns a writer feeding hashers built like hw, the writer of the
+// hashes before they changed: in parallel with WithParallelHashing, and
+// through the same pipeline, once drained, with WithPipelining. The prefix
+// hashes of a LimitedHashReader stay attached.
+func rewire(hashers map[string]hash.Hash, hw io.Writer) io.Writer {
+	switch w := hw.(type) {
+	case *pipeline:
+		w.wait()
+		unwrapped := make(map[string]hash.Hash, len(hashers))
+		for name, h := range hashers {
+			unwrapped[name] = unwrapHash(h)
+		}
+		w.w = rewire(unwrapped, w.w)
+		return w
+	case parallelHashWriter:
+		return newParallelHashWriter(hashers)
+	case prefixTee:
+		w.hw = rewire(hashers, w.hw)
+		return w
+	}
+	return hashWriter(hashers)
+}
@@ -0,0 +1,89 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAddRemoveHasher(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		opts []Option
+	}{
+		{"serial", nil},
+		{"parallel", []Option{WithParallelHashing()}},
+		{"pipelined", []Option{WithPipelining()}},
+		{"parallel and pipelined", []Option{WithParallelHashing(), WithPipelining()}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			w := NewWriter(nil, append([]Option{WithSHA256(), WithSHA1()}, tc.opts...)...)
+			w.WriteString("body")
+			sum, err := w.RemoveHasher(SHA1)
+			if err != nil {
+				t.Fatalf("HashWriter.RemoveHasher(sha1): %v", err)
+			}
+			// sha1("body")
+			if got, want := hex.EncodeToString(sum), "02083f4579e08a612425c0c1a17ee47add783b94"; got != want {
+				t.Errorf("HashWriter.RemoveHasher(sha1) got: %q, wanted %q", got, want)
+			}
+			if err := w.AddHasher(MD5, md5.New()); err != nil {
+				t.Fatalf("HashWriter.AddHasher(md5): %v", err)
+			}
+			w.WriteString("trailer")
+			for name, want := range map[string]string{
+				SHA256: "c5bac72056d761f25746b694802c4e1e3ec889f0ff89a108a1677dc4981d1211", // sha256("bodytrailer")
+				MD5:    "93707f725009f066ecf17dd8f6409a66",                                 // md5("trailer")
+			} {
+				if got := w.HexHash(name); got != want {
+					t.Errorf("HashWriter.HexHash(%s) got: %q, wanted %q", name, got, want)
+				}
+			}
+			if _, err := w.LookupHash(SHA1); err == nil {
+				t.Errorf("HashWriter.LookupHash(sha1) after RemoveHasher got: nil error, wanted one")
+			}
+		})
+	}
+}
+
+func TestAddHasherErrors(t *testing.T) {
+	hashers := map[string]hash.Hash{SHA1: sha1.New()}
+	r := NewHashReader(strings.NewReader("data"), hashers)
+	if err := r.AddHasher(SHA1, sha1.New()); err == nil {
+		t.Errorf("HashReader.AddHasher(sha1) of an attached name got: nil error, wanted one")
+	}
+	if err := r.AddHasher(MD5, nil); err == nil {
+		t.Errorf("HashReader.AddHasher(md5, nil) got: nil error, wanted one")
+	}
+	if err := r.AddHasher(MD5, md5.New()); err != nil {
+		t.Fatalf("HashReader.AddHasher(md5): %v", err)
+	}
+	// The caller's map isn't modified.
+	if len(hashers) != 1 {
+		t.Errorf("hashers passed to NewHashReader got: %d hashes after AddHasher, wanted 1", len(hashers))
+	}
+	var unknown *UnknownHashError
+	if _, err := r.RemoveHasher(SHA256); !errors.As(err, &unknown) {
+		t.Errorf("HashReader.RemoveHasher(sha256) got: %v, wanted an *UnknownHashError", err)
+	}
+	io.ReadAll(r)
+	// md5("data")
+	if got, want := r.HexHash(MD5), "8d777f385d3dfec8815d20f7496026dc"; got != want {
+		t.Errorf("HashReader.HexHash(md5) got: %q, wanted %q", got, want)
+	}
+
+	boom := errors.New("boom")
+	r = NewHashReader(&errReader{[]byte("partial"), boom}, StdCryptoHashes())
+	io.ReadAll(r)
+	if _, err := r.RemoveHasher(SHA256); !errors.Is(err, ErrStreamFailed) {
+		t.Errorf("HashReader.RemoveHasher(sha256) after error got: %v, wanted %v", err, ErrStreamFailed)
+	}
+	if _, err := r.LookupHash(SHA256); err == nil || errors.Is(err, ErrStreamFailed) {
+		t.Errorf("HashReader.LookupHash(sha256) after RemoveHasher got: %v, wanted an *UnknownHashError", err)
+	}
+}
@@ -0,0 +1,132 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"hash"
+)
+
+// AzureBlock describes a block staged with an Azure Put Block request.
+type AzureBlock struct {
+	// Index is the position of the block in the blob, starting at zero.
+	Index int
+	// Size is the length of the block in bytes.
+	Size int64
+	// ContentMD5 is the base64 encoded MD5 of the block, to be sent as the
+	// Content-MD5 header of its Put Block request.
+	ContentMD5 string
+}
+
+// AzureBlobMD5 is a hash.Hash computing the Content-MD5 values of an Azure
+// block blob staged block by block: the MD5 of every block, which the service
+// validates as each block is staged, and the MD5 of the whole blob. The
+// service doesn't compute the latter when the block list is committed, so it
+// has to be supplied with the x-ms-blob-content-md5 header of the Put Block
+// List request to be stored with the blob. Sum appends the MD5 of the whole
+// blob.
+//
+// Blocks end every blockSize bytes, or when EndBlock is called, so an
+// AzureBlobMD5 passed to NewHashWriter alongside other hashes can follow the
+// blocks of an upload:
+//
+//	blob := hashio.NewAzureBlobMD5(0)
+//	w := hashio.NewHashWriter(&buf, map[string]hash.Hash{"azure": blob})
+//	// For every block: write it to w, then
+//	block, _ := blob.EndBlock()
+//	// and stage buf with block.ContentMD5.
+type AzureBlobMD5 struct {
+	blockSize int64
+	n         int64     // bytes written to the current block
+	block     hash.Hash // MD5 of the current block
+	blob      hash.Hash // MD5 of the whole blob
+	blocks    []AzureBlock
+}
+
+// NewAzureBlobMD5 returns an AzureBlobMD5 ending a block every blockSize
+// bytes. If blockSize is not positive, blocks only end when EndBlock is
+// called.
+func NewAzureBlobMD5(blockSize int64) *AzureBlobMD5 {
+	if blockSize <= 0 {
+		blockSize = -1
+	}
+	return &AzureBlobMD5{blockSize: blockSize, block: md5.New(), blob: md5.New()}
+}
+
+// Write adds p to the current block and to the blob. It never returns an
+// error.
+func (a *AzureBlobMD5) Write(p []byte) (int, error) {
+	written := len(p)
+	a.blob.Write(p)
+	for len(p) > 0 {
+		k := int64(len(p))
+		if a.blockSize > 0 {
+			k = min(k, a.blockSize-a.n)
+		}
+		a.block.Write(p[:k])
+		a.n += k
+		p = p[k:]
+		if a.n == a.blockSize {
+			a.EndBlock()
+		}
+	}
+	return written, nil
+}
+
+// EndBlock ends the current block and returns it. It returns false, and
+// doesn't start a new block, if nothing was written since the last block
+// ended.
+func (a *AzureBlobMD5) EndBlock() (AzureBlock, bool) {
+	if a.n == 0 {
+		return AzureBlock{}, false
+	}
+	b := a.current()
+	a.blocks = append(a.blocks, b)
+	a.block.Reset()
+	a.n = 0
+	return b, true
+}
+
+// current returns the block being written.
+func (a *AzureBlobMD5) current() AzureBlock {
+	return AzureBlock{
+		Index:      len(a.blocks),
+		Size:       a.n,
+		ContentMD5: base64.StdEncoding.EncodeToString(a.block.Sum(nil)),
+	}
+}
+
+// Blocks returns the blocks written so far, including the current block if
+// it isn't empty.
+func (a *AzureBlobMD5) Blocks() []AzureBlock {
+	blocks := append([]AzureBlock(nil), a.blocks...)
+	if a.n > 0 {
+		blocks = append(blocks, a.current())
+	}
+	return blocks
+}
+
+// ContentMD5 returns the base64 encoded MD5 of the whole blob, for the
+// x-ms-blob-content-md5 header of the Put Block List request, or the
+// Content-MD5 header if the blob is uploaded with a single Put Blob request.
+func (a *AzureBlobMD5) ContentMD5() string {
+	return base64.StdEncoding.EncodeToString(a.blob.Sum(nil))
+}
+
+// Sum appends the MD5 of the whole blob to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (a *AzureBlobMD5) Sum(b []byte) []byte { return a.blob.Sum(b) }
+
+// Reset resets the AzureBlobMD5 to its initial state, keeping its block size.
+func (a *AzureBlobMD5) Reset() {
+	a.block.Reset()
+	a.blob.Reset()
+	a.n = 0
+	a.blocks = a.blocks[:0]
+}
+
+// Size returns the number of bytes Sum appends, md5.Size.
+func (a *AzureBlobMD5) Size() int { return md5.Size }
+
+// BlockSize returns the MD5 block size. It is unrelated to the size of the
+// blob's blocks.
+func (a *AzureBlobMD5) BlockSize() int { return md5.BlockSize }
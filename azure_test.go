@@ -0,0 +1,66 @@
+package hashio
+
+import (
+	"bytes"
+	"hash"
+	"reflect"
+	"testing"
+)
+
+func TestAzureBlobMD5(t *testing.T) {
+	want := []AzureBlock{
+		{0, 5, "XUFAKrxLKna5cZ2REBfFkg=="},
+		{1, 5, "ZD07MHnQPj9nB+nVe5Ddfg=="},
+		{2, 2, "lGSQrQ/cF7O4mXYKRFEo8A=="},
+	}
+
+	a := NewAzureBlobMD5(5)
+	w := NewHashWriter(nil, map[string]hash.Hash{"azure": a})
+	w.WriteString("hello, ")
+	w.WriteString("world")
+	if got := a.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AzureBlobMD5.Blocks() got: %+v, wanted %+v", got, want)
+	}
+	if got, wantMD5 := a.ContentMD5(), "5NfxtO0uQtFYmPSyewGdpA=="; got != wantMD5 {
+		t.Errorf("AzureBlobMD5.ContentMD5() got: %q, wanted %q", got, wantMD5)
+	}
+	if got := w.Base64Hash("azure"); got != a.ContentMD5() {
+		t.Errorf("HashWriter.Base64Hash(azure) got: %q, wanted %q", got, a.ContentMD5())
+	}
+
+	a.Reset()
+	if got := a.Blocks(); len(got) != 0 {
+		t.Errorf("AzureBlobMD5.Blocks() after Reset got: %+v, wanted none", got)
+	}
+	if got, wantMD5 := a.ContentMD5(), "1B2M2Y8AsgTpgAmY7PhCfg=="; got != wantMD5 {
+		t.Errorf("AzureBlobMD5.ContentMD5() after Reset got: %q, wanted %q", got, wantMD5)
+	}
+}
+
+func TestAzureBlobMD5EndBlock(t *testing.T) {
+	a := NewAzureBlobMD5(0)
+	var buf bytes.Buffer
+	w := NewHashWriter(&buf, map[string]hash.Hash{"azure": a})
+
+	var got []AzureBlock
+	for _, block := range []string{"hello", ", wor", "ld"} {
+		w.WriteString(block)
+		b, ok := a.EndBlock()
+		if !ok {
+			t.Fatalf("AzureBlobMD5.EndBlock() after %q got: false, wanted true", block)
+		}
+		got = append(got, b)
+	}
+	if _, ok := a.EndBlock(); ok {
+		t.Errorf("AzureBlobMD5.EndBlock() of an empty block got: true, wanted false")
+	}
+	if want := a.Blocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AzureBlobMD5.EndBlock() got: %+v, wanted %+v", got, want)
+	}
+	if got[1].ContentMD5 != "ZD07MHnQPj9nB+nVe5Ddfg==" || got[2].Size != 2 {
+		t.Errorf("AzureBlobMD5.EndBlock() got blocks %+v", got)
+	}
+	if buf.String() != "hello, world" {
+		t.Errorf("HashWriter wrote %q, wanted %q", buf.String(), "hello, world")
+	}
+}
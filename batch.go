@@ -0,0 +1,134 @@
+package hashio
+
+import (
+	"context"
+	"hash"
+	"io"
+	"sync"
+)
+
+// Batch hashes many inputs with a bounded number of goroutines. Each worker
+// reuses its hashes and copy buffer across items, which makes a Batch much
+// cheaper than a HashReader per item when hashing many small objects.
+//
+// Items are added with Add and hashed by Run. A Batch must not be modified
+// while Run is in progress.
+type Batch struct {
+	factories map[string]func() hash.Hash
+	items     []batchItem
+}
+
+type batchItem struct {
+	name string
+	r    io.Reader
+}
+
+// BatchResult is the outcome of hashing one item of a Batch.
+type BatchResult struct {
+	// Name is the name the item was added with.
+	Name string
+	// Sums holds the digests of the item, or nil if Err is not nil.
+	Sums Results
+	// BytesRead is the number of bytes read from the item.
+	BytesRead int64
+	// Err is the error returned by the item's reader, other than io.EOF, or
+	// the context's error if the item was not fully hashed before the context
+	// was done.
+	Err error
+}
+
+// NewBatch returns an empty Batch that hashes every item with a hash.Hash from
+// each constructor in factories. If factories is nil, StdCryptoHashFactories
+// is used.
+func NewBatch(factories map[string]func() hash.Hash) *Batch {
+	if factories == nil {
+		factories = StdCryptoHashFactories()
+	}
+	return &Batch{factories: factories}
+}
+
+// Add adds r to b under name. Names don't have to be unique; results are
+// reported in the order items were added. r is read until io.EOF but is not
+// closed.
+func (b *Batch) Add(name string, r io.Reader) {
+	b.items = append(b.items, batchItem{name, r})
+}
+
+// Len returns the number of items added to b.
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+// Run hashes every item of b using at most concurrency goroutines, or one if
+// concurrency is less than one. It returns one BatchResult per item, in the
+// order the items were added, along with ctx.Err() if ctx was done before all
+// items were hashed. Failures of individual items are reported in their
+// BatchResult and don't stop the others.
+func (b *Batch) Run(ctx context.Context, concurrency int) ([]BatchResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(b.items) {
+		concurrency = len(b.items)
+	}
+
+	results := make([]BatchResult, len(b.items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			h := NewHashReaderFromFactories(nil, b.factories)
+			buf := getBuffer(copyBufSize(defaultBufSize))
+			defer putBuffer(buf)
+			for i := range next {
+				results[i] = b.hash(ctx, h, *buf, b.items[i])
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := range b.items {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			for ; i < len(b.items); i++ {
+				results[i] = BatchResult{Name: b.items[i].name, Err: err}
+			}
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	return results, err
+}
+
+// hash hashes item with h, reading through buf.
+func (b *Batch) hash(ctx context.Context, h *HashReader, buf []byte, item batchItem) BatchResult {
+	h.Reset(ctxReader{ctx, item.r})
+	res := BatchResult{Name: item.name}
+	_, err := io.CopyBuffer(io.Discard, struct{ io.Reader }{h}, buf)
+	res.BytesRead = h.BytesRead()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Sums = h.Results()
+	return res
+}
+
+// ctxReader fails reads once ctx is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,70 @@
+package hashio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("os.Open(%q): %v", dataFile, err)
+	}
+	defer f.Close()
+
+	boom := errors.New("boom")
+	b := NewBatch(nil)
+	b.Add("file", f)
+	for i := 0; i < 20; i++ {
+		b.Add(fmt.Sprint(i), strings.NewReader(strings.Repeat("x", i)))
+	}
+	b.Add("broken", &errReader{[]byte("partial"), boom})
+	if b.Len() != 22 {
+		t.Fatalf("Batch.Len() got: %d, wanted 22", b.Len())
+	}
+
+	results, err := b.Run(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("Batch.Run(): %v", err)
+	}
+	if len(results) != b.Len() {
+		t.Fatalf("Batch.Run() got %d results, wanted %d", len(results), b.Len())
+	}
+	if got := results[0].Sums.Hex()[SHA256]; got != dataFileSHA256 {
+		t.Errorf("Batch.Run() result for %q got sha256: %q, wanted %q", results[0].Name, got, dataFileSHA256)
+	}
+	for i, res := range results[1:21] {
+		want := sha256.Sum256(bytes.Repeat([]byte("x"), i))
+		if res.Name != fmt.Sprint(i) || res.Err != nil || !bytes.Equal(res.Sums[SHA256], want[:]) || res.BytesRead != int64(i) {
+			t.Errorf("Batch.Run() result %d got: %+v, wanted sha256 %x of %d bytes", i+1, res, want, i)
+		}
+	}
+	if res := results[21]; res.Err != boom || res.Sums != nil {
+		t.Errorf("Batch.Run() result for %q got: %+v, wanted error %v and no sums", res.Name, res, boom)
+	}
+}
+
+func TestBatchCanceled(t *testing.T) {
+	b := NewBatch(StdCryptoHashFactories())
+	for i := 0; i < 10; i++ {
+		b.Add(fmt.Sprint(i), strings.NewReader("data"))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := b.Run(ctx, 2)
+	if err != context.Canceled {
+		t.Errorf("Batch.Run() with canceled context got error: %v, wanted %v", err, context.Canceled)
+	}
+	for _, res := range results {
+		if res.Err != context.Canceled {
+			t.Errorf("Batch.Run() result for %q got error: %v, wanted %v", res.Name, res.Err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+// Package blake2 implements the BLAKE2b and BLAKE2s hash functions of RFC 7693
+// in pure Go, with any digest size they allow and the optional key that turns
+// them into MACs.
+//
+// BLAKE2b works on 64-bit words and produces digests of up to 64 bytes;
+// BLAKE2s works on 32-bit words, is faster on 32-bit platforms and produces
+// digests of up to 32 bytes. Both are at least as fast as MD5 on modern
+// hardware while being as secure as SHA-3.
+package blake2
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// SizeB is the largest BLAKE2b digest size, and the largest key size.
+	SizeB = 64
+	// BlockSizeB is the block size of BLAKE2b in bytes.
+	BlockSizeB = 128
+	// SizeS is the largest BLAKE2s digest size, and the largest key size.
+	SizeS = 32
+	// BlockSizeS is the block size of BLAKE2s in bytes.
+	BlockSizeS = 64
+)
+
+var (
+	// ErrSize is returned for a digest size of zero or larger than the
+	// maximum of the function.
+	ErrSize = errors.New("blake2: invalid digest size")
+	// ErrKeySize is returned for a key longer than the maximum digest size of
+	// the function.
+	ErrKeySize = errors.New("blake2: key too long")
+
+	errInvalidState = errors.New("blake2: invalid hash state")
+)
+
+// sigma holds the message word permutations of the rounds.
+var sigma = [10][16]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// ivB is the BLAKE2b initialization vector, the same as SHA-512's.
+var ivB = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// ivS is the BLAKE2s initialization vector, the same as SHA-256's.
+var ivS = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// digestB is a BLAKE2b hash.
+type digestB struct {
+	h    [8]uint64
+	t    [2]uint64 // bytes compressed so far
+	buf  [BlockSizeB]byte
+	nx   int // bytes in buf
+	size int
+	key  [BlockSizeB]byte // the key padded to a block, if klen > 0
+	klen int
+}
+
+// NewB returns a BLAKE2b hash producing size byte digests, keyed with key if
+// it isn't empty. size must be between 1 and SizeB, and key at most SizeB
+// bytes long.
+func NewB(size int, key []byte) (hash.Hash, error) {
+	if size < 1 || size > SizeB {
+		return nil, ErrSize
+	}
+	if len(key) > SizeB {
+		return nil, ErrKeySize
+	}
+	d := &digestB{size: size, klen: len(key)}
+	copy(d.key[:], key)
+	d.Reset()
+	return d, nil
+}
+
+// NewB256 returns an unkeyed BLAKE2b-256 hash.
+func NewB256() hash.Hash {
+	d, _ := NewB(32, nil)
+	return d
+}
+
+// NewB512 returns an unkeyed BLAKE2b-512 hash.
+func NewB512() hash.Hash {
+	d, _ := NewB(64, nil)
+	return d
+}
+
+// SumB256 returns the BLAKE2b-256 digest of data.
+func SumB256(data []byte) [32]byte {
+	var sum [32]byte
+	d := NewB256()
+	d.Write(data)
+	d.Sum(sum[:0])
+	return sum
+}
+
+// SumB512 returns the BLAKE2b-512 digest of data.
+func SumB512(data []byte) [64]byte {
+	var sum [64]byte
+	d := NewB512()
+	d.Write(data)
+	d.Sum(sum[:0])
+	return sum
+}
+
+func (d *digestB) Reset() {
+	d.h = ivB
+	d.h[0] ^= 0x01010000 ^ uint64(d.klen)<<8 ^ uint64(d.size)
+	d.t = [2]uint64{}
+	d.nx = 0
+	if d.klen > 0 {
+		// The key, padded with zeros, is the first block.
+		d.buf = d.key
+		d.nx = BlockSizeB
+	}
+}
+
+func (d *digestB) Size() int      { return d.size }
+func (d *digestB) BlockSize() int { return BlockSizeB }
+
+func (d *digestB) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// The last block is compressed differently, so a full buffer is only
+		// compressed once more data follows it.
+		if d.nx == BlockSizeB {
+			d.compress(&d.buf, false)
+			d.nx = 0
+		}
+		k := copy(d.buf[d.nx:], p)
+		d.nx += k
+		p = p[k:]
+	}
+	return n, nil
+}
+
+func (d *digestB) Sum(b []byte) []byte {
+	c := *d
+	clear(c.buf[c.nx:])
+	c.compress(&c.buf, true)
+	var out [SizeB]byte
+	for i, v := range c.h {
+		binary.LittleEndian.PutUint64(out[8*i:], v)
+	}
+	return append(b, out[:d.size]...)
+}
+
+// compress compresses the nx bytes of block into the state.
+func (d *digestB) compress(block *[BlockSizeB]byte, last bool) {
+	d.t[0] += uint64(d.nx)
+	if d.t[0] < uint64(d.nx) {
+		d.t[1]++
+	}
+
+	var m [16]uint64
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(block[8*i:])
+	}
+	var v [16]uint64
+	copy(v[:8], d.h[:])
+	copy(v[8:], ivB[:])
+	v[12] ^= d.t[0]
+	v[13] ^= d.t[1]
+	if last {
+		v[14] = ^v[14]
+	}
+
+	g := func(a, b, c, e int, x, y uint64) {
+		v[a] += v[b] + x
+		v[e] = bits.RotateLeft64(v[e]^v[a], -32)
+		v[c] += v[e]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+		v[a] += v[b] + y
+		v[e] = bits.RotateLeft64(v[e]^v[a], -16)
+		v[c] += v[e]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+	}
+	for r := 0; r < 12; r++ {
+		s := &sigma[r%10]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range d.h {
+		d.h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+const magicB = "blake2b\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler. The state includes the
+// key while its block hasn't been compressed.
+func (d *digestB) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magicB)+2+8*8+16+BlockSizeB+1)
+	b = append(b, magicB...)
+	b = append(b, byte(d.size), byte(d.klen))
+	for _, v := range d.h {
+		b = binary.LittleEndian.AppendUint64(b, v)
+	}
+	b = binary.LittleEndian.AppendUint64(b, d.t[0])
+	b = binary.LittleEndian.AppendUint64(b, d.t[1])
+	b = append(b, d.buf[:]...)
+	return append(b, byte(d.nx)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The state must have
+// been saved from a hash with the same digest and key sizes.
+func (d *digestB) UnmarshalBinary(b []byte) error {
+	if len(b) != len(magicB)+2+8*8+16+BlockSizeB+1 || string(b[:len(magicB)]) != magicB {
+		return errInvalidState
+	}
+	b = b[len(magicB):]
+	if int(b[0]) != d.size || int(b[1]) != d.klen || int(b[len(b)-1]) > BlockSizeB {
+		return errInvalidState
+	}
+	b = b[2:]
+	for i := range d.h {
+		d.h[i], b = binary.LittleEndian.Uint64(b), b[8:]
+	}
+	d.t[0], d.t[1] = binary.LittleEndian.Uint64(b), binary.LittleEndian.Uint64(b[8:])
+	b = b[16+copy(d.buf[:], b[16:]):]
+	d.nx = int(b[0])
+	return nil
+}
+
+// Clone implements hash.Cloner.
+func (d *digestB) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
+
+// digestS is a BLAKE2s hash.
+type digestS struct {
+	h    [8]uint32
+	t    [2]uint32 // bytes compressed so far
+	buf  [BlockSizeS]byte
+	nx   int // bytes in buf
+	size int
+	key  [BlockSizeS]byte // the key padded to a block, if klen > 0
+	klen int
+}
+
+// NewS returns a BLAKE2s hash producing size byte digests, keyed with key if
+// it isn't empty. size must be between 1 and SizeS, and key at most SizeS
+// bytes long.
+func NewS(size int, key []byte) (hash.Hash, error) {
+	if size < 1 || size > SizeS {
+		return nil, ErrSize
+	}
+	if len(key) > SizeS {
+		return nil, ErrKeySize
+	}
+	d := &digestS{size: size, klen: len(key)}
+	copy(d.key[:], key)
+	d.Reset()
+	return d, nil
+}
+
+// NewS256 returns an unkeyed BLAKE2s-256 hash.
+func NewS256() hash.Hash {
+	d, _ := NewS(32, nil)
+	return d
+}
+
+// SumS256 returns the BLAKE2s-256 digest of data.
+func SumS256(data []byte) [32]byte {
+	var sum [32]byte
+	d := NewS256()
+	d.Write(data)
+	d.Sum(sum[:0])
+	return sum
+}
+
+func (d *digestS) Reset() {
+	d.h = ivS
+	d.h[0] ^= 0x01010000 ^ uint32(d.klen)<<8 ^ uint32(d.size)
+	d.t = [2]uint32{}
+	d.nx = 0
+	if d.klen > 0 {
+		d.buf = d.key
+		d.nx = BlockSizeS
+	}
+}
+
+func (d *digestS) Size() int      { return d.size }
+func (d *digestS) BlockSize() int { return BlockSizeS }
+
+func (d *digestS) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if d.nx == BlockSizeS {
+			d.compress(&d.buf, false)
+			d.nx = 0
+		}
+		k := copy(d.buf[d.nx:], p)
+		d.nx += k
+		p = p[k:]
+	}
+	return n, nil
+}
+
+func (d *digestS) Sum(b []byte) []byte {
+	c := *d
+	clear(c.buf[c.nx:])
+	c.compress(&c.buf, true)
+	var out [SizeS]byte
+	for i, v := range c.h {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+	return append(b, out[:d.size]...)
+}
+
+// compress compresses the nx bytes of block into the state.
+func (d *digestS) compress(block *[BlockSizeS]byte, last bool) {
+	d.t[0] += uint32(d.nx)
+	if d.t[0] < uint32(d.nx) {
+		d.t[1]++
+	}
+
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	var v [16]uint32
+	copy(v[:8], d.h[:])
+	copy(v[8:], ivS[:])
+	v[12] ^= d.t[0]
+	v[13] ^= d.t[1]
+	if last {
+		v[14] = ^v[14]
+	}
+
+	g := func(a, b, c, e int, x, y uint32) {
+		v[a] += v[b] + x
+		v[e] = bits.RotateLeft32(v[e]^v[a], -16)
+		v[c] += v[e]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+		v[a] += v[b] + y
+		v[e] = bits.RotateLeft32(v[e]^v[a], -8)
+		v[c] += v[e]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+	}
+	for r := 0; r < 10; r++ {
+		s := &sigma[r]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range d.h {
+		d.h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+const magicS = "blake2s\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler. The state includes the
+// key while its block hasn't been compressed.
+func (d *digestS) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magicS)+2+8*4+8+BlockSizeS+1)
+	b = append(b, magicS...)
+	b = append(b, byte(d.size), byte(d.klen))
+	for _, v := range d.h {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	b = binary.LittleEndian.AppendUint32(b, d.t[0])
+	b = binary.LittleEndian.AppendUint32(b, d.t[1])
+	b = append(b, d.buf[:]...)
+	return append(b, byte(d.nx)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The state must have
+// been saved from a hash with the same digest and key sizes.
+func (d *digestS) UnmarshalBinary(b []byte) error {
+	if len(b) != len(magicS)+2+8*4+8+BlockSizeS+1 || string(b[:len(magicS)]) != magicS {
+		return errInvalidState
+	}
+	b = b[len(magicS):]
+	if int(b[0]) != d.size || int(b[1]) != d.klen || int(b[len(b)-1]) > BlockSizeS {
+		return errInvalidState
+	}
+	b = b[2:]
+	for i := range d.h {
+		d.h[i], b = binary.LittleEndian.Uint32(b), b[4:]
+	}
+	d.t[0], d.t[1] = binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint32(b[4:])
+	b = b[8+copy(d.buf[:], b[8:]):]
+	d.nx = int(b[0])
+	return nil
+}
+
+// Clone implements hash.Cloner.
+func (d *digestS) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
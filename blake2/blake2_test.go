@@ -0,0 +1,142 @@
+package blake2
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func testInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func testKey(n int) []byte {
+	key := make([]byte, n)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+// Vectors computed with the reference implementation (Python's hashlib).
+var testVectors = []struct {
+	desc string
+	new  func() (hash.Hash, error)
+	n    int
+	want string
+}{
+	{"BLAKE2b-512", func() (hash.Hash, error) { return NewB512(), nil }, 0, "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+	{"BLAKE2b-512", func() (hash.Hash, error) { return NewB512(), nil }, 128, "2319e3789c47e2daa5fe807f61bec2a1a6537fa03f19ff32e87eecbfd64b7e0e8ccff439ac333b040f19b0c4ddd11a61e24ac1fe0f10a039806c5dcc0da3d115"},
+	{"BLAKE2b-512", func() (hash.Hash, error) { return NewB512(), nil }, 1000, "c11e1c0340bd7e5a1b275f1230c962fad215ecb1391486e74e31b960a2f2996381a5fad092da06841d5f26e38f6ecfeaf441acbcd1c2de61aef121e7927175f5"},
+	{"BLAKE2b-256", func() (hash.Hash, error) { return NewB256(), nil }, 3, "3d8c3d594928271f44aad7a04b177154806867bcf918e1549c0bc16f9da2b09b"},
+	{"BLAKE2b-256", func() (hash.Hash, error) { return NewB256(), nil }, 129, "f7f3c46ba2564ff4c4c162da1f5b605f9f1c4aa6a20652a9f9a337c1a2f5b9c9"},
+	{"keyed BLAKE2b-160", func() (hash.Hash, error) { return NewB(20, testKey(64)) }, 0, "15efac5a414effae1c5bc667974437c08cb07465"},
+	{"keyed BLAKE2b-160", func() (hash.Hash, error) { return NewB(20, testKey(64)) }, 256, "b6f19ca73a0a3810044d25582c33f619db9791f1"},
+	{"BLAKE2s-256", func() (hash.Hash, error) { return NewS256(), nil }, 0, "69217a3079908094e11121d042354a7c1f55b6482ca1a51e1b250dfd1ed0eef9"},
+	{"BLAKE2s-256", func() (hash.Hash, error) { return NewS256(), nil }, 127, "f18417b39d617ab1c18fdf91ebd0fc6d5516bb34cf39364037bce81fa04cecb1"},
+	{"BLAKE2s-256", func() (hash.Hash, error) { return NewS256(), nil }, 1000, "1c067a5e746fb0f6734efac9a8cdb0e11061f0077f255184365c690115392501"},
+	{"keyed BLAKE2s-128", func() (hash.Hash, error) { return NewS(16, testKey(32)) }, 3, "1e2a79436a7796a3e9826bfedf07659f"},
+	{"keyed BLAKE2s-128", func() (hash.Hash, error) { return NewS(16, testKey(32)) }, 255, "463efacd48ca749d4df4bc9c5938804d"},
+}
+
+func TestVectors(t *testing.T) {
+	for _, tc := range testVectors {
+		h, err := tc.new()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.desc, err)
+		}
+		data := testInput(tc.n)
+		// Write in uneven pieces to cross block boundaries.
+		for p := data; len(p) > 0; {
+			k := min(len(p), 37)
+			h.Write(p[:k])
+			p = p[k:]
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("%s of %d bytes got: %q, wanted %q", tc.desc, tc.n, got, tc.want)
+		}
+		if h.Size() != len(tc.want)/2 {
+			t.Errorf("%s Size() got: %d, wanted %d", tc.desc, h.Size(), len(tc.want)/2)
+		}
+
+		// Reset must keep the key.
+		h.Reset()
+		h.Write(data)
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("%s of %d bytes after Reset got: %q, wanted %q", tc.desc, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestSums(t *testing.T) {
+	data := testInput(1000)
+	if got := SumB512(data); hex.EncodeToString(got[:]) != testVectors[2].want {
+		t.Errorf("SumB512() got: %x, wanted %s", got, testVectors[2].want)
+	}
+	if got := SumS256(data); hex.EncodeToString(got[:]) != testVectors[9].want {
+		t.Errorf("SumS256() got: %x, wanted %s", got, testVectors[9].want)
+	}
+	if got, want := SumB256(testInput(3)), testVectors[3].want; hex.EncodeToString(got[:]) != want {
+		t.Errorf("SumB256() got: %x, wanted %s", got, want)
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want error
+	}{
+		{"NewB(0)", second(NewB(0, nil)), ErrSize},
+		{"NewB(65)", second(NewB(65, nil)), ErrSize},
+		{"NewB(64, 65 byte key)", second(NewB(64, testKey(65))), ErrKeySize},
+		{"NewS(33)", second(NewS(33, nil)), ErrSize},
+		{"NewS(32, 33 byte key)", second(NewS(32, testKey(33))), ErrKeySize},
+	} {
+		if tc.err != tc.want {
+			t.Errorf("%s got error: %v, wanted %v", tc.desc, tc.err, tc.want)
+		}
+	}
+}
+
+func second(_ hash.Hash, err error) error { return err }
+
+func TestMarshalBinary(t *testing.T) {
+	for _, tc := range testVectors {
+		data := testInput(tc.n)
+		for _, split := range []int{0, min(1, tc.n), tc.n / 2, tc.n} {
+			h, _ := tc.new()
+			h.Write(data[:split])
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s MarshalBinary(): %v", tc.desc, err)
+			}
+			resumed, _ := tc.new()
+			if err := resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatalf("%s UnmarshalBinary(): %v", tc.desc, err)
+			}
+			resumed.Write(data[split:])
+			if got := hex.EncodeToString(resumed.Sum(nil)); got != tc.want {
+				t.Errorf("%s resumed at %d of %d bytes got: %q, wanted %q", tc.desc, split, tc.n, got, tc.want)
+			}
+
+			clone, _ := h.(hash.Cloner).Clone()
+			clone.Write(data[split:])
+			if !bytes.Equal(clone.Sum(nil), resumed.Sum(nil)) {
+				t.Errorf("%s Clone() at %d gave a different digest", tc.desc, split)
+			}
+		}
+	}
+
+	// A state only fits hashes with the same parameters.
+	state, _ := NewB512().(encoding.BinaryMarshaler).MarshalBinary()
+	if err := NewB256().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("BLAKE2b-256 UnmarshalBinary(BLAKE2b-512 state) got no error")
+	}
+}
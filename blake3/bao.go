@@ -0,0 +1,220 @@
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Bao is a verified streaming format for BLAKE3. Its outboard encoding stores
+// the interior nodes of the BLAKE3 tree of some content separately from the
+// content itself: an 8 byte little-endian content length followed by every
+// parent node (the 32 byte chaining values of its two children) in pre-order.
+// With the outboard encoding and the BLAKE3 digest of the content, a reader
+// can verify every 1 KiB chunk as it arrives instead of only at the end.
+
+// ErrCorrupt is returned when content or an outboard encoding doesn't match
+// the expected digest.
+var ErrCorrupt = errors.New("blake3: content does not match the expected digest")
+
+// baoHeaderSize is the size of the length header of an outboard encoding.
+const baoHeaderSize = 8
+
+// Outboard reads r until io.EOF and returns the Bao outboard encoding of the
+// data along with its BLAKE3 digest. It keeps the 32 byte chaining value of
+// every 1 KiB chunk in memory until the encoding is built.
+func Outboard(r io.Reader) (outboard []byte, sum [Size]byte, err error) {
+	key := iv
+	var cvs [][8]uint32
+	var n int64
+	buf := make([]byte, ChunkSize)
+	var last []byte // the last chunk read, kept in case it is the only one
+	for {
+		k, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, sum, err
+		}
+		c := newChunkState(&key, uint64(len(cvs)))
+		c.update(buf[:k])
+		o := c.output()
+		cvs = append(cvs, o.chainingValue())
+		n += int64(k)
+		last = buf[:k]
+		if k < ChunkSize {
+			break
+		}
+	}
+
+	outboard = binary.LittleEndian.AppendUint64(make([]byte, 0, baoHeaderSize+64*max(len(cvs)-1, 0)), uint64(n))
+	var root output
+	if n <= ChunkSize {
+		c := newChunkState(&key, 0)
+		c.update(last)
+		root = c.output()
+	} else {
+		var l, r [8]uint32
+		outboard, l, r = baoParents(outboard, cvs, n)
+		root = parentOutput(l, r, &key)
+	}
+	root.rootBytes(sum[:0], Size)
+	return outboard, sum, nil
+}
+
+// baoParents appends the parent nodes of the tree over the n > ChunkSize bytes
+// whose chunk chaining values are cvs to b in pre-order, and returns b along
+// with the chaining values of the two children of the tree's top node.
+func baoParents(b []byte, cvs [][8]uint32, n int64) ([]byte, [8]uint32, [8]uint32) {
+	at := len(b)
+	b = append(b, make([]byte, 64)...)
+
+	l := leftLen(n)
+	lc := int(l / ChunkSize)
+	var left, right [8]uint32
+	var ll, lr, rl, rr [8]uint32
+	key := iv
+	if l > ChunkSize {
+		b, ll, lr = baoParents(b, cvs[:lc], l)
+		left = parentCV(ll, lr, &key)
+	} else {
+		left = cvs[0]
+	}
+	if n-l > ChunkSize {
+		b, rl, rr = baoParents(b, cvs[lc:], n-l)
+		right = parentCV(rl, rr, &key)
+	} else {
+		right = cvs[lc]
+	}
+	putWords(b[at:], left)
+	putWords(b[at+32:], right)
+	return b, left, right
+}
+
+func putWords(b []byte, w [8]uint32) {
+	for i, v := range w {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+}
+
+func getWords(b []byte) (w [8]uint32) {
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return w
+}
+
+// baoNode is a subtree still to be verified by a BaoReader.
+type baoNode struct {
+	off, n int64
+	cv     [8]uint32 // expected chaining value, unused for the root
+	root   bool
+}
+
+// BaoReader reads content and verifies it against a BLAKE3 digest one 1 KiB
+// chunk at a time, using the content's Bao outboard encoding. Read only
+// returns data that has been verified; the first corrupted chunk, or
+// corrupted outboard node, makes Read fail with ErrCorrupt.
+type BaoReader struct {
+	content  io.Reader
+	outboard io.Reader
+	sum      [Size]byte
+	stack    []baoNode
+	started  bool
+	chunk    []byte // verified data not yet returned
+	buf      [ChunkSize]byte
+	err      error
+}
+
+// NewBaoReader returns a BaoReader verifying the data read from content
+// against sum, its BLAKE3 digest, with the outboard encoding read from
+// outboard as it is needed.
+func NewBaoReader(content, outboard io.Reader, sum [Size]byte) *BaoReader {
+	return &BaoReader{content: content, outboard: outboard, sum: sum}
+}
+
+// Read reads verified content into p.
+func (b *BaoReader) Read(p []byte) (int, error) {
+	for len(b.chunk) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		b.err = b.next()
+	}
+	n := copy(p, b.chunk)
+	b.chunk = b.chunk[n:]
+	return n, nil
+}
+
+// next verifies the next chunk into b.chunk, or returns io.EOF after the last
+// one.
+func (b *BaoReader) next() error {
+	if !b.started {
+		b.started = true
+		var hdr [baoHeaderSize]byte
+		if _, err := io.ReadFull(b.outboard, hdr[:]); err != nil {
+			return outboardErr(err)
+		}
+		n := binary.LittleEndian.Uint64(hdr[:])
+		if n > 1<<62 {
+			return ErrCorrupt
+		}
+		b.stack = append(b.stack, baoNode{n: int64(n), root: true})
+	}
+
+	key := iv
+	for len(b.stack) > 0 {
+		nd := b.stack[len(b.stack)-1]
+		b.stack = b.stack[:len(b.stack)-1]
+
+		if nd.n <= ChunkSize {
+			data := b.buf[:nd.n]
+			if _, err := io.ReadFull(b.content, data); err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+			c := newChunkState(&key, uint64(nd.off/ChunkSize))
+			c.update(data)
+			if !b.matches(c.output(), nd) {
+				return ErrCorrupt
+			}
+			b.chunk = data
+			return nil
+		}
+
+		var node [64]byte
+		if _, err := io.ReadFull(b.outboard, node[:]); err != nil {
+			return outboardErr(err)
+		}
+		left, right := getWords(node[:32]), getWords(node[32:])
+		if !b.matches(parentOutput(left, right, &key), nd) {
+			return ErrCorrupt
+		}
+		l := leftLen(nd.n)
+		b.stack = append(b.stack,
+			baoNode{off: nd.off + l, n: nd.n - l, cv: right},
+			baoNode{off: nd.off, n: l, cv: left})
+	}
+	return io.EOF
+}
+
+// matches reports whether o is the node expected by nd.
+func (b *BaoReader) matches(o output, nd baoNode) bool {
+	if nd.root {
+		var sum [Size]byte
+		o.rootBytes(sum[:0], Size)
+		return sum == b.sum
+	}
+	return o.chainingValue() == nd.cv
+}
+
+// outboardErr reports a truncated outboard encoding as corruption.
+func outboardErr(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrCorrupt
+	}
+	return err
+}
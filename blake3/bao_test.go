@@ -0,0 +1,73 @@
+package blake3
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestOutboard(t *testing.T) {
+	for _, tc := range testVectors {
+		data := testInput(tc.n)
+		ob, sum, err := Outboard(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Outboard(%d bytes): %v", tc.n, err)
+		}
+		if want := Sum256(data); sum != want {
+			t.Errorf("Outboard(%d bytes) sum got: %x, wanted %x", tc.n, sum, want)
+		}
+		chunks := max((tc.n+ChunkSize-1)/ChunkSize, 1)
+		if got, want := len(ob), 8+64*(chunks-1); got != want {
+			t.Errorf("len(Outboard(%d bytes)) got: %d, wanted %d", tc.n, got, want)
+		}
+
+		got, err := io.ReadAll(NewBaoReader(bytes.NewReader(data), bytes.NewReader(ob), sum))
+		if err != nil {
+			t.Fatalf("BaoReader(%d bytes): %v", tc.n, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("BaoReader(%d bytes) returned different data", tc.n)
+		}
+	}
+}
+
+func TestBaoReaderCorruption(t *testing.T) {
+	const n = 10*ChunkSize + 100
+	data := testInput(n)
+	ob, sum, err := Outboard(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Outboard(): %v", err)
+	}
+
+	// Corrupting chunk 6 must fail the read there, after the first six
+	// chunks have been returned.
+	bad := bytes.Clone(data)
+	bad[6*ChunkSize+3] ^= 1
+	got, err := io.ReadAll(NewBaoReader(bytes.NewReader(bad), bytes.NewReader(ob), sum))
+	if err != ErrCorrupt {
+		t.Errorf("BaoReader(corrupt chunk) got error: %v, wanted %v", err, ErrCorrupt)
+	}
+	if len(got) != 6*ChunkSize || !bytes.Equal(got, data[:len(got)]) {
+		t.Errorf("BaoReader(corrupt chunk) returned %d bytes, wanted the %d verified bytes", len(got), 6*ChunkSize)
+	}
+
+	// Any flipped bit in the outboard encoding must be caught.
+	for i := 0; i < len(ob); i += 7 {
+		badOb := bytes.Clone(ob)
+		badOb[i] ^= 0x80
+		if _, err := io.ReadAll(NewBaoReader(bytes.NewReader(data), bytes.NewReader(badOb), sum)); err == nil {
+			t.Errorf("BaoReader(outboard corrupted at %d) got no error", i)
+		}
+	}
+
+	if _, err := io.ReadAll(NewBaoReader(bytes.NewReader(data), bytes.NewReader(ob[:len(ob)-1]), sum)); err != ErrCorrupt {
+		t.Errorf("BaoReader(truncated outboard) got error: %v, wanted %v", err, ErrCorrupt)
+	}
+	if _, err := io.ReadAll(NewBaoReader(bytes.NewReader(data[:n-1]), bytes.NewReader(ob), sum)); err != io.ErrUnexpectedEOF {
+		t.Errorf("BaoReader(truncated content) got error: %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+	other := Sum256(nil)
+	if _, err := io.ReadAll(NewBaoReader(bytes.NewReader(data), bytes.NewReader(ob), other)); err != ErrCorrupt {
+		t.Errorf("BaoReader(wrong sum) got error: %v, wanted %v", err, ErrCorrupt)
+	}
+}
@@ -0,0 +1,401 @@
+// Package blake3 implements the BLAKE3 hash function in pure Go, along with a
+// multi-threaded mode for large inputs that support random access.
+//
+// BLAKE3 hashes its input as a binary tree of 1 KiB chunks, so independent
+// subtrees can be hashed on separate cores. HashReaderAt does exactly that;
+// New returns a streaming hash.Hash that works on any io.Writer source.
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// Size is the size of a BLAKE3 digest in bytes.
+	Size = 32
+	// BlockSize is the block size of BLAKE3 in bytes.
+	BlockSize = 64
+
+	// ChunkSize is the size of the chunks at the leaves of the BLAKE3 tree.
+	ChunkSize = 1024
+)
+
+// Domain separation flags.
+const (
+	flagChunkStart = 1 << iota
+	flagChunkEnd
+	flagParent
+	flagRoot
+)
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+func g(s *[16]uint32, a, b, c, d int, mx, my uint32) {
+	s[a] += s[b] + mx
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] += s[b] + my
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
+
+func round(s *[16]uint32, m *[16]uint32) {
+	g(s, 0, 4, 8, 12, m[0], m[1])
+	g(s, 1, 5, 9, 13, m[2], m[3])
+	g(s, 2, 6, 10, 14, m[4], m[5])
+	g(s, 3, 7, 11, 15, m[6], m[7])
+	g(s, 0, 5, 10, 15, m[8], m[9])
+	g(s, 1, 6, 11, 12, m[10], m[11])
+	g(s, 2, 7, 8, 13, m[12], m[13])
+	g(s, 3, 4, 9, 14, m[14], m[15])
+}
+
+// compress is the BLAKE3 compression function.
+func compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	m := *block
+	for r := 0; r < 7; r++ {
+		round(&s, &m)
+		if r < 6 {
+			var p [16]uint32
+			for i, j := range msgPermutation {
+				p[i] = m[j]
+			}
+			m = p
+		}
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func blockWords(b *[BlockSize]byte) [16]uint32 {
+	var w [16]uint32
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return w
+}
+
+func first8(s [16]uint32) [8]uint32 {
+	return [8]uint32{s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7]}
+}
+
+// output is a node of the tree that has not been compressed yet. It can
+// become either a chaining value or, at the root, the digest.
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o *output) chainingValue() [8]uint32 {
+	return first8(compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags))
+}
+
+// rootBytes appends n bytes of root output to b.
+func (o *output) rootBytes(b []byte, n int) []byte {
+	for counter := uint64(0); n > 0; counter++ {
+		block := o.rootBlock(counter)
+		k := min(n, BlockSize)
+		b = append(b, block[:k]...)
+		n -= k
+	}
+	return b
+}
+
+// rootBlock returns the block of root output at counter.
+func (o *output) rootBlock(counter uint64) [BlockSize]byte {
+	var block [BlockSize]byte
+	words := compress(&o.cv, &o.block, counter, o.blockLen, o.flags|flagRoot)
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(block[4*i:], w)
+	}
+	return block
+}
+
+func parentOutput(left, right [8]uint32, key *[8]uint32) output {
+	var block [16]uint32
+	copy(block[:8], left[:])
+	copy(block[8:], right[:])
+	return output{cv: *key, block: block, blockLen: BlockSize, flags: flagParent}
+}
+
+func parentCV(left, right [8]uint32, key *[8]uint32) [8]uint32 {
+	o := parentOutput(left, right, key)
+	return o.chainingValue()
+}
+
+// chunkState hashes the up to ChunkSize bytes of a single chunk.
+type chunkState struct {
+	cv       [8]uint32
+	counter  uint64
+	block    [BlockSize]byte
+	blockLen int
+	blocks   int // blocks compressed so far
+}
+
+func newChunkState(key *[8]uint32, counter uint64) chunkState {
+	return chunkState{cv: *key, counter: counter}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.blocks + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocks == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(p []byte) {
+	for len(p) > 0 {
+		// The last block of a chunk is compressed by output, so a full block
+		// is only compressed once more input arrives.
+		if c.blockLen == BlockSize {
+			w := blockWords(&c.block)
+			c.cv = first8(compress(&c.cv, &w, c.counter, BlockSize, c.startFlag()))
+			c.blocks++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		cv:       c.cv,
+		block:    blockWords(&c.block),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.startFlag() | flagChunkEnd,
+	}
+}
+
+// Hasher is a streaming BLAKE3 hash. It implements hash.Hash. The zero value
+// is not usable; use New.
+type Hasher struct {
+	key   [8]uint32
+	chunk chunkState
+	base  uint64 // counter of the first chunk, non-zero when hashing a subtree
+	stack [54][8]uint32
+	depth int
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+// New returns a new BLAKE3 hash.Hash computing a 32 byte digest.
+func New() *Hasher {
+	return newHasher(0)
+}
+
+func newHasher(base uint64) *Hasher {
+	h := &Hasher{key: iv, base: base}
+	h.chunk = newChunkState(&h.key, base)
+	return h
+}
+
+// Sum256 returns the BLAKE3 digest of data.
+func Sum256(data []byte) [Size]byte {
+	h := New()
+	h.Write(data)
+	var sum [Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// Write adds p to the running hash. It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if h.chunk.len() == ChunkSize {
+			cv := h.chunk.output()
+			total := h.chunk.counter - h.base + 1
+			h.push(cv.chainingValue(), total)
+			h.chunk = newChunkState(&h.key, h.chunk.counter+1)
+		}
+		k := min(ChunkSize-h.chunk.len(), len(p))
+		h.chunk.update(p[:k])
+		p = p[k:]
+	}
+	return n, nil
+}
+
+// push adds the chaining value of a completed chunk to the stack, merging
+// every subtree it completes. total is the number of chunks hashed so far.
+func (h *Hasher) push(cv [8]uint32, total uint64) {
+	for total&1 == 0 {
+		h.depth--
+		cv = parentCV(h.stack[h.depth], cv, &h.key)
+		total >>= 1
+	}
+	h.stack[h.depth] = cv
+	h.depth++
+}
+
+// finalOutput returns the root node of the data written so far, without
+// changing the state of h.
+func (h *Hasher) finalOutput() output {
+	o := h.chunk.output()
+	for i := h.depth - 1; i >= 0; i-- {
+		o = parentOutput(h.stack[i], o.chainingValue(), &h.key)
+	}
+	return o
+}
+
+// Sum appends the current digest to b and returns the resulting slice. It
+// does not change the underlying hash state.
+func (h *Hasher) Sum(b []byte) []byte {
+	o := h.finalOutput()
+	return o.rootBytes(b, Size)
+}
+
+// SumN appends n bytes of output for the data written so far to b and returns
+// the resulting slice. It does not change the underlying hash state. Shorter
+// outputs are prefixes of longer ones; Sum appends the first Size bytes. A
+// negative n appends nothing.
+func (h *Hasher) SumN(b []byte, n int) []byte {
+	o := h.finalOutput()
+	return o.rootBytes(b, n)
+}
+
+// XOF returns an OutputReader streaming the output for the data written so
+// far, which extends the digest to any length. It does not change the
+// underlying hash state.
+func (h *Hasher) XOF() *OutputReader {
+	return &OutputReader{o: h.finalOutput()}
+}
+
+// OutputReader reads the extendable output of a finalized BLAKE3 hash, whose
+// first Size bytes are its digest.
+type OutputReader struct {
+	o     output
+	pos   uint64 // bytes read
+	block [BlockSize]byte
+}
+
+// Read reads the next len(p) bytes of output into p. It never returns an
+// error.
+func (r *OutputReader) Read(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		off := int(r.pos % BlockSize)
+		if off == 0 {
+			r.block = r.o.rootBlock(r.pos / BlockSize)
+		}
+		k := copy(p, r.block[off:])
+		p = p[k:]
+		r.pos += uint64(k)
+	}
+	return n, nil
+}
+
+// Reset resets the hash to its initial state.
+func (h *Hasher) Reset() {
+	*h = *newHasher(h.base)
+}
+
+// Clone implements hash.Cloner, returning a Hasher with an independent copy of
+// the state of h.
+func (h *Hasher) Clone() (hash.Cloner, error) {
+	c := *h
+	return &c, nil
+}
+
+// Size returns the size of the digest, 32 bytes.
+func (h *Hasher) Size() int { return Size }
+
+// BlockSize returns the block size of BLAKE3, 64 bytes.
+func (h *Hasher) BlockSize() int { return BlockSize }
+
+// chainingValue returns the chaining value of the subtree written so far. It
+// is used instead of Sum when h hashes a subtree rather than the whole input.
+func (h *Hasher) chainingValue() [8]uint32 {
+	o := h.finalOutput()
+	return o.chainingValue()
+}
+
+var errInvalidState = errors.New("blake3: invalid hash state")
+
+const (
+	marshalMagic = "blake3\x01"
+	// marshaledSize is the size of a state with an empty stack: the magic,
+	// key, base, depth and chunk state.
+	marshaledSize = len(marshalMagic) + 32 + 8 + 1 + 32 + 8 + BlockSize + 1 + 1
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, saving the state of h so
+// hashing can be resumed later with UnmarshalBinary.
+func (h *Hasher) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize+32*h.depth)
+	b = append(b, marshalMagic...)
+	b = appendWords(b, h.key)
+	b = binary.LittleEndian.AppendUint64(b, h.base)
+	b = append(b, byte(h.depth))
+	for _, cv := range h.stack[:h.depth] {
+		b = appendWords(b, cv)
+	}
+	b = appendWords(b, h.chunk.cv)
+	b = binary.LittleEndian.AppendUint64(b, h.chunk.counter)
+	b = append(b, h.chunk.block[:]...)
+	b = append(b, byte(h.chunk.blockLen), byte(h.chunk.blocks))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a state
+// saved by MarshalBinary.
+func (h *Hasher) UnmarshalBinary(b []byte) error {
+	if len(b) < marshaledSize || string(b[:len(marshalMagic)]) != marshalMagic {
+		return errInvalidState
+	}
+	b = b[len(marshalMagic):]
+	var s Hasher
+	s.key, b = getWords(b), b[32:]
+	s.base, b = binary.LittleEndian.Uint64(b), b[8:]
+	s.depth, b = int(b[0]), b[1:]
+	if s.depth > len(s.stack) || len(b) != 32*s.depth+32+8+BlockSize+2 {
+		return errInvalidState
+	}
+	for i := range s.stack[:s.depth] {
+		s.stack[i], b = getWords(b), b[32:]
+	}
+	s.chunk.cv, b = getWords(b), b[32:]
+	s.chunk.counter, b = binary.LittleEndian.Uint64(b), b[8:]
+	b = b[copy(s.chunk.block[:], b):]
+	s.chunk.blockLen, s.chunk.blocks = int(b[0]), int(b[1])
+	if s.chunk.blockLen > BlockSize || s.chunk.blocks > ChunkSize/BlockSize {
+		return errInvalidState
+	}
+	*h = s
+	return nil
+}
+
+func appendWords(b []byte, w [8]uint32) []byte {
+	for _, v := range w {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
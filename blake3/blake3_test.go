@@ -0,0 +1,160 @@
+package blake3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// testVectors are from the official BLAKE3 test vectors, which hash inputs
+// made of the repeating byte pattern 0, 1, ..., 250.
+var testVectors = []struct {
+	n    int
+	want string
+}{
+	{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+	{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+	{1023, "10108970eeda3eb932baac1428c7a2163b0e924c9a9e25b35bba72b28f70bd11"},
+	{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+	{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+	{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+	{2049, "5f4d72f40d7a5f82b15ca2b2e44b1de3c2ef86c426c95c1af0b6879522563030"},
+	{3072, "b98cb0ff3623be03326b373de6b9095218513e64f1ee2edd2525c7ad1e5cffd2"},
+	{3073, "7124b49501012f81cc7f11ca069ec9226cecb8a2c850cfe644e327d22d3e1cd3"},
+	{4096, "015094013f57a5277b59d8475c0501042c0b642e531b0a1c8f58d2163229e969"},
+	{4097, "9b4052b38f1c5fc8b1f9ff7ac7b27cd242487b3d890d15c96a1c25b8aa0fb995"},
+	{8192, "aae792484c8efe4f19e2ca7d371d8c467ffb10748d8a5a1ae579948f718a2a63"},
+	{8193, "bab6c09cb8ce8cf459261398d2e7aef35700bf488116ceb94a36d0f5f1b7bc3b"},
+	{16384, "f875d6646de28985646f34ee13be9a576fd515f76b5b0a26bb324735041ddde4"},
+	{31744, "62b6960e1a44bcc1eb1a611a8d6235b6b4b78f32e7abc4fb4c6cdcce94895c47"},
+	{102400, "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+}
+
+func testInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func TestSum256(t *testing.T) {
+	for _, tc := range testVectors {
+		sum := Sum256(testInput(tc.n))
+		if got := hex.EncodeToString(sum[:]); got != tc.want {
+			t.Errorf("Sum256(%d bytes) got: %q, wanted %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestHasher(t *testing.T) {
+	h := New()
+	for _, tc := range testVectors {
+		h.Reset()
+		data := testInput(tc.n)
+		// Write in uneven pieces to cross block and chunk boundaries.
+		for len(data) > 0 {
+			k := min(len(data), 37)
+			h.Write(data[:k])
+			data = data[k:]
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("Hasher.Sum() of %d bytes got: %q, wanted %q", tc.n, got, tc.want)
+		}
+		// Sum must not change the state.
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("second Hasher.Sum() of %d bytes got: %q, wanted %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestXOF(t *testing.T) {
+	// The extended outputs of the official test vectors.
+	for _, tc := range []struct {
+		n    int
+		want string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262e00f03e7b69af26b7faaf09fcd333050338ddfe085b8cc869ca98b206c08243a26f5487789e8f660afe6c99ef9e0c52b92e7393024a80459cf91f476f9ffdbda7001c22e159b402631f277ca96f2defdf1078282314e763699a31c5363165421cce14d"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444f4c4a22b4b399155358a994e52bf255de60035742ec71bd08ac275a1b51cc6bfe332b0ef84b409108cda080e6269ed4b3e2c3f7d722aa4cdc98d16deb554e5627be8f955c98e1d5f9565a9194cad0c4285f93700062d9595adb992ae68ff12800ab67a"},
+	} {
+		h := New()
+		h.Write(testInput(tc.n))
+		if got := hex.EncodeToString(h.SumN(nil, len(tc.want)/2)); got != tc.want {
+			t.Errorf("Hasher.SumN() of %d bytes got: %q, wanted %q", tc.n, got, tc.want)
+		}
+
+		// Read in uneven pieces to cross block boundaries.
+		xof := h.XOF()
+		var out []byte
+		for len(out) < len(tc.want)/2 {
+			p := make([]byte, min(len(tc.want)/2-len(out), 23))
+			n, err := xof.Read(p)
+			if err != nil {
+				t.Fatalf("OutputReader.Read(): %v", err)
+			}
+			out = append(out, p[:n]...)
+		}
+		if got := hex.EncodeToString(out); got != tc.want {
+			t.Errorf("Hasher.XOF() of %d bytes read: %q, wanted %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestHashReaderAt(t *testing.T) {
+	for _, n := range []int{0, 1025, 102400, 1<<20 + 12345} {
+		data := testInput(n)
+		want := Sum256(data)
+		for _, workers := range []int{0, 1, 2, 3, 8} {
+			got, err := HashReaderAt(bytes.NewReader(data), int64(n), workers)
+			if err != nil {
+				t.Fatalf("HashReaderAt(%d bytes, %d workers): %v", n, workers, err)
+			}
+			if got != want {
+				t.Errorf("HashReaderAt(%d bytes, %d workers) got: %x, wanted %x", n, workers, got, want)
+			}
+		}
+	}
+
+	data := testInput(1 << 20)
+	if _, err := HashReaderAt(bytes.NewReader(data), 2<<20, 4); err != io.ErrUnexpectedEOF {
+		t.Errorf("HashReaderAt() past the end got error: %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func BenchmarkHashReaderAt(b *testing.B) {
+	data := testInput(16 << 20)
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprint(workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				HashReaderAt(bytes.NewReader(data), int64(len(data)), workers)
+			}
+		})
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data := testInput(102400)
+	want := Sum256(data)
+	for _, split := range []int{0, 1, 64, 1024, 1025, 4096, 31744, 102400} {
+		h := New()
+		h.Write(data[:split])
+		state, err := h.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Hasher.MarshalBinary() at %d: %v", split, err)
+		}
+		resumed := New()
+		if err := resumed.UnmarshalBinary(state); err != nil {
+			t.Fatalf("Hasher.UnmarshalBinary() at %d: %v", split, err)
+		}
+		resumed.Write(data[split:])
+		if got := resumed.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Errorf("resumed at %d, Hasher.Sum() got: %x, wanted %x", split, got, want)
+		}
+		if err := New().UnmarshalBinary(state[:len(state)-1]); err == nil {
+			t.Errorf("Hasher.UnmarshalBinary(truncated state at %d) got no error", split)
+		}
+	}
+}
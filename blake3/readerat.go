@@ -0,0 +1,114 @@
+package blake3
+
+import (
+	"io"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// minParallelSubtree is the smallest subtree HashReaderAt hands to a goroutine
+// of its own. Smaller subtrees are not worth the scheduling overhead.
+const minParallelSubtree = 64 * ChunkSize
+
+// readBufSize is the size of the buffer each goroutine of HashReaderAt reads
+// through.
+const readBufSize = 256 << 10
+
+// HashReaderAt returns the BLAKE3 digest of the first size bytes of ra,
+// hashing independent subtrees of the input on up to workers goroutines. If
+// workers is less than one, runtime.GOMAXPROCS(0) is used. The result is the
+// same as hashing the data with New.
+//
+// Each goroutine reads its own section of ra, so ra must support concurrent
+// calls to ReadAt, as *os.File does. io.ErrUnexpectedEOF is returned if ra
+// holds fewer than size bytes.
+func HashReaderAt(ra io.ReaderAt, size int64, workers int) ([Size]byte, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var sum [Size]byte
+	if size <= ChunkSize || workers == 1 {
+		h := New()
+		if err := copySection(h, ra, 0, size); err != nil {
+			return sum, err
+		}
+		h.Sum(sum[:0])
+		return sum, nil
+	}
+
+	// The root is a parent node, which must be finalized with the root flag.
+	left, right, err := children(ra, 0, size, workers)
+	if err != nil {
+		return sum, err
+	}
+	key := iv
+	o := parentOutput(left, right, &key)
+	o.rootBytes(sum[:0], Size)
+	return sum, nil
+}
+
+// subtreeCV returns the chaining value of the subtree holding the n bytes of
+// ra at offset off, which is a multiple of ChunkSize.
+func subtreeCV(ra io.ReaderAt, off, n int64, workers int) ([8]uint32, error) {
+	if workers < 2 || n < 2*minParallelSubtree {
+		h := newHasher(uint64(off / ChunkSize))
+		if err := copySection(h, ra, off, n); err != nil {
+			return [8]uint32{}, err
+		}
+		return h.chainingValue(), nil
+	}
+	left, right, err := children(ra, off, n, workers)
+	if err != nil {
+		return [8]uint32{}, err
+	}
+	key := iv
+	return parentCV(left, right, &key), nil
+}
+
+// children returns the chaining values of the two children of the subtree
+// holding the n bytes of ra at offset off, computing them concurrently.
+func children(ra io.ReaderAt, off, n int64, workers int) (left, right [8]uint32, err error) {
+	l := leftLen(n)
+	var lerr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		left, lerr = subtreeCV(ra, off, l, workers/2)
+	}()
+	right, err = subtreeCV(ra, off+l, n-l, workers-workers/2)
+	wg.Wait()
+	if lerr != nil {
+		return left, right, lerr
+	}
+	return left, right, err
+}
+
+// leftLen returns the number of bytes in the left subtree of a tree holding n
+// bytes, n > ChunkSize: the largest power of two number of chunks that leaves
+// at least one byte for the right subtree.
+func leftLen(n int64) int64 {
+	full := uint64(n-1) / ChunkSize
+	return int64(1) << (bits.Len64(full) - 1) * ChunkSize
+}
+
+// copySection writes the n bytes of ra at offset off to h.
+func copySection(h *Hasher, ra io.ReaderAt, off, n int64) error {
+	buf := make([]byte, min(n, readBufSize))
+	for n > 0 {
+		p := buf[:min(n, int64(len(buf)))]
+		k, err := ra.ReadAt(p, off)
+		h.Write(p[:k])
+		off += int64(k)
+		n -= int64(k)
+		if err == io.EOF && n > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+	return nil
+}
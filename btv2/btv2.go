@@ -0,0 +1,195 @@
+// Package btv2 computes the per-file Merkle trees of BitTorrent v2 (BEP 52):
+// the "pieces root" of a file and its piece layer, from a single pass over the
+// file's data.
+//
+// The tree's leaves are the SHA-256 hashes of consecutive 16 KiB blocks of the
+// file, the last of which may be shorter. The leaf layer is padded with zero
+// hashes up to a power of two and every interior node is the SHA-256 hash of
+// its two children.
+package btv2
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+)
+
+const (
+	// BlockSize is the size of the data blocks at the leaves of the tree.
+	BlockSize = 16 << 10
+	// Size is the size of the pieces root and of every hash in the tree.
+	Size = sha256.Size
+)
+
+// ErrPieceLength is returned by New for piece lengths that are not a power of
+// two of at least BlockSize.
+var ErrPieceLength = errors.New("btv2: piece length must be a power of two and at least 16 KiB")
+
+// Hasher computes the Merkle tree of one file. It implements hash.Hash, with
+// the pieces root as the digest, so it can be used as one of the hashes of a
+// hashio.HashReader or hashio.HashWriter while the file is copied.
+type Hasher struct {
+	pieceLength int64
+	block       hash.Hash // hashes the current block
+	blockLen    int       // bytes written to block
+	leaves      [][]byte  // leaf hashes of the current piece
+	pieces      [][]byte  // hashes of the completed pieces
+	n           int64     // bytes written
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+// New returns a Hasher for a torrent with the given piece length.
+func New(pieceLength int64) (*Hasher, error) {
+	if pieceLength < BlockSize || pieceLength&(pieceLength-1) != 0 {
+		return nil, ErrPieceLength
+	}
+	return &Hasher{pieceLength: pieceLength, block: sha256.New()}, nil
+}
+
+// Write adds p to the file data. It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// A full block (and piece) is only finished once more data arrives,
+		// so that the last, possibly partial, ones are handled by Sum.
+		if h.blockLen == BlockSize {
+			h.finishBlock()
+		}
+		k := min(BlockSize-h.blockLen, len(p))
+		h.block.Write(p[:k])
+		h.blockLen += k
+		p = p[k:]
+	}
+	h.n += int64(n)
+	return n, nil
+}
+
+// finishBlock adds the hash of the current, full block to the leaves,
+// completing a piece once it has all of its leaves.
+func (h *Hasher) finishBlock() {
+	h.leaves = append(h.leaves, h.block.Sum(nil))
+	h.block.Reset()
+	h.blockLen = 0
+	if int64(len(h.leaves))*BlockSize == h.pieceLength {
+		h.pieces = append(h.pieces, root(h.leaves, len(h.leaves)))
+		h.leaves = h.leaves[:0]
+	}
+}
+
+// finalLayers returns the leaf hashes of the last piece and the hashes of the
+// completed pieces, including the current block and piece, without changing
+// h.
+func (h *Hasher) finalLayers() (leaves, pieces [][]byte) {
+	leaves = append(h.leaves[:len(h.leaves):len(h.leaves)], h.block.Sum(nil))
+	return leaves, h.pieces
+}
+
+// Len returns the number of bytes written.
+func (h *Hasher) Len() int64 {
+	return h.n
+}
+
+// PiecesRoot returns the root of the file's Merkle tree, or nil for an empty
+// file, which has none.
+func (h *Hasher) PiecesRoot() []byte {
+	if h.n == 0 {
+		return nil
+	}
+	leaves, pieces := h.finalLayers()
+	if len(pieces) == 0 {
+		// The file fits in one piece; its tree is only as wide as needed.
+		return root(leaves, nextPow2(len(leaves)))
+	}
+	leavesPerPiece := int(h.pieceLength / BlockSize)
+	pieces = append(pieces[:len(pieces):len(pieces)], root(leaves, leavesPerPiece))
+	return rootPadded(pieces, nextPow2(len(pieces)), padHash(leavesPerPiece))
+}
+
+// PieceLayer returns the concatenated hashes of the piece layer of the tree,
+// as stored in the "piece layers" dictionary of a v2 torrent. Files no larger
+// than one piece have no piece layer and nil is returned.
+func (h *Hasher) PieceLayer() []byte {
+	if h.n <= h.pieceLength {
+		return nil
+	}
+	leaves, pieces := h.finalLayers()
+	last := root(leaves, int(h.pieceLength/BlockSize))
+	layer := make([]byte, 0, (len(pieces)+1)*Size)
+	for _, p := range pieces {
+		layer = append(layer, p...)
+	}
+	return append(layer, last...)
+}
+
+// Sum appends the pieces root to b. For an empty file, which has no pieces
+// root, Size zero bytes are appended. Sum does not change the state of h.
+func (h *Hasher) Sum(b []byte) []byte {
+	r := h.PiecesRoot()
+	if r == nil {
+		r = make([]byte, Size)
+	}
+	return append(b, r...)
+}
+
+// Reset discards all data written.
+func (h *Hasher) Reset() {
+	h.block.Reset()
+	h.blockLen = 0
+	h.leaves = h.leaves[:0]
+	h.pieces = nil
+	h.n = 0
+}
+
+// Size returns the size of the pieces root.
+func (h *Hasher) Size() int { return Size }
+
+// BlockSize returns the size of the blocks at the leaves of the tree.
+func (h *Hasher) BlockSize() int { return BlockSize }
+
+// root returns the root of the tree over leaves, padded with zero hashes to
+// width leaves, a power of two.
+func root(leaves [][]byte, width int) []byte {
+	return rootPadded(leaves, width, make([]byte, Size))
+}
+
+// rootPadded returns the root of the tree over nodes padded with pad to width
+// nodes, a power of two.
+func rootPadded(nodes [][]byte, width int, pad []byte) []byte {
+	layer := make([][]byte, width)
+	copy(layer, nodes)
+	for i := len(nodes); i < width; i++ {
+		layer[i] = pad
+	}
+	for len(layer) > 1 {
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = node(layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0]
+}
+
+// padHash returns the root of a subtree of width zero leaves.
+func padHash(width int) []byte {
+	pad := make([]byte, Size)
+	for ; width > 1; width >>= 1 {
+		pad = node(pad, pad)
+	}
+	return pad
+}
+
+func node(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,111 @@
+package btv2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// referenceTree computes the layers of the BEP 52 tree of data the slow way:
+// layers[0] holds the padded leaves and the last layer holds the root.
+func referenceTree(data []byte) [][][]byte {
+	var leaves [][]byte
+	for i := 0; i < len(data); i += BlockSize {
+		sum := sha256.Sum256(data[i:min(i+BlockSize, len(data))])
+		leaves = append(leaves, sum[:])
+	}
+	for len(leaves)&(len(leaves)-1) != 0 {
+		leaves = append(leaves, make([]byte, Size))
+	}
+	layers := [][][]byte{leaves}
+	for len(leaves) > 1 {
+		var next [][]byte
+		for i := 0; i < len(leaves); i += 2 {
+			sum := sha256.Sum256(append(bytes.Clone(leaves[i]), leaves[i+1]...))
+			next = append(next, sum[:])
+		}
+		layers = append(layers, next)
+		leaves = next
+	}
+	return layers
+}
+
+func TestHasher(t *testing.T) {
+	const pieceLength = 4 * BlockSize
+	for _, n := range []int{1, 100, BlockSize, BlockSize + 1, pieceLength - 1, pieceLength, pieceLength + 1, 5*pieceLength + 123, 8 * pieceLength} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i * 31)
+		}
+		h, err := New(pieceLength)
+		if err != nil {
+			t.Fatalf("New(%d): %v", pieceLength, err)
+		}
+		for i := 0; i < n; i += 5000 {
+			h.Write(data[i:min(i+5000, n)])
+		}
+
+		layers := referenceTree(data)
+		wantRoot := layers[len(layers)-1][0]
+		if got := h.PiecesRoot(); !bytes.Equal(got, wantRoot) {
+			t.Errorf("%d bytes: Hasher.PiecesRoot() got: %x, wanted %x", n, got, wantRoot)
+		}
+
+		var wantLayer []byte
+		if n > pieceLength {
+			// The piece layer is two layers above the leaves, without the
+			// padding beyond the end of the file.
+			pieces := (n + pieceLength - 1) / pieceLength
+			for _, p := range layers[2][:pieces] {
+				wantLayer = append(wantLayer, p...)
+			}
+		}
+		if got := h.PieceLayer(); !bytes.Equal(got, wantLayer) {
+			t.Errorf("%d bytes: Hasher.PieceLayer() got %d bytes, wanted %d", n, len(got), len(wantLayer))
+		}
+	}
+}
+
+func TestHasherEmptyAndReset(t *testing.T) {
+	h, err := New(BlockSize)
+	if err != nil {
+		t.Fatalf("New(%d): %v", BlockSize, err)
+	}
+	if h.PiecesRoot() != nil || h.PieceLayer() != nil {
+		t.Errorf("empty file got pieces root %x and piece layer %x, wanted neither", h.PiecesRoot(), h.PieceLayer())
+	}
+	h.Write([]byte("hello"))
+	h.Reset()
+	h.Write([]byte("abc"))
+	want := sha256.Sum256([]byte("abc"))
+	if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("Hasher.Sum() after Reset got: %x, wanted %x", got, want)
+	}
+
+	for _, l := range []int64{0, BlockSize / 2, 3 * BlockSize} {
+		if _, err := New(l); err != ErrPieceLength {
+			t.Errorf("New(%d) got error: %v, wanted %v", l, err, ErrPieceLength)
+		}
+	}
+}
+
+func TestHasherWithHashWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("torrent"), 10000)
+	bt, err := New(BlockSize)
+	if err != nil {
+		t.Fatalf("New(%d): %v", BlockSize, err)
+	}
+	var dst bytes.Buffer
+	hw := hashio.NewHashWriter(&dst, map[string]hash.Hash{"btv2": bt, hashio.SHA256: sha256.New()})
+	if _, err := hw.Write(data); err != nil {
+		t.Fatalf("HashWriter.Write(): %v", err)
+	}
+	layers := referenceTree(data)
+	if got, want := hw.HexHash("btv2"), hex.EncodeToString(layers[len(layers)-1][0]); got != want {
+		t.Errorf("HashWriter.HexHash(btv2) got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package hashio
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// bufferSize is the size set by SetBufferSize, or 0 if it was never set.
+var bufferSize atomic.Int64
+
+// SetBufferSize sets the size of the buffers used by the functions and methods
+// of this package that copy data themselves: CopyAndHash, HashFile,
+// HashSection, HashWriter.ReadFrom and HashReader.WriteTo. Values of n less
+// than or equal to zero restore the defaults, which are 32 KiB (like io.Copy)
+// and 256 KiB for HashFile. A size given to WithBufferSize takes precedence.
+//
+// The buffers are pooled and reused across calls, so it is safe to call
+// SetBufferSize at any time, though it is intended to be called once at
+// startup.
+func SetBufferSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	bufferSize.Store(int64(n))
+}
+
+// copyBufSize returns the size set by SetBufferSize, or def if it is unset.
+func copyBufSize(def int) int {
+	if n := bufferSize.Load(); n > 0 {
+		return int(n)
+	}
+	return def
+}
+
+// bufferPools maps a buffer size to the *sync.Pool of *[]byte of that size.
+// Sizes are few in practice (the defaults and whatever was configured), so a
+// pool per size keeps buffers of different sizes from evicting each other.
+var bufferPools sync.Map
+
+// getBuffer returns a buffer of size bytes from the pool. It should be given
+// back with putBuffer once it is no longer used.
+func getBuffer(size int) *[]byte {
+	p, ok := bufferPools.Load(size)
+	if !ok {
+		p, _ = bufferPools.LoadOrStore(size, &sync.Pool{
+			New: func() any {
+				b := make([]byte, size)
+				return &b
+			},
+		})
+	}
+	return p.(*sync.Pool).Get().(*[]byte)
+}
+
+// putBuffer returns a buffer obtained from getBuffer to the pool.
+func putBuffer(b *[]byte) {
+	if p, ok := bufferPools.Load(len(*b)); ok {
+		p.(*sync.Pool).Put(b)
+	}
+}
@@ -0,0 +1,67 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSetBufferSize(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	SetBufferSize(64)
+	defer SetBufferSize(0)
+
+	src := &readSizes{Reader: bytes.NewReader(contents)}
+	hw := NewWriter(io.Discard, WithSHA256())
+	if _, err := hw.ReadFrom(src); err != nil {
+		t.Fatalf("HashWriter.ReadFrom(): %v", err)
+	}
+	if got := src.sizes[0]; got != 64 {
+		t.Errorf("HashWriter.ReadFrom() read with a buffer of %d bytes, wanted 64", got)
+	}
+	if got := hw.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+
+	// WithBufferSize takes precedence.
+	src = &readSizes{Reader: bytes.NewReader(contents)}
+	hw = NewWriter(io.Discard, WithSHA256(), WithBufferSize(16))
+	if _, err := hw.ReadFrom(src); err != nil {
+		t.Fatalf("HashWriter.ReadFrom(): %v", err)
+	}
+	if got := src.sizes[0]; got != 16 {
+		t.Errorf("HashWriter.ReadFrom() with WithBufferSize(16) read with a buffer of %d bytes, wanted 16", got)
+	}
+
+	SetBufferSize(0)
+	src = &readSizes{Reader: bytes.NewReader(contents)}
+	hw = NewWriter(io.Discard, WithSHA256())
+	if _, err := hw.ReadFrom(src); err != nil {
+		t.Fatalf("HashWriter.ReadFrom(): %v", err)
+	}
+	if got := src.sizes[0]; got != defaultBufSize {
+		t.Errorf("HashWriter.ReadFrom() after SetBufferSize(0) read with a buffer of %d bytes, wanted %d", got, defaultBufSize)
+	}
+}
+
+func TestBufferPooling(t *testing.T) {
+	res := testing.Benchmark(func(b *testing.B) {
+		src := &onlyReader{bytes.NewReader(nil)}
+		hw := NewWriter(io.Discard, WithSHA256())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			src.Reader.(*bytes.Reader).Reset([]byte("hello"))
+			hw.Reset(io.Discard)
+			hw.ReadFrom(src)
+		}
+	})
+	// Without pooling every call allocates a 32 KiB buffer.
+	if got := res.AllocedBytesPerOp(); got >= defaultBufSize {
+		t.Errorf("HashWriter.ReadFrom() allocated %d bytes per call, wanted less than %d", got, defaultBufSize)
+	}
+}
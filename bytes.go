@@ -0,0 +1,26 @@
+package hashio
+
+// HashBytes hashes b with a fresh instance of each algorithm in names and
+// returns the digests keyed by name. If no names are given, the hashes of
+// StdCryptoHashes are computed. An error is returned only if a name is not a
+// known algorithm.
+func HashBytes(b []byte, names ...string) (map[string][]byte, error) {
+	hashers, err := hashersByName(names)
+	if err != nil {
+		return nil, err
+	}
+	h := NewHasher(hashers)
+	h.Write(b)
+	return h.Sums(), nil
+}
+
+// HashString is like HashBytes but hashes s without copying it.
+func HashString(s string, names ...string) (map[string][]byte, error) {
+	hashers, err := hashersByName(names)
+	if err != nil {
+		return nil, err
+	}
+	h := NewHasher(hashers)
+	h.WriteString(s)
+	return h.Sums(), nil
+}
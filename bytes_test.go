@@ -0,0 +1,66 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"slices"
+	"testing"
+)
+
+func TestHashBytesAndString(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	want := map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256}
+	fromBytes, err := HashBytes(contents)
+	if err != nil {
+		t.Fatalf("HashBytes(): %v", err)
+	}
+	fromString, err := HashString(string(contents))
+	if err != nil {
+		t.Fatalf("HashString(): %v", err)
+	}
+	for name, w := range want {
+		if got := hex.EncodeToString(fromBytes[name]); got != w {
+			t.Errorf("HashBytes()[%s] got: %q, wanted %q", name, got, w)
+		}
+		if got := hex.EncodeToString(fromString[name]); got != w {
+			t.Errorf("HashString()[%s] got: %q, wanted %q", name, got, w)
+		}
+	}
+
+	sums, err := HashString("abc", SHA512)
+	if err != nil {
+		t.Fatalf("HashString(\"abc\", sha512): %v", err)
+	}
+	if len(sums) != 1 || len(sums[SHA512]) != 64 {
+		t.Errorf("HashString(\"abc\", sha512) got: %x, wanted a single 64 byte sha512 digest", sums)
+	}
+}
+
+func TestHashBytesUnknown(t *testing.T) {
+	var unknown *UnknownHashError
+	if _, err := HashBytes(nil, SHA256, "nope"); !errors.As(err, &unknown) || unknown.Name != "nope" {
+		t.Errorf("HashBytes(nil, sha256, nope) got error: %v, wanted *UnknownHashError for \"nope\"", err)
+	}
+	if unknown != nil && !slices.Contains(unknown.Available, SHA256) {
+		t.Errorf("HashBytes(nil, sha256, nope) got error with Available %q, wanted the registered algorithms", unknown.Available)
+	}
+	if _, err := HashString("", "nope"); !errors.As(err, &unknown) || !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("HashString(\"\", nope) got error: %v, wanted *UnknownHashError", err)
+	}
+}
+
+func TestHashStringBLAKE3(t *testing.T) {
+	sums, err := HashString("abc", BLAKE3)
+	if err != nil {
+		t.Fatalf("HashString(\"abc\", blake3): %v", err)
+	}
+	want := "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"
+	if got := hex.EncodeToString(sums[BLAKE3]); got != want {
+		t.Errorf("HashString(\"abc\", blake3) got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package hashio
+
+import (
+	"bufio"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// CPUFeatures reports the instruction set extensions of the current machine
+// that speed up hashing. Go's SHA-1, SHA-256 and SHA-512 implementations use
+// them when present, which can change which algorithm is fastest by several
+// times.
+type CPUFeatures struct {
+	// Arch is runtime.GOARCH.
+	Arch string
+	// Detected is false if the features couldn't be determined on this
+	// platform, in which case they are all reported as missing. Detection
+	// reads /proc/cpuinfo, so it only works on Linux.
+	Detected bool
+
+	// SHANI is the x86 SHA extensions, accelerating SHA-1 and SHA-256.
+	SHANI bool
+	// AVX2 and AVX512 are the x86 vector extensions (AVX512 is AVX-512F),
+	// used by SHA-1, SHA-256 and SHA-512 without SHANI.
+	AVX2   bool
+	AVX512 bool
+	// ARMSHA1, ARMSHA2, ARMSHA512 and ARMSHA3 are the ARMv8 cryptographic
+	// extensions, accelerating SHA-1, SHA-256, SHA-512 and SHA-3.
+	ARMSHA1   bool
+	ARMSHA2   bool
+	ARMSHA512 bool
+	ARMSHA3   bool
+}
+
+// Capabilities returns the hashing related CPU features of the current
+// machine. See CPUFeatures.
+func Capabilities() CPUFeatures {
+	f := CPUFeatures{Arch: runtime.GOARCH}
+	cpuinfo, ok := readCPUInfo()
+	if !ok {
+		return f
+	}
+	defer cpuinfo.Close()
+	parseCPUInfo(&f, cpuinfo)
+	return f
+}
+
+// parseCPUInfo sets the features listed by the first "flags" (x86) or
+// "Features" (ARM) line of cpuinfo, in the format of /proc/cpuinfo.
+func parseCPUInfo(f *CPUFeatures, cpuinfo io.Reader) {
+	s := bufio.NewScanner(cpuinfo)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+		if key = strings.TrimSpace(key); key != "flags" && key != "Features" {
+			continue
+		}
+		f.Detected = true
+		for _, flag := range strings.Fields(value) {
+			switch flag {
+			case "sha_ni":
+				f.SHANI = true
+			case "avx2":
+				f.AVX2 = true
+			case "avx512f":
+				f.AVX512 = true
+			case "sha1":
+				f.ARMSHA1 = true
+			case "sha2":
+				f.ARMSHA2 = true
+			case "sha512":
+				f.ARMSHA512 = true
+			case "sha3":
+				f.ARMSHA3 = true
+			}
+		}
+		return
+	}
+}
+
+// recommendable are the algorithms RecommendAlgorithm picks from by default:
+// the built in cryptographic hashes with at least 128 bits of collision
+// resistance.
+var recommendable = []string{SHA256, SHA512_256, SHA3_256, BLAKE2b_256, BLAKE2s_256, BLAKE3}
+
+// benchmarkSize is the number of bytes each candidate of RecommendAlgorithm
+// hashes per round, in writes of benchmarkChunk bytes.
+const (
+	benchmarkSize  = 1 << 20
+	benchmarkChunk = 64 << 10
+)
+
+// RecommendAlgorithm measures the throughput of the registered algorithms in
+// acceptable on the current machine and returns the fastest one. If
+// acceptable is empty, it picks among the built in cryptographic hashes with a
+// 256-bit digest: SHA256, SHA512_256, SHA3_256, BLAKE2b_256, BLAKE2s_256 and
+// BLAKE3, or only among those FIPS-only mode allows when it's on. An
+// *UnknownHashError is returned if a name isn't registered.
+//
+// Each candidate hashes a few MiB, which takes milliseconds, so the result
+// should be computed once, for example at startup, rather than per stream.
+// It is only a measurement: on a busy machine two similar algorithms may
+// rank differently from one run to the next.
+func RecommendAlgorithm(acceptable ...string) (string, error) {
+	if len(acceptable) == 0 {
+		for _, alg := range recommendable {
+			if !FIPSOnly() || fipsAllowed(alg) {
+				acceptable = append(acceptable, alg)
+			}
+		}
+	}
+	hashers, err := hashersByName(acceptable)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, benchmarkChunk)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+	var best string
+	var bestTime time.Duration
+	for _, name := range acceptable {
+		h := hashers[name]
+		// The best of a few rounds, to ignore a round slowed by the scheduler.
+		fastest := time.Duration(-1)
+		for round := 0; round < 3; round++ {
+			h.Reset()
+			start := time.Now()
+			for n := 0; n < benchmarkSize; n += len(buf) {
+				h.Write(buf)
+			}
+			h.Sum(nil)
+			if d := time.Since(start); fastest < 0 || d < fastest {
+				fastest = d
+			}
+		}
+		if best == "" || fastest < bestTime {
+			best, bestTime = name, fastest
+		}
+	}
+	return best, nil
+}
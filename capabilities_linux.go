@@ -0,0 +1,17 @@
+//go:build linux
+
+package hashio
+
+import (
+	"io"
+	"os"
+)
+
+// readCPUInfo opens /proc/cpuinfo.
+func readCPUInfo() (io.ReadCloser, bool) {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
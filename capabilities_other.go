@@ -0,0 +1,10 @@
+//go:build !linux
+
+package hashio
+
+import "io"
+
+// readCPUInfo always reports false: CPU features are only detected on Linux.
+func readCPUInfo() (io.ReadCloser, bool) {
+	return nil, false
+}
@@ -0,0 +1,77 @@
+package hashio
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCPUInfo(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		cpuinfo string
+		want    CPUFeatures
+	}{
+		{
+			desc:    "x86",
+			cpuinfo: "processor\t: 0\nflags\t\t: fpu sse2 avx2 sha_ni avx512f\n\nprocessor\t: 1\nflags\t\t: fpu\n",
+			want:    CPUFeatures{Detected: true, SHANI: true, AVX2: true, AVX512: true},
+		},
+		{
+			desc:    "arm64",
+			cpuinfo: "processor\t: 0\nFeatures\t: fp asimd aes pmull sha1 sha2 crc32 sha3 sha512\n",
+			want:    CPUFeatures{Detected: true, ARMSHA1: true, ARMSHA2: true, ARMSHA512: true, ARMSHA3: true},
+		},
+		{
+			desc:    "none",
+			cpuinfo: "flags\t\t: fpu sse2 avx\n",
+			want:    CPUFeatures{Detected: true},
+		},
+		{
+			desc:    "no flags",
+			cpuinfo: "processor\t: 0\n",
+			want:    CPUFeatures{},
+		},
+	} {
+		var got CPUFeatures
+		parseCPUInfo(&got, strings.NewReader(tc.cpuinfo))
+		if got != tc.want {
+			t.Errorf("parseCPUInfo(%s) got: %+v, wanted %+v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	f := Capabilities()
+	if f.Arch != runtime.GOARCH {
+		t.Errorf("Capabilities().Arch got: %q, wanted %q", f.Arch, runtime.GOARCH)
+	}
+	if runtime.GOOS == "linux" && !f.Detected {
+		t.Errorf("Capabilities().Detected on Linux got: false, wanted true")
+	}
+}
+
+func TestRecommendAlgorithm(t *testing.T) {
+	got, err := RecommendAlgorithm(SHA256, MD5)
+	if err != nil {
+		t.Fatalf("RecommendAlgorithm(sha256, md5): %v", err)
+	}
+	if got != SHA256 && got != MD5 {
+		t.Errorf("RecommendAlgorithm(sha256, md5) got: %q, wanted one of them", got)
+	}
+	if got, err := RecommendAlgorithm(); err != nil || !slices.Contains(recommendable, got) {
+		t.Errorf("RecommendAlgorithm() got: %q, %v, wanted one of %q", got, err, recommendable)
+	}
+	var unknown *UnknownHashError
+	if _, err := RecommendAlgorithm(SHA256, "missing"); !errors.As(err, &unknown) {
+		t.Errorf("RecommendAlgorithm(sha256, missing) got: %v, wanted an *UnknownHashError", err)
+	}
+
+	SetFIPSOnly(true)
+	t.Cleanup(func() { SetFIPSOnly(false) })
+	if got, err := RecommendAlgorithm(); err != nil || !FIPSApproved(got) {
+		t.Errorf("RecommendAlgorithm() in FIPS-only mode got: %q, %v, wanted a FIPS approved algorithm", got, err)
+	}
+}
@@ -0,0 +1,96 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+	"math/bits"
+
+	"github.com/mikewiacek/hashio/internal/gear"
+)
+
+// minCDCAverage is the smallest average chunk size NewCDCReader accepts. The gear
+// hash used to find boundaries only considers the last 64 bytes of input, so
+// smaller chunks would not be meaningfully content defined.
+const minCDCAverage = 64
+
+// CDCReader splits the data read from an io.Reader into content-defined chunks
+// and computes a strong digest of each chunk. Because boundaries are chosen by a
+// rolling hash over the content rather than by offset, inserting or removing
+// bytes only changes the chunks around the edit, which makes the chunks suitable
+// as deduplication keys.
+type CDCReader struct {
+	r      io.Reader
+	strong func() hash.Hash
+	min    int
+	max    int
+	mask   uint64
+	buf    []byte
+	err    error
+}
+
+// NewCDCReader returns a CDCReader that reads from r and cuts chunks averaging
+// roughly avgChunkSize bytes. Chunks are never smaller than avgChunkSize/4 bytes
+// (except for the final chunk) nor larger than avgChunkSize*4 bytes. avgChunkSize
+// is rounded down to a power of two and raised to 64 if it is smaller than that.
+//
+// strong is called once per chunk to obtain the hash.Hash used for that chunk's
+// digest.
+func NewCDCReader(r io.Reader, avgChunkSize int, strong func() hash.Hash) *CDCReader {
+	if avgChunkSize < minCDCAverage {
+		avgChunkSize = minCDCAverage
+	}
+	shift := bits.Len(uint(avgChunkSize)) - 1
+	avg := 1 << shift
+
+	return &CDCReader{
+		r:      r,
+		strong: strong,
+		min:    avg / 4,
+		max:    avg * 4,
+		mask:   gear.Mask(shift),
+	}
+}
+
+// NextChunk returns the next chunk of data along with its strong digest. The
+// returned data is not modified by later calls and may be retained by the caller.
+//
+// After the last chunk NextChunk returns io.EOF. Any other error returned by the
+// underlying reader is returned once all data read before it has been chunked.
+func (c *CDCReader) NextChunk() (data []byte, digest []byte, err error) {
+	c.fill()
+	if len(c.buf) == 0 {
+		if c.err == nil {
+			c.err = io.EOF
+		}
+		return nil, nil, c.err
+	}
+
+	cut := c.boundary()
+	data = c.buf[:cut:cut]
+	c.buf = c.buf[cut:]
+
+	h := c.strong()
+	h.Write(data)
+	return data, h.Sum(nil), nil
+}
+
+// fill reads from the underlying reader until a maximum sized chunk is buffered
+// or the reader reports an error.
+func (c *CDCReader) fill() {
+	for len(c.buf) < c.max && c.err == nil {
+		if cap(c.buf)-len(c.buf) < c.max {
+			buf := make([]byte, len(c.buf), len(c.buf)+2*c.max)
+			copy(buf, c.buf)
+			c.buf = buf
+		}
+
+		n, err := c.r.Read(c.buf[len(c.buf):cap(c.buf)])
+		c.buf = c.buf[:len(c.buf)+n]
+		c.err = err
+	}
+}
+
+// boundary returns the length of the next chunk in c.buf.
+func (c *CDCReader) boundary() int {
+	return gear.Cut(c.buf, c.min, c.max, c.max, c.mask, c.mask)
+}
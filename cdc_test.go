@@ -0,0 +1,83 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+type cdcChunk struct {
+	data   []byte
+	digest []byte
+}
+
+func readChunks(t *testing.T, data []byte, avg int) []cdcChunk {
+	t.Helper()
+
+	var chunks []cdcChunk
+	c := NewCDCReader(bytes.NewReader(data), avg, sha256.New)
+	for {
+		d, sum, err := c.NextChunk()
+		if err == io.EOF {
+			return chunks
+		}
+		if err != nil {
+			t.Fatalf("CDCReader.NextChunk: %v", err)
+		}
+		chunks = append(chunks, cdcChunk{d, sum})
+	}
+}
+
+func TestCDCReader(t *testing.T) {
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	const avg = 4096
+	first := readChunks(t, data, avg)
+	second := readChunks(t, data, avg)
+
+	if len(first) != len(second) {
+		t.Fatalf("CDCReader produced %d chunks then %d chunks for the same data", len(first), len(second))
+	}
+	if len(first) < 2 {
+		t.Fatalf("CDCReader produced %d chunks, wanted many", len(first))
+	}
+
+	var joined []byte
+	for i, c := range first {
+		if !bytes.Equal(c.data, second[i].data) || !bytes.Equal(c.digest, second[i].digest) {
+			t.Errorf("chunk %d differs between runs", i)
+		}
+		if want := sha256.Sum256(c.data); !bytes.Equal(c.digest, want[:]) {
+			t.Errorf("chunk %d digest got: %x, wanted %x", i, c.digest, want)
+		}
+		if len(c.data) > avg*4 {
+			t.Errorf("chunk %d is %d bytes, wanted at most %d", i, len(c.data), avg*4)
+		}
+		if i < len(first)-1 && len(c.data) < avg/4 {
+			t.Errorf("chunk %d is %d bytes, wanted at least %d", i, len(c.data), avg/4)
+		}
+		joined = append(joined, c.data...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Errorf("concatenated chunks do not match the input")
+	}
+
+	// Prepending data should only disturb the first few chunks.
+	shifted := readChunks(t, append([]byte("some new header bytes"), data...), avg)
+	seen := make(map[string]bool)
+	for _, c := range first {
+		seen[string(c.digest)] = true
+	}
+	var shared int
+	for _, c := range shifted {
+		if seen[string(c.digest)] {
+			shared++
+		}
+	}
+	if shared < len(first)-2 {
+		t.Errorf("%d of %d chunks survived a prepend, wanted at least %d", shared, len(first), len(first)-2)
+	}
+}
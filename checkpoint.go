@@ -0,0 +1,61 @@
+package hashio
+
+import "hash"
+
+// stop is called by a HashReader or HashWriter with its hashes every time the
+// number of bytes hashed reaches a multiple of every. Stops are set by
+// WithCheckpoint and RecordEvery.
+type stop struct {
+	every int64
+	at    func(hashers map[string]hash.Hash, offset int64)
+}
+
+// WithCheckpoint makes a HashReader or HashWriter call fn with the state of its
+// hashes, as returned by MarshalBinary, every time the number of bytes read or
+// written reaches a multiple of every. Reads and Writes crossing a multiple are
+// hashed in two steps so the state describes exactly offset bytes. fn runs
+// synchronously, so persisting the state (for instance along with the offset,
+// to resume with UnmarshalBinary after a crash) slows down the stream.
+//
+// If a hash doesn't implement encoding.BinaryMarshaler, fn is called with a
+// nil state. WithCheckpoint does nothing if every is not positive.
+func WithCheckpoint(every int64, fn func(offset int64, state []byte)) Option {
+	return func(c *config) {
+		if every <= 0 || fn == nil {
+			return
+		}
+		c.stops = append(c.stops, stop{every, func(hashers map[string]hash.Hash, offset int64) {
+			state, err := marshalState(hashers, offset)
+			if err != nil {
+				state = nil
+			}
+			fn(offset, state)
+		}})
+	}
+}
+
+// split passes p, which follows the first n bytes of the stream, to hashSome,
+// stopping at every multiple of the intervals of stops in p.
+func split(stops []stop, hashers map[string]hash.Hash, n int64, p []byte, hashSome func([]byte)) {
+	for len(p) > 0 {
+		next := int64(-1)
+		for _, s := range stops {
+			if at := (n/s.every + 1) * s.every; next < 0 || at < next {
+				next = at
+			}
+		}
+		if n+int64(len(p)) < next {
+			hashSome(p)
+			return
+		}
+		k := next - n
+		hashSome(p[:k])
+		n, p = next, p[k:]
+
+		for _, s := range stops {
+			if n%s.every == 0 {
+				s.at(hashers, n)
+			}
+		}
+	}
+}
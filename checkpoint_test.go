@@ -0,0 +1,69 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWithCheckpoint(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	type checkpointed struct {
+		offset int64
+		state  []byte
+	}
+	var got []checkpointed
+	record := func(offset int64, state []byte) {
+		got = append(got, checkpointed{offset, state})
+	}
+
+	// Read in pieces that don't line up with the checkpoints.
+	hr := NewReader(&onlyReader{bytes.NewReader(contents)}, WithSHA256(), WithMD5(), WithCheckpoint(32, record))
+	buf := make([]byte, 45)
+	if _, err := io.CopyBuffer(io.Discard, struct{ io.Reader }{hr}, buf); err != nil {
+		t.Fatalf("io.CopyBuffer(): %v", err)
+	}
+	if want := len(contents) / 32; len(got) != want {
+		t.Fatalf("WithCheckpoint(32) called back %d times for %d bytes, wanted %d", len(got), len(contents), want)
+	}
+
+	for i, c := range got {
+		if want := int64(32 * (i + 1)); c.offset != want {
+			t.Errorf("checkpoint %d got offset: %d, wanted %d", i, c.offset, want)
+		}
+		// Every state resumes to the digest of the whole file.
+		w := NewWriter(nil, WithSHA256(), WithMD5())
+		if err := w.UnmarshalBinary(c.state); err != nil {
+			t.Fatalf("HashWriter.UnmarshalBinary(checkpoint %d): %v", i, err)
+		}
+		w.Write(contents[c.offset:])
+		if got := w.HexHash(SHA256); got != dataFileSHA256 {
+			t.Errorf("resumed from checkpoint %d, HexHash(sha256) got: %q, wanted %q", i, got, dataFileSHA256)
+		}
+	}
+	if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+
+	// A HashWriter checkpoints the same way, and a hash that can't be saved
+	// gives nil states.
+	got = nil
+	hw := NewWriter(nil, WithHasher("etag", NewS3ETag(0)), WithCheckpoint(50, record), WithPipelining())
+	hw.Write(contents)
+	if len(got) != len(contents)/50 || got[0].offset != 50 || got[0].state != nil {
+		t.Errorf("HashWriter with WithCheckpoint(50) called back with %v", got)
+	}
+
+	// Checkpoints are disabled by a non-positive interval.
+	got = nil
+	hw = NewWriter(nil, WithSHA256(), WithCheckpoint(0, record))
+	hw.Write(contents)
+	if len(got) != 0 {
+		t.Errorf("WithCheckpoint(0) called back %d times, wanted none", len(got))
+	}
+}
@@ -0,0 +1,102 @@
+package hashio
+
+import (
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+
+	"github.com/mikewiacek/hashio/xxhash"
+)
+
+var (
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+	crc64ISO   = crc64.MakeTable(crc64.ISO)
+	crc64ECMA  = crc64.MakeTable(crc64.ECMA)
+)
+
+func newCRC32() hash.Hash { return crc32.NewIEEE() }
+
+func newCRC32C() hash.Hash { return crc32.New(castagnoli) }
+
+func newCRC64ISO() hash.Hash { return crc64.New(crc64ISO) }
+
+func newCRC64ECMA() hash.Hash { return crc64.New(crc64ECMA) }
+
+func newAdler32() hash.Hash { return adler32.New() }
+
+func newXXH64() hash.Hash { return xxhash.New64() }
+
+func newXXH3() hash.Hash { return xxhash.New3() }
+
+func newFNV1a32() hash.Hash { return fnv.New32a() }
+
+func newFNV1a64() hash.Hash { return fnv.New64a() }
+
+func newFNV1a128() hash.Hash { return fnv.New128a() }
+
+// Sum32 returns the checksum identified by name as an integer, for 32-bit
+// checksums such as CRC32, CRC32C, Adler32 and FNV1a32 whose values are usually
+// handled as a uint32 rather than as bytes. An error is returned in the same
+// cases as LookupHash, or if the hash doesn't implement hash.Hash32.
+func (h *HashReader) Sum32(name string) (uint32, error) {
+	if _, err := h.LookupHash(name); err != nil {
+		return 0, err
+	}
+	return sum32(h.hashers, name)
+}
+
+// Sum64 returns the checksum identified by name as an integer, for 64-bit
+// checksums and hashes such as CRC64ISO, XXH64, XXH3 and FNV1a64, for
+// instance to pick a shard. An error is returned in the same
+// cases as LookupHash, or if the hash doesn't implement hash.Hash64.
+func (h *HashReader) Sum64(name string) (uint64, error) {
+	if _, err := h.LookupHash(name); err != nil {
+		return 0, err
+	}
+	return sum64(h.hashers, name)
+}
+
+// Sum32 returns the checksum identified by name as an integer, for 32-bit
+// checksums such as CRC32, CRC32C, Adler32 and FNV1a32 whose values are usually
+// handled as a uint32 rather than as bytes. An error is returned in the same
+// cases as LookupHash, or if the hash doesn't implement hash.Hash32.
+func (h *HashWriter) Sum32(name string) (uint32, error) {
+	if _, err := h.LookupHash(name); err != nil {
+		return 0, err
+	}
+	return sum32(h.hashers, name)
+}
+
+// Sum64 returns the checksum identified by name as an integer, for 64-bit
+// checksums and hashes such as CRC64ISO, XXH64, XXH3 and FNV1a64, for
+// instance to pick a shard. An error is returned in the same
+// cases as LookupHash, or if the hash doesn't implement hash.Hash64.
+func (h *HashWriter) Sum64(name string) (uint64, error) {
+	if _, err := h.LookupHash(name); err != nil {
+		return 0, err
+	}
+	return sum64(h.hashers, name)
+}
+
+// sum32 returns the value of the hash.Hash32 hashers[name], which must exist
+// and be finalized.
+func sum32(hashers map[string]hash.Hash, name string) (uint32, error) {
+	h, ok := unwrapHash(hashers[name]).(hash.Hash32)
+	if !ok {
+		return 0, fmt.Errorf("hashio: the %s hash is not a 32-bit checksum", name)
+	}
+	return h.Sum32(), nil
+}
+
+// sum64 returns the value of the hash.Hash64 hashers[name], which must exist
+// and be finalized.
+func sum64(hashers map[string]hash.Hash, name string) (uint64, error) {
+	h, ok := unwrapHash(hashers[name]).(hash.Hash64)
+	if !ok {
+		return 0, fmt.Errorf("hashio: the %s hash is not a 64-bit checksum", name)
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,86 @@
+package hashio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	// The check values of the algorithms, their checksum of "123456789".
+	r, err := NewByNames(strings.NewReader("123456789"), CRC32, CRC32C, CRC64ISO, CRC64ECMA, Adler32, SHA256)
+	if err != nil {
+		t.Fatalf("NewByNames(): %v", err)
+	}
+	io.ReadAll(r)
+	for name, want := range map[string]uint32{CRC32: 0xcbf43926, CRC32C: 0xe3069283, Adler32: 0x091e01de} {
+		if got, err := r.Sum32(name); err != nil || got != want {
+			t.Errorf("HashReader.Sum32(%s) got: (%#x, %v), wanted (%#x, nil)", name, got, err, want)
+		}
+	}
+	for name, want := range map[string]uint64{CRC64ISO: 0xb90956c775a41001, CRC64ECMA: 0x995dc9bbdf1939fa} {
+		if got, err := r.Sum64(name); err != nil || got != want {
+			t.Errorf("HashReader.Sum64(%s) got: (%#x, %v), wanted (%#x, nil)", name, got, err, want)
+		}
+	}
+	if got, want := r.HexHash(CRC32), "cbf43926"; got != want {
+		t.Errorf("HashReader.HexHash(crc32) got: %q, wanted %q", got, want)
+	}
+
+	if _, err := r.Sum32(SHA256); err == nil {
+		t.Errorf("HashReader.Sum32(sha256) got no error")
+	}
+	if _, err := r.Sum64(CRC32); err == nil {
+		t.Errorf("HashReader.Sum64(crc32) got no error")
+	}
+	var unknown *UnknownHashError
+	if _, err := r.Sum32("missing"); !errors.As(err, &unknown) {
+		t.Errorf("HashReader.Sum32(missing) got: %v, wanted an *UnknownHashError", err)
+	}
+}
+
+func TestChecksumsHashWriter(t *testing.T) {
+	w, err := NewWriterByNames(nil, CRC32C, CRC64ECMA)
+	if err != nil {
+		t.Fatalf("NewWriterByNames(): %v", err)
+	}
+	w.WriteString("1234")
+	w.WriteString("56789")
+	if got, err := w.Sum32(CRC32C); err != nil || got != 0xe3069283 {
+		t.Errorf("HashWriter.Sum32(crc32c) got: (%#x, %v), wanted (0xe3069283, nil)", got, err)
+	}
+	if got, err := w.Sum64(CRC64ECMA); err != nil || got != 0x995dc9bbdf1939fa {
+		t.Errorf("HashWriter.Sum64(crc64-ecma) got: (%#x, %v), wanted (0x995dc9bbdf1939fa, nil)", got, err)
+	}
+
+	boom := errors.New("boom")
+	w = NewWriter(&errWriter{0, boom}, WithHasher(CRC32, newCRC32()))
+	w.WriteString("abc")
+	if _, err := w.Sum32(CRC32); !errors.Is(err, ErrStreamFailed) {
+		t.Errorf("HashWriter.Sum32() after error got: %v, wanted ErrStreamFailed", err)
+	}
+}
+
+func TestFastHashes(t *testing.T) {
+	// One pass for an integrity hash and sharding keys.
+	r, err := NewByNames(strings.NewReader("abc"), SHA256, XXH64, XXH3, FNV1a32, FNV1a64, FNV1a128)
+	if err != nil {
+		t.Fatalf("NewByNames(): %v", err)
+	}
+	io.ReadAll(r)
+	for name, want := range map[string]uint64{XXH64: 0x44bc2cf5ad770999, XXH3: 0x78af5f94892f3950, FNV1a64: 0xe71fa2190541574b} {
+		if got, err := r.Sum64(name); err != nil || got != want {
+			t.Errorf("HashReader.Sum64(%s) got: (%#x, %v), wanted (%#x, nil)", name, got, err, want)
+		}
+	}
+	if got, err := r.Sum32(FNV1a32); err != nil || got != 0x1a47e90b {
+		t.Errorf("HashReader.Sum32(fnv1a-32) got: (%#x, %v), wanted (0x1a47e90b, nil)", got, err)
+	}
+	if got, want := r.HexHash(FNV1a128), "a68d622cec8b5822836dbc7977af7f3b"; got != want {
+		t.Errorf("HashReader.HexHash(fnv1a-128) got: %q, wanted %q", got, want)
+	}
+	if got, want := r.HexHash(SHA256), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"; got != want {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package checksums
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// bsdTags maps hashio algorithms to the tags naming them in the BSD format,
+// as written by coreutils with --tag, macOS shasum and the FreeBSD tools.
+var bsdTags = map[string]string{
+	hashio.MD5:         "MD5",
+	hashio.SHA1:        "SHA1",
+	hashio.SHA256:      "SHA256",
+	hashio.SHA384:      "SHA384",
+	hashio.SHA512:      "SHA512",
+	hashio.SHA512_256:  "SHA512t256",
+	hashio.SHA3_256:    "SHA3-256",
+	hashio.SHA3_384:    "SHA3-384",
+	hashio.SHA3_512:    "SHA3-512",
+	hashio.BLAKE2b_256: "BLAKE2b-256",
+	hashio.BLAKE2b_384: "BLAKE2b-384",
+	hashio.BLAKE2b_512: "BLAKE2b",
+	hashio.BLAKE3:      "BLAKE3",
+	"ripemd160":        "RMD160",
+	"sm3":              "SM3",
+}
+
+// bsdAlgorithms is the inverse of bsdTags.
+var bsdAlgorithms = func() map[string]string {
+	m := make(map[string]string, len(bsdTags))
+	for alg, tag := range bsdTags {
+		m[tag] = alg
+	}
+	return m
+}()
+
+// bsdTag returns the tag of alg in the BSD format: the one of the tools
+// above, or alg in uppercase.
+func bsdTag(alg string) string {
+	if tag, ok := bsdTags[alg]; ok {
+		return tag
+	}
+	return strings.ToUpper(alg)
+}
+
+// bsdAlgorithm returns the hashio algorithm named by tag in the BSD format,
+// reversing bsdTag.
+func bsdAlgorithm(tag string) string {
+	if alg, ok := bsdAlgorithms[tag]; ok {
+		return alg
+	}
+	return strings.ToLower(tag)
+}
+
+// parseBSD parses a line in the BSD format.
+func parseBSD(line string) (Entry, bool) {
+	escaped := strings.HasPrefix(line, `\`)
+	if escaped {
+		line = line[1:]
+	}
+	tag, rest, ok := strings.Cut(line, " (")
+	if !ok || tag == "" || strings.Contains(tag, " ") {
+		return Entry{}, false
+	}
+	// The name may itself contain ") = ", so the digest follows the last one.
+	i := strings.LastIndex(rest, ") = ")
+	if i < 0 {
+		return Entry{}, false
+	}
+	name := rest[:i]
+	sum, err := hex.DecodeString(rest[i+len(") = "):])
+	if err != nil || len(sum) == 0 || name == "" {
+		return Entry{}, false
+	}
+	if escaped {
+		if name, ok = unescape(name); !ok {
+			return Entry{}, false
+		}
+	}
+	return Entry{Name: name, Algorithm: bsdAlgorithm(tag), Sum: sum, Binary: true}, true
+}
+
+// WriteBSD writes entries to w in the BSD format, as "sha256sum --tag", macOS
+// shasum --tag and the FreeBSD sha256 tool do:
+//
+//	SHA256 (foo.txt) = b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c
+//
+// Each line names the algorithm of its entry, so entries of different
+// algorithms can be mixed. Names are escaped like by WriteGNU. The format has
+// no text mode, so Binary is ignored when writing, and set by Parse.
+func WriteBSD(w io.Writer, entries []Entry) error {
+	var b []byte
+	for _, e := range entries {
+		name, escaped := escape(e.Name)
+		if escaped {
+			b = append(b, '\\')
+		}
+		b = append(b, bsdTag(e.Algorithm)...)
+		b = append(b, " ("...)
+		b = append(b, name...)
+		b = append(b, ") = "...)
+		b = hex.AppendEncode(b, e.Sum)
+		b = append(b, '\n')
+	}
+	_, err := w.Write(b)
+	return err
+}
@@ -0,0 +1,97 @@
+package checksums
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// sha256sumTagOutput is the output of sha256sum --tag (GNU coreutils) 9.1 for
+// the files of testFS.
+const sha256sumTagOutput = "SHA256 (foo.txt) = b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c\n" +
+	`\SHA256 (a\\b.bin) = fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9` + "\n" +
+	`\SHA256 (new\nline) = 2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881` + "\n"
+
+func TestWriteBSD(t *testing.T) {
+	entries, err := Sum(testFS, hashio.SHA256, "foo.txt", `a\b.bin`, "new\nline")
+	if err != nil {
+		t.Fatalf("Sum(): %v", err)
+	}
+	var b bytes.Buffer
+	if err := WriteBSD(&b, entries); err != nil {
+		t.Fatalf("WriteBSD(): %v", err)
+	}
+	if got := b.String(); got != sha256sumTagOutput {
+		t.Errorf("WriteBSD() got: %q, wanted %q", got, sha256sumTagOutput)
+	}
+
+	b.Reset()
+	WriteBSD(&b, []Entry{
+		{Name: "foo.txt", Algorithm: hashio.BLAKE2b_256, Sum: mustDecode("20590a52c4f00588c500328b16d466c982a26fabaa5fa4dcc83052dd0a84f233")},
+		{Name: "foo.txt", Algorithm: hashio.XXH64, Sum: mustDecode("0123456789abcdef")},
+	})
+	want := "BLAKE2b-256 (foo.txt) = 20590a52c4f00588c500328b16d466c982a26fabaa5fa4dcc83052dd0a84f233\n" +
+		"XXH64 (foo.txt) = 0123456789abcdef\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteBSD() of BLAKE2b-256 and XXH64 got: %q, wanted %q", got, want)
+	}
+}
+
+func TestParseBSD(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sha256sumTagOutput), "")
+	if err != nil {
+		t.Fatalf("Parse(): %v", err)
+	}
+	want := []Entry{
+		{Name: "foo.txt", Algorithm: hashio.SHA256, Sum: mustDecode("b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"), Binary: true},
+		{Name: `a\b.bin`, Algorithm: hashio.SHA256, Sum: mustDecode("fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"), Binary: true},
+		{Name: "new\nline", Algorithm: hashio.SHA256, Sum: mustDecode("2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881"), Binary: true},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() got: %+v, wanted %+v", entries, want)
+	}
+
+	// Lines of md5 (macOS), b2sum --tag and md5sum mixed in one file, with a
+	// name containing ") = ".
+	entries, err = Parse(strings.NewReader(
+		"MD5 (foo.txt) = d3b07384d113edec49eaa6238ad5ff00\n"+
+			"BLAKE2b-256 (a) = b) = 20590a52c4f00588c500328b16d466c982a26fabaa5fa4dcc83052dd0a84f233\n"+
+			"d3b07384d113edec49eaa6238ad5ff00  bar (1).txt\n"), "")
+	if err != nil {
+		t.Fatalf("Parse() of mixed lines: %v", err)
+	}
+	want = []Entry{
+		{Name: "foo.txt", Algorithm: hashio.MD5, Sum: mustDecode("d3b07384d113edec49eaa6238ad5ff00"), Binary: true},
+		{Name: "a) = b", Algorithm: hashio.BLAKE2b_256, Sum: mustDecode("20590a52c4f00588c500328b16d466c982a26fabaa5fa4dcc83052dd0a84f233"), Binary: true},
+		{Name: "bar (1).txt", Algorithm: hashio.MD5, Sum: mustDecode("d3b07384d113edec49eaa6238ad5ff00")},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() of mixed lines got: %+v, wanted %+v", entries, want)
+	}
+
+	// A BSD line names its algorithm whatever the one given.
+	entries, err = Parse(strings.NewReader("MD5 (foo.txt) = d3b07384d113edec49eaa6238ad5ff00\n"), hashio.SHA256)
+	if err != nil || len(entries) != 1 || entries[0].Algorithm != hashio.MD5 {
+		t.Errorf("Parse(sha256) of an MD5 line got: %+v, %v, wanted an MD5 entry", entries, err)
+	}
+
+	if _, err := Parse(strings.NewReader("NOPE (foo.txt) = d3b07384d113edec49eaa6238ad5ff00\n"), ""); !errors.Is(err, hashio.ErrUnknownHash) {
+		t.Errorf("Parse() of an unknown tag got: %v, wanted %v", err, hashio.ErrUnknownHash)
+	}
+	for _, line := range []string{
+		"SHA256 (foo.txt) = d3b07384d113edec49eaa6238ad5ff00",
+		"MD5 (foo.txt) = ",
+		"MD5 () = d3b07384d113edec49eaa6238ad5ff00",
+		"MD5 (foo.txt) d3b07384d113edec49eaa6238ad5ff00",
+		`\MD5 (foo\t.txt) = d3b07384d113edec49eaa6238ad5ff00`,
+	} {
+		var perr *ParseError
+		if _, err := Parse(strings.NewReader(line+"\n"), ""); !errors.As(err, &perr) || perr.Line != 1 {
+			t.Errorf("Parse(%q) got: %v, wanted a *ParseError for line 1", line, err)
+		}
+	}
+}
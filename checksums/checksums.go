@@ -0,0 +1,144 @@
+// Package checksums reads, writes and verifies checksum files, the lists of
+// file digests shipped alongside release artifacts, in the format of the GNU
+// coreutils tools sha256sum, sha1sum, md5sum and friends:
+//
+//	2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae  foo.txt
+//	fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9 *bar.bin
+//
+// and in the BSD format of macOS and the BSDs, also written by the coreutils
+// tools with --tag:
+//
+//	SHA256 (foo.txt) = 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+//
+// SFV files of CRC32s are also supported, see ParseSFV.
+//
+// Digests are computed with hashio, so any algorithm registered with it can
+// be used.
+package checksums
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// Entry is the digest of one file in a checksum file.
+type Entry struct {
+	// Name is the name of the file, unescaped.
+	Name string
+	// Algorithm is the hashio name of the algorithm of Sum, such as
+	// hashio.SHA256.
+	Algorithm string
+	Sum       []byte
+	// Binary is set for files hashed in binary mode, marked with a "*" by
+	// coreutils. It only matters on systems that distinguish text files.
+	Binary bool
+}
+
+// ParseError is returned for an improperly formatted line of a checksum file.
+type ParseError struct {
+	Line int // 1-based
+	Text string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("checksums: line %d: improperly formatted checksum line %q", e.Line, e.Text)
+}
+
+// Sum hashes the files of fsys with the given names, with the registered
+// hashio algorithm alg, and returns their entries in the same order. An error
+// is returned if alg isn't registered or a file can't be read.
+func Sum(fsys fs.FS, alg string, names ...string) ([]Entry, error) {
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		sum, err := sumFile(fsOpen(fsys), name, alg)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Name: name, Algorithm: alg, Sum: sum})
+	}
+	return entries, nil
+}
+
+// sumFile returns the digest with alg of the file opened by open.
+func sumFile(open func(string) (io.ReadCloser, error), name, alg string) ([]byte, error) {
+	f, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := hashio.NewByNames(f, alg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return r.LookupHash(alg)
+}
+
+// Result is the outcome of verifying one Entry.
+type Result struct {
+	Entry
+	// OK reports whether the file has the digest of the entry.
+	OK bool
+	// Err is the error opening or reading the file, or that of an algorithm
+	// that isn't registered. OK is false if it is set.
+	Err error
+}
+
+// String returns r as reported by "sha256sum --check": the name of the file
+// followed by ": OK", ": FAILED" or ": FAILED open or read".
+func (r Result) String() string {
+	switch {
+	case r.Err != nil:
+		return r.Name + ": FAILED open or read"
+	case !r.OK:
+		return r.Name + ": FAILED"
+	}
+	return r.Name + ": OK"
+}
+
+// Verify hashes the file of every entry, opened with open, and reports whether
+// it matches, in the order of entries. open can serve files from anywhere,
+// such as an archive or a map of readers.
+func Verify(entries []Entry, open func(name string) (io.ReadCloser, error)) []Result {
+	results := make([]Result, len(entries))
+	for i, e := range entries {
+		results[i].Entry = e
+		sum, err := sumFile(open, e.Name, e.Algorithm)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].OK = hashio.Digest{Algorithm: e.Algorithm, Sum: sum}.Equal(hashio.Digest{Algorithm: e.Algorithm, Sum: e.Sum})
+	}
+	return results
+}
+
+// VerifyFS is like Verify but opens the files from fsys, usually a directory
+// opened with os.DirFS. Names are cleaned, so "./foo" matches foo, as in
+// checksum files listing paths relative to the current directory.
+func VerifyFS(fsys fs.FS, entries []Entry) []Result {
+	return Verify(entries, fsOpen(fsys))
+}
+
+// fsOpen returns a function opening the files of fsys by cleaned name.
+func fsOpen(fsys fs.FS) func(string) (io.ReadCloser, error) {
+	return func(name string) (io.ReadCloser, error) {
+		return fsys.Open(path.Clean(name))
+	}
+}
+
+// AllOK reports whether every file of results was verified.
+func AllOK(results []Result) bool {
+	for _, r := range results {
+		if !r.OK {
+			return false
+		}
+	}
+	return true
+}
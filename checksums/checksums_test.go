@@ -0,0 +1,143 @@
+package checksums
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mikewiacek/hashio"
+)
+
+var testFS = fstest.MapFS{
+	"foo.txt":   {Data: []byte("foo\n")},
+	`a\b.bin`:   {Data: []byte("bar")},
+	"new\nline": {Data: []byte("x")},
+}
+
+// sha256sumOutput is the output of sha256sum (GNU coreutils) 9.1 for the
+// files of testFS.
+const sha256sumOutput = "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c  foo.txt\n" +
+	`\fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9  a\\b.bin` + "\n" +
+	`\2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881  new\nline` + "\n"
+
+func mustDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSumAndWriteGNU(t *testing.T) {
+	entries, err := Sum(testFS, hashio.SHA256, "foo.txt", `a\b.bin`, "new\nline")
+	if err != nil {
+		t.Fatalf("Sum(): %v", err)
+	}
+	var b bytes.Buffer
+	if err := WriteGNU(&b, entries); err != nil {
+		t.Fatalf("WriteGNU(): %v", err)
+	}
+	if got := b.String(); got != sha256sumOutput {
+		t.Errorf("WriteGNU() got: %q, wanted %q", got, sha256sumOutput)
+	}
+
+	entries[0].Binary = true
+	b.Reset()
+	WriteGNU(&b, entries[:1])
+	if got, want := b.String(), "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c *foo.txt\n"; got != want {
+		t.Errorf("WriteGNU() of a binary entry got: %q, wanted %q", got, want)
+	}
+
+	if _, err := Sum(testFS, hashio.SHA256, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Sum(missing) got: %v, wanted %v", err, fs.ErrNotExist)
+	}
+	if _, err := Sum(testFS, "nope", "foo.txt"); !errors.Is(err, hashio.ErrUnknownHash) {
+		t.Errorf("Sum(nope) got: %v, wanted %v", err, hashio.ErrUnknownHash)
+	}
+}
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sha256sumOutput), "")
+	if err != nil {
+		t.Fatalf("Parse(): %v", err)
+	}
+	want := []Entry{
+		{Name: "foo.txt", Algorithm: hashio.SHA256, Sum: mustDecode("b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c")},
+		{Name: `a\b.bin`, Algorithm: hashio.SHA256, Sum: mustDecode("fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9")},
+		{Name: "new\nline", Algorithm: hashio.SHA256, Sum: mustDecode("2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881")},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() got: %+v, wanted %+v", entries, want)
+	}
+
+	// md5sum output with Windows line endings, uppercase hex and a blank line.
+	entries, err = Parse(strings.NewReader("D3B07384D113EDEC49EAA6238AD5FF00 *./foo.txt\r\n\r\n"), "")
+	if err != nil {
+		t.Fatalf("Parse() of md5sum output: %v", err)
+	}
+	want = []Entry{{Name: "./foo.txt", Algorithm: hashio.MD5, Sum: mustDecode("d3b07384d113edec49eaa6238ad5ff00"), Binary: true}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() of md5sum output got: %+v, wanted %+v", entries, want)
+	}
+
+	// The algorithm given decides the length of the digests.
+	if entries, err := Parse(strings.NewReader("d3b07384d113edec49eaa6238ad5ff00  foo.txt\n"), hashio.BLAKE2b_256); err == nil {
+		t.Errorf("Parse(blake2b-256) of an MD5 digest got: %+v, wanted an error", entries)
+	}
+	if _, err := Parse(strings.NewReader(""), "nope"); !errors.Is(err, hashio.ErrUnknownHash) {
+		t.Errorf("Parse(nope) got: %v, wanted %v", err, hashio.ErrUnknownHash)
+	}
+
+	for _, line := range []string{
+		"d3b07384d113edec49eaa6238ad5ff00",
+		"d3b07384d113edec49eaa6238ad5ff00 foo.txt",
+		"d3b07384d113edec49eaa6238ad5ff00  ",
+		"d3b07384d113edec49eaa6238ad5ff0  foo.txt",
+		"d3b07384d113edec49eaa6238ad5ff  foo.txt",
+		`\d3b07384d113edec49eaa6238ad5ff00  foo\t.txt`,
+		`\d3b07384d113edec49eaa6238ad5ff00  foo\`,
+	} {
+		var perr *ParseError
+		if _, err := Parse(strings.NewReader("b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c  foo.txt\n"+line+"\n"), ""); !errors.As(err, &perr) || perr.Line != 2 {
+			t.Errorf("Parse(%q) got: %v, wanted a *ParseError for line 2", line, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sha256sumOutput+
+		"0000000000000000000000000000000000000000000000000000000000000000  ./foo.txt\n"+
+		"b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c  missing\n"), "")
+	if err != nil {
+		t.Fatalf("Parse(): %v", err)
+	}
+	results := VerifyFS(testFS, entries)
+	var got []string
+	for _, r := range results {
+		got = append(got, r.String())
+	}
+	want := []string{"foo.txt: OK", `a\b.bin: OK`, "new\nline: OK", "./foo.txt: FAILED", "missing: FAILED open or read"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyFS() got: %q, wanted %q", got, want)
+	}
+	if AllOK(results) {
+		t.Errorf("AllOK() got: true, wanted false")
+	}
+	if !AllOK(results[:3]) {
+		t.Errorf("AllOK() of the matching files got: false, wanted true")
+	}
+
+	readers := map[string]string{"foo.txt": "foo\n"}
+	results = Verify(entries[:1], func(name string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(readers[name])), nil
+	})
+	if !AllOK(results) {
+		t.Errorf("Verify() with readers got: %v, wanted OK", results)
+	}
+}
@@ -0,0 +1,175 @@
+package checksums
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// Algorithms inferred from the length of the hex digests of checksum files
+// parsed without an algorithm, as the coreutils tools are named.
+var algorithmsByHexLen = map[int]string{
+	32:  hashio.MD5,
+	40:  hashio.SHA1,
+	64:  hashio.SHA256,
+	96:  hashio.SHA384,
+	128: hashio.SHA512,
+}
+
+// Parse reads a checksum file in the format written by sha256sum and the
+// other coreutils tools, or in the BSD format (see WriteBSD), or a mix of
+// both. alg is the hashio name of the algorithm of the digests of lines in
+// the coreutils format; if it is empty, it is inferred from their length:
+// MD5, SHA1, SHA256, SHA384 or SHA512, like md5sum through sha512sum. Lines
+// in the BSD format name their own algorithm. Both lowercase and uppercase
+// hex are accepted, as are Windows line endings; blank lines are skipped.
+//
+// A *ParseError is returned for the first line that isn't a valid entry, and a
+// *hashio.UnknownHashError if alg or the algorithm of a BSD line isn't
+// registered.
+func Parse(r io.Reader, alg string) ([]Entry, error) {
+	sizes := make(map[string]int)
+	if alg != "" {
+		if _, err := digestSize(sizes, alg); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []Entry
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		e, ok := parseBSD(line)
+		if !ok {
+			if e, ok = parseGNU(line); ok {
+				e.Algorithm = alg
+				if alg == "" {
+					e.Algorithm, ok = algorithmsByHexLen[2*len(e.Sum)]
+				}
+			}
+		}
+		if ok {
+			size, err := digestSize(sizes, e.Algorithm)
+			if err != nil {
+				return nil, err
+			}
+			ok = len(e.Sum) == size
+		}
+		if !ok {
+			return nil, &ParseError{Line: n, Text: line}
+		}
+		entries = append(entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// digestSize returns the size of the digests of alg, caching it in sizes.
+func digestSize(sizes map[string]int, alg string) (int, error) {
+	if size, ok := sizes[alg]; ok {
+		return size, nil
+	}
+	sums, err := hashio.HashBytes(nil, alg)
+	if err != nil {
+		return 0, err
+	}
+	sizes[alg] = len(sums[alg])
+	return sizes[alg], nil
+}
+
+// parseGNU parses a line in the coreutils format, without setting the
+// algorithm of the entry.
+func parseGNU(line string) (Entry, bool) {
+	escaped := strings.HasPrefix(line, `\`)
+	if escaped {
+		line = line[1:]
+	}
+	h, rest, ok := strings.Cut(line, " ")
+	if !ok || len(rest) < 2 || (rest[0] != ' ' && rest[0] != '*') {
+		return Entry{}, false
+	}
+	sum, err := hex.DecodeString(h)
+	if err != nil || len(sum) == 0 {
+		return Entry{}, false
+	}
+	name := rest[1:]
+	if escaped {
+		if name, ok = unescape(name); !ok {
+			return Entry{}, false
+		}
+	}
+	return Entry{Name: name, Sum: sum, Binary: rest[0] == '*'}, true
+}
+
+// WriteGNU writes entries to w in the format of sha256sum and the other
+// coreutils tools, which their --check option reads back. Names containing a
+// backslash or a line break are escaped as coreutils does. The algorithms of
+// the entries aren't recorded, so they should all be the same.
+func WriteGNU(w io.Writer, entries []Entry) error {
+	var b []byte
+	for _, e := range entries {
+		name, escaped := escape(e.Name)
+		if escaped {
+			b = append(b, '\\')
+		}
+		b = hex.AppendEncode(b, e.Sum)
+		b = append(b, ' ')
+		if e.Binary {
+			b = append(b, '*')
+		} else {
+			b = append(b, ' ')
+		}
+		b = append(b, name...)
+		b = append(b, '\n')
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+var nameEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+
+// escape escapes name as coreutils does, reporting whether it had to.
+func escape(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n\r") {
+		return name, false
+	}
+	return nameEscaper.Replace(name), true
+}
+
+// unescape reverses escape, reporting false for an invalid escape sequence.
+func unescape(name string) (string, bool) {
+	if !strings.Contains(name, `\`) {
+		return name, true
+	}
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i == len(name) {
+			return "", false
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
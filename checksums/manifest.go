@@ -0,0 +1,35 @@
+package checksums
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// FromManifest returns the entries of the files of m, as returned by
+// hashio.HashDir, with their digests of the algorithm alg, ordered by path, so
+// that m can be written by WriteGNU or WriteBSD and checked by sha256sum and
+// the like. The sizes, times and ignore patterns of m are lost.
+//
+// An error is returned if alg isn't an algorithm of m, or if a file of m has
+// no digest of it.
+func FromManifest(m *hashio.Manifest, alg string) ([]Entry, error) {
+	if !slices.Contains(m.Algorithms, alg) {
+		return nil, fmt.Errorf("checksums: manifest has no %s digests, only %v", alg, m.Algorithms)
+	}
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	entries := make([]Entry, len(names))
+	for i, name := range names {
+		sum, ok := m.Files[name].Sums[alg]
+		if !ok {
+			return nil, fmt.Errorf("checksums: manifest file %q has no %s digest", name, alg)
+		}
+		entries[i] = Entry{Name: name, Algorithm: alg, Sum: sum, Binary: true}
+	}
+	return entries, nil
+}
@@ -0,0 +1,39 @@
+package checksums
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+func TestFromManifest(t *testing.T) {
+	m, err := hashio.HashDir(testFS, hashio.SHA256)
+	if err != nil {
+		t.Fatalf("hashio.HashDir(): %v", err)
+	}
+	entries, err := FromManifest(m, hashio.SHA256)
+	if err != nil {
+		t.Fatalf("FromManifest(): %v", err)
+	}
+	var b bytes.Buffer
+	WriteBSD(&b, entries)
+	// The same lines as sha256sum --tag, ordered by path.
+	want := `\SHA256 (a\\b.bin) = fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9` + "\n" +
+		"SHA256 (foo.txt) = b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c\n" +
+		`\SHA256 (new\nline) = 2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteBSD(FromManifest()) got: %q, wanted %q", got, want)
+	}
+	if results := VerifyFS(testFS, entries); !AllOK(results) {
+		t.Errorf("VerifyFS(FromManifest()) got: %v, wanted every file OK", results)
+	}
+
+	if entries, err := FromManifest(m, hashio.MD5); err == nil {
+		t.Errorf("FromManifest(md5) of a SHA256 manifest got: %+v, wanted an error", entries)
+	}
+	delete(m.Files["foo.txt"].Sums, hashio.SHA256)
+	if entries, err := FromManifest(m, hashio.SHA256); err == nil {
+		t.Errorf("FromManifest() of a file without digest got: %+v, wanted an error", entries)
+	}
+}
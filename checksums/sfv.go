@@ -0,0 +1,69 @@
+package checksums
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// ParseSFV reads a Simple File Verification (.sfv) file, the checksum files
+// of legacy release and archival tools, listing the CRC32 of every file after
+// its name:
+//
+//	; comment
+//	foo.txt 7E3265A8
+//
+// Entries have the algorithm hashio.CRC32, so they can be checked with Verify
+// like any other. Lines starting with ";" are comments; they and blank lines
+// are skipped. Both lowercase and uppercase hex are accepted, as are Windows
+// line endings. A *ParseError is returned for the first line that isn't a
+// valid entry.
+func ParseSFV(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		// Names may contain spaces, so the CRC is the last field.
+		i := strings.LastIndexAny(line, " \t")
+		name := strings.TrimRight(line[:max(i, 0)], " \t")
+		sum, err := hex.DecodeString(line[i+1:])
+		if i < 0 || name == "" || err != nil || len(sum) != 4 {
+			return nil, &ParseError{Line: n, Text: line}
+		}
+		entries = append(entries, Entry{Name: name, Algorithm: hashio.CRC32, Sum: sum})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WriteSFV writes entries to w in the SFV format, with the CRCs in uppercase
+// hex as most tools write them. Entries must have the algorithm hashio.CRC32,
+// as returned by Sum with it, and names can't contain line breaks, which the
+// format can't escape; an error is returned otherwise, before anything is
+// written.
+func WriteSFV(w io.Writer, entries []Entry) error {
+	var b []byte
+	for _, e := range entries {
+		if e.Algorithm != hashio.CRC32 || len(e.Sum) != 4 {
+			return fmt.Errorf("checksums: SFV entry %q isn't a CRC32", e.Name)
+		}
+		if strings.ContainsAny(e.Name, "\n\r") {
+			return fmt.Errorf("checksums: SFV entry name %q contains a line break", e.Name)
+		}
+		b = append(b, e.Name...)
+		b = append(b, ' ')
+		b = append(b, strings.ToUpper(hex.EncodeToString(e.Sum))...)
+		b = append(b, '\n')
+	}
+	_, err := w.Write(b)
+	return err
+}
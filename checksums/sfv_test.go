@@ -0,0 +1,60 @@
+package checksums
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+func TestSFV(t *testing.T) {
+	entries, err := Sum(testFS, hashio.CRC32, "foo.txt", `a\b.bin`)
+	if err != nil {
+		t.Fatalf("Sum(): %v", err)
+	}
+	var b bytes.Buffer
+	if err := WriteSFV(&b, entries); err != nil {
+		t.Fatalf("WriteSFV(): %v", err)
+	}
+	want := "foo.txt 7E3265A8\n" + `a\b.bin 76FF8CAA` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteSFV() got: %q, wanted %q", got, want)
+	}
+
+	parsed, err := ParseSFV(strings.NewReader("; Generated by cksfv\r\n;\r\n" + b.String() + "\r\nmy file.txt\t7e3265a8\r\n"))
+	if err != nil {
+		t.Fatalf("ParseSFV(): %v", err)
+	}
+	wantEntries := append(entries, Entry{Name: "my file.txt", Algorithm: hashio.CRC32, Sum: mustDecode("7e3265a8")})
+	if !reflect.DeepEqual(parsed, wantEntries) {
+		t.Errorf("ParseSFV() got: %+v, wanted %+v", parsed, wantEntries)
+	}
+
+	results := VerifyFS(testFS, parsed)
+	var got []string
+	for _, r := range results {
+		got = append(got, r.String())
+	}
+	if want := []string{"foo.txt: OK", `a\b.bin: OK`, "my file.txt: FAILED open or read"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyFS() got: %q, wanted %q", got, want)
+	}
+
+	for _, line := range []string{"foo.txt", "7E3265A8", " 7E3265A8", "foo.txt 7E3265", "foo.txt 7E3265A8A8", "foo.txt 7E3265AZ"} {
+		var perr *ParseError
+		if _, err := ParseSFV(strings.NewReader("foo.txt 7E3265A8\n" + line + "\n")); !errors.As(err, &perr) || perr.Line != 2 {
+			t.Errorf("ParseSFV(%q) got: %v, wanted a *ParseError for line 2", line, err)
+		}
+	}
+
+	for _, e := range []Entry{
+		{Name: "foo.txt", Algorithm: hashio.MD5, Sum: mustDecode("d3b07384d113edec49eaa6238ad5ff00")},
+		{Name: "new\nline", Algorithm: hashio.CRC32, Sum: mustDecode("8cdc1683")},
+	} {
+		if err := WriteSFV(&b, []Entry{e}); err == nil {
+			t.Errorf("WriteSFV(%+v) got: nil, wanted an error", e)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// writeSizes records the size of every buffer Write is called with.
+type writeSizes struct {
+	sizes []int
+}
+
+func (w *writeSizes) Write(p []byte) (int, error) {
+	w.sizes = append(w.sizes, len(p))
+	return len(p), nil
+}
+
+func TestWithChunkSize(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	src := &readSizes{Reader: bytes.NewReader(contents)}
+	hw := NewWriter(io.Discard, WithSHA256(), WithBufferSize(16), WithChunkSize(48))
+	if _, err := hw.ReadFrom(src); err != nil {
+		t.Fatalf("HashWriter.ReadFrom(): %v", err)
+	}
+	if got := src.sizes[0]; got != 48 {
+		t.Errorf("HashWriter.ReadFrom() with WithChunkSize(48) read with a buffer of %d bytes, wanted 48", got)
+	}
+	if got := hw.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+
+	dst := &writeSizes{}
+	hr := NewReader(&onlyReader{bytes.NewReader(contents)}, WithSHA256(), WithChunkSize(48))
+	if _, err := hr.WriteTo(dst); err != nil {
+		t.Fatalf("HashReader.WriteTo(): %v", err)
+	}
+	for _, n := range dst.sizes {
+		if n > 48 {
+			t.Errorf("HashReader.WriteTo() with WithChunkSize(48) wrote a chunk of %d bytes", n)
+		}
+	}
+	if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+}
@@ -0,0 +1,162 @@
+// Package chunker splits a stream into content-defined chunks with the FastCDC
+// algorithm and hashes every chunk, for backup and deduplication tools.
+//
+// Because chunk boundaries depend on the content rather than on offsets,
+// inserting or removing bytes only changes the chunks around the edit, so the
+// chunk digests make good deduplication keys.
+package chunker
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+
+	"github.com/mikewiacek/hashio"
+	"github.com/mikewiacek/hashio/internal/gear"
+)
+
+// Config configures a Chunker. Sizes are in bytes; zero values select the
+// defaults.
+type Config struct {
+	// MinSize is the smallest chunk the Chunker cuts, except for the last
+	// chunk of the stream. It defaults to AvgSize/4.
+	MinSize int
+	// AvgSize is the targeted average chunk size. It is rounded down to a
+	// power of two and defaults to 8 KiB.
+	AvgSize int
+	// MaxSize is the largest chunk the Chunker cuts. It defaults to
+	// AvgSize*8.
+	MaxSize int
+	// Hashes holds the constructors of the hashes computed for every chunk,
+	// keyed by name. It defaults to SHA-256 under the name hashio.SHA256.
+	Hashes map[string]func() hash.Hash
+}
+
+// Chunk is one content-defined chunk of a stream.
+type Chunk struct {
+	// Offset is the position of the chunk's first byte in the stream.
+	Offset int64
+	// Length is the size of the chunk in bytes.
+	Length int
+	// Digests holds the digest of the chunk for every configured hash.
+	Digests map[string][]byte
+	// Data is the content of the chunk. It is only valid until the next call
+	// to Next.
+	Data []byte
+}
+
+// ErrInvalidConfig is returned by New for sizes that violate
+// 0 < MinSize <= AvgSize <= MaxSize once defaults are applied.
+var ErrInvalidConfig = errors.New("chunker: invalid chunk sizes")
+
+// minAvgSize is the smallest average chunk size accepted. The gear hash only
+// depends on the last 64 bytes, so smaller chunks are not meaningfully
+// content-defined.
+const minAvgSize = 64
+
+// Chunker reads a stream and returns its chunks one at a time. Use New to
+// create one.
+type Chunker struct {
+	r      io.Reader
+	min    int
+	avg    int
+	max    int
+	maskS  uint64 // mask used before avg, harder to match
+	maskL  uint64 // mask used after avg, easier to match
+	hasher *hashio.HashWriter
+	buf    []byte
+	start  int // start of the unconsumed data in buf
+	end    int // end of the data in buf
+	off    int64
+	err    error
+}
+
+// New returns a Chunker that splits the data read from r according to cfg.
+func New(r io.Reader, cfg Config) (*Chunker, error) {
+	avg := cfg.AvgSize
+	if avg == 0 {
+		avg = 8 << 10
+	}
+	if avg < minAvgSize {
+		return nil, ErrInvalidConfig
+	}
+	shift := bits.Len(uint(avg)) - 1
+	avg = 1 << shift
+
+	lo, hi := cfg.MinSize, cfg.MaxSize
+	if lo == 0 {
+		lo = avg / 4
+	}
+	if hi == 0 {
+		hi = avg * 8
+	}
+	if lo <= 0 || lo > avg || avg > hi {
+		return nil, ErrInvalidConfig
+	}
+
+	hashes := cfg.Hashes
+	if hashes == nil {
+		hashes = map[string]func() hash.Hash{hashio.SHA256: sha256.New}
+	}
+	return &Chunker{
+		r:   r,
+		min: lo,
+		avg: avg,
+		max: hi,
+		// Normalized chunking, level 2: two more mask bits before the
+		// average size and two fewer after it narrow the size distribution.
+		maskS:  gear.Mask(shift + 2),
+		maskL:  gear.Mask(max(shift-2, 1)),
+		hasher: hashio.NewHashWriterFromFactories(nil, hashes),
+		buf:    make([]byte, 2*hi),
+	}, nil
+}
+
+// Next returns the next chunk of the stream. After the last chunk it returns
+// io.EOF. Any other error from the underlying reader is returned once all data
+// read before it has been chunked.
+func (c *Chunker) Next() (Chunk, error) {
+	if c.end-c.start < c.max && c.err == nil {
+		c.fill()
+	}
+	if c.start == c.end {
+		if c.err == nil {
+			c.err = io.EOF
+		}
+		return Chunk{}, c.err
+	}
+
+	data := c.buf[c.start:c.end]
+	n := c.cut(data)
+	data = data[:n]
+	c.hasher.Reset(nil)
+	c.hasher.Write(data)
+	chunk := Chunk{
+		Offset:  c.off,
+		Length:  n,
+		Digests: c.hasher.Sums(),
+		Data:    data,
+	}
+	c.start += n
+	c.off += int64(n)
+	return chunk, nil
+}
+
+// fill moves the unconsumed data to the front of buf and reads until buf is
+// full or the reader fails.
+func (c *Chunker) fill() {
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+	for c.end < len(c.buf) && c.err == nil {
+		var n int
+		n, c.err = c.r.Read(c.buf[c.end:])
+		c.end += n
+	}
+}
+
+// cut returns the length of the chunk at the start of data.
+func (c *Chunker) cut(data []byte) int {
+	return gear.Cut(data, c.min, c.avg, c.max, c.maskS, c.maskL)
+}
@@ -0,0 +1,108 @@
+package chunker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+func randomData(n int, seed int64) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(b)
+	return b
+}
+
+func chunks(t *testing.T, data []byte, cfg Config) []Chunk {
+	t.Helper()
+	c, err := New(bytes.NewReader(data), cfg)
+	if err != nil {
+		t.Fatalf("New(%+v): %v", cfg, err)
+	}
+	var out []Chunk
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("Chunker.Next(): %v", err)
+		}
+		chunk.Data = bytes.Clone(chunk.Data)
+		out = append(out, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := randomData(1<<20, 1)
+	cfg := Config{MinSize: 1 << 10, AvgSize: 4 << 10, MaxSize: 16 << 10}
+	got := chunks(t, data, cfg)
+
+	var off int64
+	for i, c := range got {
+		if c.Offset != off || c.Length != len(c.Data) {
+			t.Fatalf("chunk %d got offset %d, length %d, wanted offset %d, length %d", i, c.Offset, c.Length, off, len(c.Data))
+		}
+		if !bytes.Equal(c.Data, data[off:off+int64(c.Length)]) {
+			t.Errorf("chunk %d data doesn't match the input at offset %d", i, off)
+		}
+		if c.Length > cfg.MaxSize || (c.Length < cfg.MinSize && i != len(got)-1) {
+			t.Errorf("chunk %d got length %d, wanted between %d and %d", i, c.Length, cfg.MinSize, cfg.MaxSize)
+		}
+		if want := sha256.Sum256(c.Data); !bytes.Equal(c.Digests[hashio.SHA256], want[:]) {
+			t.Errorf("chunk %d got digest %x, wanted %x", i, c.Digests[hashio.SHA256], want)
+		}
+		off += int64(c.Length)
+	}
+	if off != int64(len(data)) {
+		t.Errorf("chunks cover %d bytes, wanted %d", off, len(data))
+	}
+	if avg := len(data) / len(got); avg < 2<<10 || avg > 8<<10 {
+		t.Errorf("average chunk size got %d, wanted around %d", avg, cfg.AvgSize)
+	}
+}
+
+func TestChunkerShift(t *testing.T) {
+	data := randomData(1<<20, 2)
+	shifted := append([]byte("inserted bytes"), data...)
+
+	seen := make(map[string]bool)
+	for _, c := range chunks(t, data, Config{}) {
+		seen[string(c.Digests[hashio.SHA256])] = true
+	}
+	all := chunks(t, shifted, Config{})
+	shared := 0
+	for _, c := range all {
+		if seen[string(c.Digests[hashio.SHA256])] {
+			shared++
+		}
+	}
+	// Only the chunks around the insertion should change.
+	if shared < len(all)-2 {
+		t.Errorf("%d of %d chunks survived an insertion at the start, wanted at least %d", shared, len(all), len(all)-2)
+	}
+}
+
+func TestChunkerConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{AvgSize: 16},
+		{MinSize: 8 << 10, AvgSize: 4 << 10},
+		{AvgSize: 8 << 10, MaxSize: 4 << 10},
+	} {
+		if _, err := New(bytes.NewReader(nil), cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("New(%+v) got error: %v, wanted %v", cfg, err, ErrInvalidConfig)
+		}
+	}
+
+	c, err := New(bytes.NewReader(nil), Config{})
+	if err != nil {
+		t.Fatalf("New(Config{}): %v", err)
+	}
+	if _, err := c.Next(); err != io.EOF {
+		t.Errorf("Chunker.Next() on empty input got error: %v, wanted io.EOF", err)
+	}
+}
@@ -0,0 +1,76 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+)
+
+// HashReadCloser is a HashReader that also implements io.Closer by forwarding
+// Close to the wrapped io.ReadCloser.
+type HashReadCloser struct {
+	*HashReader
+	c       io.Closer
+	onClose func(sums map[string][]byte)
+	closed  bool
+}
+
+// NewHashReadCloser is like NewHashReader but keeps rc's Close method available
+// on the returned HashReadCloser. If onClose is not nil, it is called with the
+// digests of every hash in hashers (see HashReader.Sums) the first time Close is
+// called, after rc has been closed, whether or not closing rc failed.
+func NewHashReadCloser(rc io.ReadCloser, hashers map[string]hash.Hash, onClose func(sums map[string][]byte)) *HashReadCloser {
+	return &HashReadCloser{
+		HashReader: NewHashReader(rc, hashers),
+		c:          rc,
+		onClose:    onClose,
+	}
+}
+
+// Close closes the wrapped io.ReadCloser and finalizes the digests, passing them
+// to the onClose callback given to NewHashReadCloser. It returns the error from
+// closing the wrapped io.ReadCloser.
+func (h *HashReadCloser) Close() error {
+	err := h.c.Close()
+	if !h.closed {
+		h.closed = true
+		if h.onClose != nil {
+			h.onClose(h.Sums())
+		}
+	}
+	return err
+}
+
+// HashWriteCloser is a HashWriter that also implements io.Closer by forwarding
+// Close to the wrapped io.WriteCloser.
+type HashWriteCloser struct {
+	*HashWriter
+	c       io.Closer
+	onClose func(sums map[string][]byte)
+	closed  bool
+}
+
+// NewHashWriteCloser is like NewHashWriter but keeps wc's Close method available
+// on the returned HashWriteCloser. If onClose is not nil, it is called with the
+// digests of every hash in hashers (see HashWriter.Sums) the first time Close is
+// called, after wc has been closed, whether or not closing wc failed.
+func NewHashWriteCloser(wc io.WriteCloser, hashers map[string]hash.Hash, onClose func(sums map[string][]byte)) *HashWriteCloser {
+	return &HashWriteCloser{
+		HashWriter: NewHashWriter(wc, hashers),
+		c:          wc,
+		onClose:    onClose,
+	}
+}
+
+// Close closes the wrapped io.WriteCloser and finalizes the digests, passing
+// them to the onClose callback given to NewHashWriteCloser. It returns the error
+// from closing the wrapped io.WriteCloser.
+func (h *HashWriteCloser) Close() error {
+	err := h.c.Close()
+	if !h.closed {
+		h.closed = true
+		if h.onClose != nil {
+			h.onClose(h.Sums())
+		}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package hashio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashReadCloser(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+
+	var got map[string][]byte
+	var calls int
+	hr := NewHashReadCloser(f, StdCryptoHashes(), func(sums map[string][]byte) {
+		got = sums
+		calls++
+	})
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if err := hr.Close(); err != nil {
+		t.Errorf("HashReadCloser.Close: %v", err)
+	}
+	if err := hr.Close(); err == nil {
+		t.Errorf("second HashReadCloser.Close got: nil error, wanted the error from closing the file twice")
+	}
+
+	if calls != 1 {
+		t.Errorf("onClose called %d times, wanted 1", calls)
+	}
+	if hash := fmt.Sprintf("%x", got[SHA256]); hash != dataFileSHA256 {
+		t.Errorf("onClose sums[sha256] got: %q, wanted %q", hash, dataFileSHA256)
+	}
+}
+
+func TestHashWriteCloser(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("os.Create: %v", err)
+	}
+
+	var got map[string][]byte
+	hw := NewHashWriteCloser(f, StdCryptoHashes(), func(sums map[string][]byte) {
+		got = sums
+	})
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriteCloser.Write: %v", err)
+	}
+	if err := hw.Close(); err != nil {
+		t.Errorf("HashWriteCloser.Close: %v", err)
+	}
+	if _, err := f.Write(contents); err == nil {
+		t.Errorf("HashWriteCloser.Close did not close the underlying file")
+	}
+	if hash := fmt.Sprintf("%x", got[MD5]); hash != dataFileMD5 {
+		t.Errorf("onClose sums[md5] got: %q, wanted %q", hash, dataFileMD5)
+	}
+}
@@ -0,0 +1,128 @@
+package hashio
+
+import (
+	"encoding"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Compose returns a factory of hashes computing the outer digest of the inner
+// digest of the data, such as the SHA-256 of the SHA-1 of a file. Passing it
+// to Register makes the derived digest available by name like any other
+// algorithm:
+//
+//	hashio.Register("sha256-of-sha1", hashio.Compose(sha256.New, sha1.New))
+//
+// The hashes have the size of the outer digest. Their state is that of the
+// inner hash, so they can be marshaled and cloned if it can.
+func Compose(outer, inner func() hash.Hash) func() hash.Hash {
+	return func() hash.Hash {
+		return &composedHash{Hash: inner(), outer: outer(), newOuter: outer}
+	}
+}
+
+// composedHash is a hash.Hash whose digest is that of outer over the digest
+// of the embedded hash.
+type composedHash struct {
+	hash.Hash
+	outer    hash.Hash // scratch hash for Sum
+	newOuter func() hash.Hash
+}
+
+func (c *composedHash) Sum(b []byte) []byte {
+	var buf [64]byte
+	c.outer.Reset()
+	c.outer.Write(c.Hash.Sum(buf[:0]))
+	return c.outer.Sum(b)
+}
+
+func (c *composedHash) Size() int { return c.outer.Size() }
+
+// MarshalBinary implements encoding.BinaryMarshaler if the inner hash does.
+func (c *composedHash) MarshalBinary() ([]byte, error) {
+	m, ok := c.Hash.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement encoding.BinaryMarshaler", c.Hash)
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler if the inner hash
+// does.
+func (c *composedHash) UnmarshalBinary(data []byte) error {
+	u, ok := c.Hash.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("hashio: %T does not implement encoding.BinaryUnmarshaler", c.Hash)
+	}
+	return u.UnmarshalBinary(data)
+}
+
+// Clone implements hash.Cloner if the inner hash does.
+func (c *composedHash) Clone() (hash.Cloner, error) {
+	cl, ok := c.Hash.(hash.Cloner)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement hash.Cloner", c.Hash)
+	}
+	inner, err := cl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &composedHash{Hash: inner, outer: c.newOuter(), newOuter: c.newOuter}, nil
+}
+
+// Transform returns a factory of hashes computing the digest of h over the
+// data as rewritten by transform, such as the SHA-256 of a document with its
+// line endings normalized. transform is called with the underlying hash
+// whenever a hash is created or Reset, and returns the writer the data is
+// written to. Like Compose, the result can be passed to Register.
+//
+// The writer must not fail, since hash.Hash writes can't. If it holds back
+// bytes until it sees what follows them, it should implement a Pending()
+// []byte method returning them: Sum passes them to a clone of the underlying
+// hash, which must implement hash.Cloner as those of the standard library do,
+// so the digest is that of the data as if it ended there while the hash
+// keeps going. Held back bytes are left out of the digest if the hash can't
+// be cloned. The hashes can't be marshaled or cloned themselves, since the
+// state of the writer is opaque.
+func Transform(h func() hash.Hash, transform func(w io.Writer) io.Writer) func() hash.Hash {
+	return func() hash.Hash {
+		t := &transformedHash{Hash: h(), transform: transform}
+		t.w = transform(t.Hash)
+		return t
+	}
+}
+
+// transformedHash is a hash.Hash of the data written through w.
+type transformedHash struct {
+	hash.Hash
+	w         io.Writer
+	transform func(io.Writer) io.Writer
+}
+
+func (t *transformedHash) Write(p []byte) (int, error) {
+	return t.w.Write(p)
+}
+
+func (t *transformedHash) Sum(b []byte) []byte {
+	p, ok := t.w.(interface{ Pending() []byte })
+	if !ok {
+		return t.Hash.Sum(b)
+	}
+	pending := p.Pending()
+	c, ok := t.Hash.(hash.Cloner)
+	if len(pending) == 0 || !ok {
+		return t.Hash.Sum(b)
+	}
+	clone, err := c.Clone()
+	if err != nil {
+		return t.Hash.Sum(b)
+	}
+	clone.Write(pending)
+	return clone.Sum(b)
+}
+
+func (t *transformedHash) Reset() {
+	t.Hash.Reset()
+	t.w = t.transform(t.Hash)
+}
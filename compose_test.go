@@ -0,0 +1,95 @@
+package hashio
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	registerForTest(t, "sha256-of-sha1", Compose(sha256.New, sha1.New))
+	r, err := NewByNames(strings.NewReader("hello world"), "sha256-of-sha1")
+	if err != nil {
+		t.Fatalf("NewByNames(): %v", err)
+	}
+	io.ReadAll(r)
+	// sha256(sha1("hello world"))
+	want := "39dac1aa252f351647ae84fa1bb0cb1e58f257033d0b6eeaaa747341e79c8203"
+	if got := r.HexHash("sha256-of-sha1"); got != want {
+		t.Errorf("HashReader.HexHash(sha256-of-sha1) got: %q, wanted %q", got, want)
+	}
+	state, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("HashReader.MarshalBinary(): %v", err)
+	}
+	r.Reset(strings.NewReader(""))
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatalf("HashReader.UnmarshalBinary(): %v", err)
+	}
+	if got := r.HexHash("sha256-of-sha1"); got != want {
+		t.Errorf("HashReader.HexHash(sha256-of-sha1) after UnmarshalBinary got: %q, wanted %q", got, want)
+	}
+}
+
+// lfWriter replaces the "\r\n" line endings of what is written with "\n".
+type lfWriter struct {
+	w  io.Writer
+	cr bool // a "\r" was held back
+}
+
+func (l *lfWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if l.cr && b != '\n' {
+			l.w.Write([]byte{'\r'})
+		}
+		l.cr = b == '\r'
+		if !l.cr {
+			l.w.Write([]byte{b})
+		}
+	}
+	return len(p), nil
+}
+
+func (l *lfWriter) Pending() []byte {
+	if l.cr {
+		return []byte{'\r'}
+	}
+	return nil
+}
+
+func TestTransform(t *testing.T) {
+	newHash := Transform(sha256.New, func(w io.Writer) io.Writer { return &lfWriter{w: w} })
+	w := NewHashWriter(nil, map[string]hash.Hash{"sha256-lf": newHash()})
+	w.WriteString("a\r\nb\r")
+	w.WriteString("\nc\r")
+	// sha256("a\nb\nc\r"): the held back "\r" is included.
+	if got, want := w.HexHash("sha256-lf"), "67946356020311b729ec9a37a87e223d2479ff7af3aaacccca2b187bf8a963ee"; got != want {
+		t.Errorf("HashWriter.HexHash(sha256-lf) got: %q, wanted %q", got, want)
+	}
+
+	w.Reset(nil)
+	w.WriteString("a\nb\r\nc\r\n")
+	// sha256("a\nb\nc\n")
+	if got, want := w.HexHash("sha256-lf"), "880553fca8fcea94e325ee2cfb48e5a985cc797f39a14cc6d3cedecfeb2ae4d2"; got != want {
+		t.Errorf("HashWriter.HexHash(sha256-lf) after Reset got: %q, wanted %q", got, want)
+	}
+
+	// Taking the digest mid-stream doesn't change the final one.
+	w.Reset(nil)
+	w.WriteString("a\r")
+	if got, want := w.HexHash("sha256-lf"), fmt.Sprintf("%x", sha256.Sum256([]byte("a\r"))); got != want {
+		t.Errorf("HashWriter.HexHash(sha256-lf) mid-stream got: %q, wanted %q", got, want)
+	}
+	w.WriteString("\n")
+	if got, want := w.HexHash("sha256-lf"), fmt.Sprintf("%x", sha256.Sum256([]byte("a\n"))); got != want {
+		t.Errorf("HashWriter.HexHash(sha256-lf) after a mid-stream digest got: %q, wanted %q", got, want)
+	}
+
+	if _, err := w.MarshalBinary(); err == nil {
+		t.Errorf("HashWriter.MarshalBinary() of a transformed hash got: nil error, wanted one")
+	}
+}
@@ -0,0 +1,22 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+)
+
+// CopyAndHash copies from src to dst until either EOF is reached on src or an
+// error occurs, passing every byte dst accepts to each hash.Hash in hashers. It
+// returns the number of bytes copied, the digest of every hash keyed by name,
+// and the first error encountered while copying, if any.
+//
+// dst may be nil, in which case src is only hashed. sums is nil whenever err
+// is not nil, since the digests then describe an incomplete copy.
+func CopyAndHash(dst io.Writer, src io.Reader, hashers map[string]hash.Hash) (n int64, sums map[string][]byte, err error) {
+	h := NewHashWriter(dst, hashers)
+	n, err = io.Copy(h, src)
+	if err != nil {
+		return n, nil, err
+	}
+	return n, h.Sums(), nil
+}
@@ -0,0 +1,45 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestCopyAndHash(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("os.Open(%q): %v", dataFile, err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	n, sums, err := CopyAndHash(&buf, f, StdCryptoHashes())
+	if err != nil {
+		t.Fatalf("CopyAndHash([from: %q]): %v", dataFile, err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("CopyAndHash() got n: %d, wanted %d", n, buf.Len())
+	}
+	for name, want := range map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256} {
+		if got := hex.EncodeToString(sums[name]); got != want {
+			t.Errorf("CopyAndHash() sums[%s] got: %q, wanted %q", name, got, want)
+		}
+	}
+}
+
+func TestCopyAndHashErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	n, sums, err := CopyAndHash(nil, &errReader{[]byte("partial"), boom}, StdCryptoHashes())
+	if err != boom || n != 7 || sums != nil {
+		t.Errorf("CopyAndHash() with failing src got: (%d, %v, %v), wanted (7, nil, %v)", n, sums, err, boom)
+	}
+
+	n, sums, err = CopyAndHash(&errWriter{4, boom}, bytes.NewReader([]byte("too long")), StdCryptoHashes())
+	if err != boom || n != 4 || sums != nil {
+		t.Errorf("CopyAndHash() with failing dst got: (%d, %v, %v), wanted (4, nil, %v)", n, sums, err, boom)
+	}
+}
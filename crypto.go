@@ -0,0 +1,78 @@
+package hashio
+
+import (
+	"crypto"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// cryptoNames maps crypto.Hash values to the names used for them by this package.
+var cryptoNames = map[crypto.Hash]Algorithm{
+	crypto.MD4:         "md4",
+	crypto.MD5:         MD5,
+	crypto.SHA1:        SHA1,
+	crypto.SHA224:      "sha224",
+	crypto.SHA256:      SHA256,
+	crypto.SHA384:      SHA384,
+	crypto.SHA512:      SHA512,
+	crypto.MD5SHA1:     "md5sha1",
+	crypto.RIPEMD160:   "ripemd160",
+	crypto.SHA3_224:    "sha3-224",
+	crypto.SHA3_256:    SHA3_256,
+	crypto.SHA3_384:    SHA3_384,
+	crypto.SHA3_512:    SHA3_512,
+	crypto.SHA512_224:  "sha512-224",
+	crypto.SHA512_256:  SHA512_256,
+	crypto.BLAKE2s_256: BLAKE2s_256,
+	crypto.BLAKE2b_256: BLAKE2b_256,
+	crypto.BLAKE2b_384: BLAKE2b_384,
+	crypto.BLAKE2b_512: BLAKE2b_512,
+}
+
+// CryptoName returns the name used by this package for h, for example "sha256"
+// for crypto.SHA256. Hashes without a well known name are named after their
+// lower cased crypto.Hash string.
+func CryptoName(h crypto.Hash) Algorithm {
+	if name, ok := cryptoNames[h]; ok {
+		return name
+	}
+	return strings.ToLower(h.String())
+}
+
+// cryptoHashers returns a fresh hash.Hash for each of hs keyed by CryptoName.
+func cryptoHashers(hs []crypto.Hash) (map[string]hash.Hash, error) {
+	hashers := make(map[string]hash.Hash, len(hs))
+	for _, h := range hs {
+		if !h.Available() {
+			return nil, fmt.Errorf("hashio: crypto hash %v is unavailable, the package implementing it may not be linked into the binary", h)
+		}
+		hashers[CryptoName(h)] = h.New()
+	}
+	return hashers, nil
+}
+
+// NewHashReaderFromCrypto is like NewHashReader but builds the set of hashes
+// from the crypto.Hash values in hs. Each hash is named by CryptoName. An error
+// is returned if any of hs is not available, which usually means the package
+// implementing it (e.g. crypto/sha512) was not imported by the program.
+func NewHashReaderFromCrypto(r io.Reader, hs ...crypto.Hash) (*HashReader, error) {
+	hashers, err := cryptoHashers(hs)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashReader(r, hashers), nil
+}
+
+// NewHashWriterFromCrypto is like NewHashWriter but builds the set of hashes
+// from the crypto.Hash values in hs. Each hash is named by CryptoName. An error
+// is returned if any of hs is not available, which usually means the package
+// implementing it (e.g. crypto/sha512) was not imported by the program.
+func NewHashWriterFromCrypto(w io.Writer, hs ...crypto.Hash) (*HashWriter, error) {
+	hashers, err := cryptoHashers(hs)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashWriter(w, hashers), nil
+}
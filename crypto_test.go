@@ -0,0 +1,45 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFromCrypto(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr, err := NewHashReaderFromCrypto(bytes.NewReader(contents), crypto.SHA256, crypto.SHA1, crypto.MD5)
+	if err != nil {
+		t.Fatalf("NewHashReaderFromCrypto: %v", err)
+	}
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if hash := hr.HexHash(SHA256); hash != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", hash, dataFileSHA256)
+	}
+	if hash := hr.HexHash(SHA1); hash != dataFileSHA1 {
+		t.Errorf("HashReader.HexHash(sha1) got: %q, wanted %q", hash, dataFileSHA1)
+	}
+
+	hw, err := NewHashWriterFromCrypto(ioutil.Discard, crypto.MD5)
+	if err != nil {
+		t.Fatalf("NewHashWriterFromCrypto: %v", err)
+	}
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	if hash := hw.HexHash(MD5); hash != dataFileMD5 {
+		t.Errorf("HashWriter.HexHash(md5) got: %q, wanted %q", hash, dataFileMD5)
+	}
+
+	// Nothing in this package or its tests links in MD4.
+	if _, err := NewHashWriterFromCrypto(ioutil.Discard, crypto.MD4); err == nil {
+		t.Errorf("NewHashWriterFromCrypto(MD4) got: nil error, wanted an error for an unlinked hash")
+	}
+}
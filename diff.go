@@ -0,0 +1,104 @@
+package hashio
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ChangeKind is the kind of a Change between two manifests.
+type ChangeKind int
+
+const (
+	// Added is reported for a file only in the new manifest.
+	Added ChangeKind = iota
+	// Removed is reported for a file only in the old manifest.
+	Removed
+	// Modified is reported for a file whose size or digests changed.
+	Modified
+	// MetadataChanged is reported for a file with the same contents but a
+	// different modification time or inode number, such as a file touched or
+	// copied again by a build.
+	MetadataChanged
+)
+
+var changeKindNames = [...]string{
+	Added:           "added",
+	Removed:         "removed",
+	Modified:        "modified",
+	MetadataChanged: "metadata",
+}
+
+// String returns the lowercase name of k, such as "modified".
+func (k ChangeKind) String() string {
+	if k < 0 || int(k) >= len(changeKindNames) {
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+	return changeKindNames[k]
+}
+
+// Change is a difference between two manifests, as reported by ManifestDiff.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	// Old and New are the entries of the file in the old and new manifests,
+	// the zero value for those it isn't in.
+	Old, New ManifestEntry
+}
+
+// ManifestDiff returns the changes between the manifests old and new of the
+// same tree, such as those of two builds, ordered by path. Files in both are
+// compared by size and by the digests of the algorithms the manifests have in
+// common; those with the same contents are reported as MetadataChanged if
+// their modification time or inode number differ, and not at all otherwise.
+// A file without a digest of one of the common algorithms is reported as
+// Modified, since its contents can't be shown to be the same.
+//
+// An error is returned if the manifests have no algorithm in common, since
+// their files can't be compared then.
+func ManifestDiff(old, new *Manifest) ([]Change, error) {
+	var common []string
+	for _, alg := range old.Algorithms {
+		if slices.Contains(new.Algorithms, alg) {
+			common = append(common, alg)
+		}
+	}
+	if len(common) == 0 && len(old.Files) > 0 && len(new.Files) > 0 {
+		return nil, fmt.Errorf("hashio: manifests have no algorithm in common: %v and %v", old.Algorithms, new.Algorithms)
+	}
+
+	var changes []Change
+	for name, o := range old.Files {
+		n, ok := new.Files[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: name, Kind: Removed, Old: o})
+		case !sameContents(o, n, common):
+			changes = append(changes, Change{Path: name, Kind: Modified, Old: o, New: n})
+		case !o.ModTime.Equal(n.ModTime) || o.Inode != n.Inode:
+			changes = append(changes, Change{Path: name, Kind: MetadataChanged, Old: o, New: n})
+		}
+	}
+	for name, n := range new.Files {
+		if _, ok := old.Files[name]; !ok {
+			changes = append(changes, Change{Path: name, Kind: Added, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// sameContents reports whether a and b have the same size and the same
+// digests with the algorithms algs, which both must have.
+func sameContents(a, b ManifestEntry, algs []string) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	for _, alg := range algs {
+		if len(a.Sums[alg]) == 0 || subtle.ConstantTimeCompare(a.Sums[alg], b.Sums[alg]) != 1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package hashio
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestManifestDiff(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	build1 := fstest.MapFS{
+		"bin/app":     {Data: []byte("v1"), ModTime: t0},
+		"lib/same.so": {Data: []byte("same"), ModTime: t0},
+		"lib/old.so":  {Data: []byte("old"), ModTime: t0},
+		"README":      {Data: []byte("readme"), ModTime: t0},
+		"VERSION":     {Data: []byte("1.0"), ModTime: t0},
+	}
+	build2 := fstest.MapFS{
+		"bin/app":     {Data: []byte("v2"), ModTime: t0.Add(time.Hour)},
+		"lib/same.so": {Data: []byte("same"), ModTime: t0.Add(time.Hour)},
+		"lib/new.so":  {Data: []byte("new"), ModTime: t0},
+		"README":      {Data: []byte("readme"), ModTime: t0},
+		"VERSION":     {Data: []byte("1.10"), ModTime: t0},
+	}
+	before, err := HashDir(build1, SHA256, MD5)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	after, err := HashDir(build2, SHA256)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+
+	changes, err := ManifestDiff(before, after)
+	if err != nil {
+		t.Fatalf("ManifestDiff(): %v", err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Kind.String()+" "+c.Path)
+	}
+	want := []string{"modified VERSION", "modified bin/app", "added lib/new.so", "removed lib/old.so", "metadata lib/same.so"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ManifestDiff() got: %q, wanted %q", got, want)
+	}
+	if c := changes[1]; !reflect.DeepEqual(c.Old, before.Files["bin/app"]) || !reflect.DeepEqual(c.New, after.Files["bin/app"]) {
+		t.Errorf("ManifestDiff() got entries %+v and %+v for bin/app, wanted those of the manifests", c.Old, c.New)
+	}
+
+	if changes, err := ManifestDiff(before, before); err != nil || len(changes) != 0 {
+		t.Errorf("ManifestDiff() of a manifest with itself got: %+v, %v, wanted no changes", changes, err)
+	}
+	other, _ := HashDir(build2, XXH3)
+	if _, err := ManifestDiff(after, other); err == nil {
+		t.Errorf("ManifestDiff() of manifests without a common algorithm got: nil, wanted an error")
+	}
+	// Files without digests can't be shown to be the same.
+	bare := &Manifest{Algorithms: []string{SHA256}, Files: map[string]ManifestEntry{"a": {Size: 1}}}
+	if changes, err := ManifestDiff(bare, bare); err != nil || len(changes) != 1 || changes[0].Kind != Modified {
+		t.Errorf("ManifestDiff() of files without digests got: %+v, %v, wanted a modified file", changes, err)
+	}
+
+	if got := ChangeKind(9).String(); got != "ChangeKind(9)" {
+		t.Errorf("ChangeKind(9).String() got: %q, wanted %q", got, "ChangeKind(9)")
+	}
+}
@@ -0,0 +1,136 @@
+package hashio
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// Digest is the result of hashing some data with the algorithm identified by
+// Algorithm. Digests returned by HashReader and HashWriter use the names the
+// hashes were registered under as their Algorithm.
+type Digest struct {
+	Algorithm string
+	Sum       []byte
+}
+
+// Hex returns d.Sum as a hex encoded ASCII string.
+func (d Digest) Hex() string {
+	return hex.EncodeToString(d.Sum)
+}
+
+// Equal reports whether d and other have the same algorithm and sum. The sums
+// are compared in constant time.
+func (d Digest) Equal(other Digest) bool {
+	return d.Algorithm == other.Algorithm && subtle.ConstantTimeCompare(d.Sum, other.Sum) == 1
+}
+
+// String returns d in the canonical "algorithm:hex" form, e.g.
+// "sha256:1963f25b...".
+func (d Digest) String() string {
+	return d.Algorithm + ":" + d.Hex()
+}
+
+// ParseDigest parses a digest in the canonical "algorithm:hex" form produced by
+// FormatDigest and Digest.String, such as those found in OCI manifests. If the
+// algorithm is known to this package the length of the digest is checked too.
+func ParseDigest(s string) (Digest, error) {
+	alg, h, ok := strings.Cut(s, ":")
+	if !ok || alg == "" {
+		return Digest{}, fmt.Errorf("hashio: invalid digest %q, expected algorithm:hex", s)
+	}
+
+	sum, err := hex.DecodeString(h)
+	if err != nil {
+		return Digest{}, fmt.Errorf("hashio: invalid digest %q: %w", s, err)
+	}
+	if len(sum) == 0 {
+		return Digest{}, fmt.Errorf("hashio: invalid digest %q, empty sum", s)
+	}
+	if hh, err := newHash(alg); err == nil && hh.Size() != len(sum) {
+		return Digest{}, fmt.Errorf("hashio: invalid digest %q, %s digests are %d bytes, not %d", s, alg, hh.Size(), len(sum))
+	}
+
+	return Digest{Algorithm: alg, Sum: sum}, nil
+}
+
+// FormatDigest returns d in the canonical "algorithm:hex" form understood by
+// ParseDigest. It is equivalent to d.String().
+func FormatDigest(d Digest) string {
+	return d.String()
+}
+
+// MarshalText implements encoding.TextMarshaler using FormatDigest.
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(FormatDigest(d)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseDigest.
+func (d *Digest) UnmarshalText(text []byte) error {
+	parsed, err := ParseDigest(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// digests returns the current digest of every hash.Hash in hashers ordered by
+// name.
+func digests(hashers map[string]hash.Hash) []Digest {
+	ds := make([]Digest, 0, len(hashers))
+	for _, name := range sortedNames(hashers) {
+		ds = append(ds, Digest{Algorithm: name, Sum: hashers[name].Sum(nil)})
+	}
+	return ds
+}
+
+// Digests returns the digest of every hash passed to NewHashReader ordered by
+// name. If any call to Read returned an error (not including io.EOF), nil is
+// returned. See Err.
+func (h *HashReader) Digests() []Digest {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return digests(h.hashers)
+}
+
+// Digests returns the digest of every hash passed to NewHashWriter ordered by
+// name. If any call to Write returned an error, nil is returned. See Err.
+func (h *HashWriter) Digests() []Digest {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return digests(h.hashers)
+}
+
+// VerifyFile hashes the file at path with a fresh instance of the algorithm named
+// by expected.Algorithm and reports whether the result matches expected.Sum. The
+// comparison is done in constant time.
+//
+// An error is returned if expected.Algorithm is not a known algorithm or if the
+// file cannot be read; the bool is false in both cases.
+func VerifyFile(path string, expected Digest) (bool, error) {
+	h, err := newHash(expected.Algorithm)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return false, err
+	}
+
+	return Digest{expected.Algorithm, h.Sum(nil)}.Equal(expected), nil
+}
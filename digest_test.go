@@ -0,0 +1,108 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+)
+
+func TestVerifyFile(t *testing.T) {
+	sum, err := hex.DecodeString(dataFileSHA256)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q): %v", dataFileSHA256, err)
+	}
+
+	ok, err := VerifyFile(dataFile, Digest{Algorithm: "sha256", Sum: sum})
+	if err != nil {
+		t.Fatalf("VerifyFile(%q, sha256): %v", dataFile, err)
+	}
+	if !ok {
+		t.Errorf("VerifyFile(%q, sha256) got: false, wanted true", dataFile)
+	}
+
+	bad := append([]byte(nil), sum...)
+	bad[0] ^= 0xff
+	ok, err = VerifyFile(dataFile, Digest{Algorithm: "sha256", Sum: bad})
+	if err != nil {
+		t.Fatalf("VerifyFile(%q, corrupted sha256): %v", dataFile, err)
+	}
+	if ok {
+		t.Errorf("VerifyFile(%q, corrupted sha256) got: true, wanted false", dataFile)
+	}
+
+	if _, err := VerifyFile(dataFile, Digest{Algorithm: "sha-256", Sum: sum}); err == nil {
+		t.Errorf("VerifyFile(%q, sha-256) got: nil error, wanted unknown hash error", dataFile)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write([]byte("hello I am happy"))
+
+	ds := hw.Digests()
+	if len(ds) != 3 {
+		t.Fatalf("HashWriter.Digests() returned %d digests, wanted 3", len(ds))
+	}
+	if ds[0].Algorithm != MD5 || ds[1].Algorithm != SHA1 || ds[2].Algorithm != SHA256 {
+		t.Errorf("HashWriter.Digests() got: %v, wanted md5, sha1, sha256 in order", ds)
+	}
+
+	d := ds[2]
+	want := "1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a"
+	if got := d.Hex(); got != want {
+		t.Errorf("Digest.Hex() got: %q, wanted %q", got, want)
+	}
+	if got := d.String(); got != "sha256:"+want {
+		t.Errorf("Digest.String() got: %q, wanted %q", got, "sha256:"+want)
+	}
+
+	sum, _ := hex.DecodeString(want)
+	if !d.Equal(Digest{SHA256, sum}) {
+		t.Errorf("Digest.Equal got: false for an identical digest, wanted true")
+	}
+	if d.Equal(Digest{SHA512, sum}) {
+		t.Errorf("Digest.Equal got: true for a different algorithm, wanted false")
+	}
+	if d.Equal(Digest{SHA256, sum[:16]}) {
+		t.Errorf("Digest.Equal got: true for a different sum, wanted false")
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	d, err := ParseDigest("sha256:" + dataFileSHA256)
+	if err != nil {
+		t.Fatalf("ParseDigest: %v", err)
+	}
+	if d.Algorithm != SHA256 || d.Hex() != dataFileSHA256 {
+		t.Errorf("ParseDigest got: %v, wanted sha256:%s", d, dataFileSHA256)
+	}
+	if s := FormatDigest(d); s != "sha256:"+dataFileSHA256 {
+		t.Errorf("FormatDigest got: %q, wanted %q", s, "sha256:"+dataFileSHA256)
+	}
+
+	// Unknown algorithms are accepted without a length check.
+	if d, err := ParseDigest("custom:00ff"); err != nil || d.Algorithm != "custom" || len(d.Sum) != 2 {
+		t.Errorf("ParseDigest(custom:00ff) got: %v, %v, wanted custom:00ff, nil", d, err)
+	}
+
+	for _, bad := range []string{
+		"",
+		"sha256",
+		":" + dataFileSHA256,
+		"sha256:",
+		"sha256:xyz",
+		"sha256:" + dataFileMD5,
+	} {
+		if _, err := ParseDigest(bad); err == nil {
+			t.Errorf("ParseDigest(%q) got: nil error, wanted an error", bad)
+		}
+	}
+
+	var fromText Digest
+	if err := fromText.UnmarshalText([]byte("md5:" + dataFileMD5)); err != nil {
+		t.Fatalf("Digest.UnmarshalText: %v", err)
+	}
+	if text, _ := fromText.MarshalText(); string(text) != "md5:"+dataFileMD5 {
+		t.Errorf("Digest text round trip got: %s, wanted md5:%s", text, dataFileMD5)
+	}
+}
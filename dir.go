@@ -0,0 +1,458 @@
+package hashio
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Manifest records the digests of the regular files of a directory tree, as
+// returned by HashDir, so that the tree can later be checked with VerifyDir.
+// It can be stored as JSON, see MarshalJSON, as newline-delimited JSON with
+// WriteNDJSON, or as CSV with WriteCSV; the checksums package converts it to
+// the formats of sha256sum and the BSD tools.
+type Manifest struct {
+	// Algorithms are the names of the algorithms the files were hashed with,
+	// in sorted order.
+	Algorithms []string
+	// Files maps the slash-separated path of every regular file, relative to
+	// the root of the tree, to its entry.
+	Files map[string]ManifestEntry
+	// Ignore holds the patterns of the files left out, those of
+	// DirHasher.Ignore followed by those of the IgnoreFile of the tree.
+	// VerifyDir leaves out the same files.
+	Ignore []string
+}
+
+// ManifestEntry is the record of one file of a Manifest.
+type ManifestEntry struct {
+	Size int64
+	// ModTime and Inode are the modification time and inode number of the
+	// file when it was hashed, used by UpdateDir to tell whether it changed.
+	// Inode is 0 on systems without inode numbers and for file systems that
+	// don't report them, such as fstest.MapFS.
+	ModTime time.Time
+	Inode   uint64
+	// Sums holds the digest of the file with each algorithm of the manifest.
+	Sums Results
+}
+
+// DirHasher hashes and verifies directory trees, like HashDir and VerifyDir,
+// with a pool of goroutines: hashing a tree of many files on one keeps a
+// single read in flight, far from what disks and SSDs can serve. Each worker
+// reuses its hashes and copy buffer across files.
+//
+// The zero value hashes with the algorithms of StdCryptoHashes on one
+// goroutine. A DirHasher must not be modified while in use, but can be used
+// concurrently otherwise.
+type DirHasher struct {
+	// Algorithms are the names of the algorithms Hash computes, or those of
+	// StdCryptoHashes if empty. Verify uses those of the manifest instead.
+	Algorithms []string
+	// Workers is the number of files hashed at once; values less than one
+	// mean one.
+	Workers int
+	// CheckInode makes Update hash files whose inode number changed even if
+	// their size and modification time didn't, as when a file is replaced by
+	// another with the same metadata, such as by rsync or an archive
+	// extraction.
+	CheckInode bool
+	// Ignore holds patterns of files and directories to leave out, such as
+	// logs, caches and version control metadata, in addition to those listed
+	// in the IgnoreFile at the root of the tree, if any. A pattern is a
+	// path.Match pattern matched against the base name of every file and
+	// directory, or against its path relative to the root if the pattern
+	// contains a "/" other than at its end; a leading "/" is dropped. A
+	// pattern ending with "/" only matches directories. The contents of
+	// ignored directories are ignored too. For example, ".git/" leaves out
+	// every .git directory, "*.log" every log file and "/build/*.o" the object
+	// files directly under build. Negated patterns aren't supported.
+	Ignore []string
+}
+
+// HashDir hashes every regular file of fsys, usually a directory opened with
+// os.DirFS, with a fresh instance of each algorithm in algos, or those of
+// StdCryptoHashes if none are given. Directories are walked recursively in
+// lexical order; symbolic links and other irregular files are skipped, as are
+// the files matched by the patterns of the IgnoreFile at the root of the
+// tree, if any (see DirHasher.Ignore). Files are hashed one at a time; see
+// DirHasher to hash several at once.
+//
+// An error is returned if any name is not a known algorithm, if the
+// IgnoreFile holds an invalid pattern or if a directory or file can't be
+// read.
+func HashDir(fsys fs.FS, algos ...string) (*Manifest, error) {
+	d := DirHasher{Algorithms: algos}
+	return d.Hash(context.Background(), fsys)
+}
+
+// Hash is like HashDir, hashing up to d.Workers files at once. The manifest
+// doesn't depend on the number of workers, nor does the error: if several
+// files can't be read, it is that of the first in lexical order. ctx.Err() is
+// returned if ctx is done before every file is hashed.
+func (d *DirHasher) Hash(ctx context.Context, fsys fs.FS) (*Manifest, error) {
+	return d.Update(ctx, fsys, nil)
+}
+
+// UpdateDir is like HashDir, but only hashes the files that changed since
+// prev, a manifest of the same tree: the entries of files whose size and
+// modification time are those recorded in prev are reused, provided they
+// hold a digest of every algorithm in algos. Hashing a large tree where few
+// files changed thus mostly costs a walk.
+//
+// Like make and rsync, UpdateDir trusts modification times: a file rewritten
+// with the same size within the resolution of the file system's timestamps,
+// or whose time was restored, keeps its previous digests. Use VerifyDir or
+// HashDir to check every file.
+func UpdateDir(fsys fs.FS, prev *Manifest, algos ...string) (*Manifest, error) {
+	d := DirHasher{Algorithms: algos}
+	return d.Update(context.Background(), fsys, prev)
+}
+
+// Update is like UpdateDir, hashing up to d.Workers files at once, and also
+// comparing inode numbers if d.CheckInode is set. prev may be nil, in which
+// case Update is like Hash.
+func (d *DirHasher) Update(ctx context.Context, fsys fs.FS, prev *Manifest) (*Manifest, error) {
+	hashers, err := hashersByName(d.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{Algorithms: sortedNames(hashers), Files: make(map[string]ManifestEntry)}
+	patterns, err := readIgnoreFile(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.Ignore)+len(patterns) > 0 {
+		m.Ignore = append(slices.Clone(d.Ignore), patterns...)
+	}
+
+	var mu sync.Mutex
+	err = d.walk(ctx, fsys, m.Algorithms, m.Ignore, func(h *HashWriter, buf []byte, name string, de fs.DirEntry) error {
+		e, ok := d.unchanged(prev, m.Algorithms, name, de)
+		if !ok {
+			var err error
+			if e, err = hashFSFile(ctx, fsys, name, h, buf); err != nil {
+				return err
+			}
+		}
+		mu.Lock()
+		m.Files[name] = e
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// unchanged returns the entry of the file at name in prev, with the digests of
+// algs only, and reports whether the file described by de is unchanged since
+// and the entry holds those digests.
+func (d *DirHasher) unchanged(prev *Manifest, algs []string, name string, de fs.DirEntry) (ManifestEntry, bool) {
+	if prev == nil {
+		return ManifestEntry{}, false
+	}
+	old, ok := prev.Files[name]
+	if !ok {
+		return ManifestEntry{}, false
+	}
+	info, err := de.Info()
+	if err != nil || info.Size() != old.Size || !info.ModTime().Equal(old.ModTime) || d.CheckInode && inode(info) != old.Inode {
+		return ManifestEntry{}, false
+	}
+	sums := make(Results, len(algs))
+	for _, alg := range algs {
+		sum, ok := old.Sums[alg]
+		if !ok {
+			return ManifestEntry{}, false
+		}
+		sums[alg] = sum
+	}
+	old.Sums = sums
+	return old, true
+}
+
+// errStopWalk stops walkFiles once a file failed.
+var errStopWalk = errors.New("hashio: walk stopped")
+
+// walk calls fn with the path of every regular file of fsys on up to
+// d.Workers goroutines, each passing its own buffer and HashWriter of the
+// algorithms algs. Files are handed out in lexical order, and none are once
+// a call failed. The error returned is ctx.Err() if ctx is done, or else that
+// of the first file in lexical order for which fn failed, or that of the walk.
+func (d *DirHasher) walk(ctx context.Context, fsys fs.FS, algs, ignore []string, fn func(h *HashWriter, buf []byte, name string, de fs.DirEntry) error) error {
+	ignored, err := compileIgnore(ignore)
+	if err != nil {
+		return err
+	}
+	workers := max(d.Workers, 1)
+	// Create the hashes up front, so that an unknown algorithm is reported
+	// before anything is read.
+	hs := make([]*HashWriter, workers)
+	for i := range hs {
+		hashers := make(map[string]hash.Hash, len(algs))
+		for _, alg := range algs {
+			h, err := newHash(alg)
+			if err != nil {
+				return err
+			}
+			hashers[alg] = h
+		}
+		hs[i] = NewHasher(hashers)
+	}
+
+	type item struct {
+		i    int
+		name string
+		de   fs.DirEntry
+	}
+	next := make(chan item)
+	stop := make(chan struct{})
+	var (
+		mu       sync.Mutex
+		firstErr error
+		firstI   int
+		wg       sync.WaitGroup
+	)
+	wg.Add(workers)
+	for _, h := range hs {
+		go func(h *HashWriter) {
+			defer wg.Done()
+			buf := getBuffer(copyBufSize(fileBufSize))
+			defer putBuffer(buf)
+			for it := range next {
+				err := fn(h, *buf, it.name, it.de)
+				if err == nil {
+					continue
+				}
+				mu.Lock()
+				if firstErr == nil {
+					close(stop)
+				}
+				if firstErr == nil || it.i < firstI {
+					firstErr, firstI = err, it.i
+				}
+				mu.Unlock()
+			}
+		}(h)
+	}
+
+	n := 0
+	walkErr := walkFiles(fsys, ignored, func(name string, de fs.DirEntry) error {
+		select {
+		case next <- item{n, name, de}:
+			n++
+			return nil
+		case <-stop:
+			return errStopWalk
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(next)
+	wg.Wait()
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case firstErr != nil:
+		return firstErr
+	}
+	return walkErr
+}
+
+// walkFiles calls fn with the path and entry of every regular file of fsys
+// not matched by ignored, in lexical order, stopping at the first error.
+func walkFiles(fsys fs.FS, ignored ignoreSet, fn func(name string, de fs.DirEntry) error) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if name != "." && ignored.match(name, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return fn(name, d)
+	})
+}
+
+// hashFSFile hashes the file of fsys at name with h, reading through buf
+// until ctx is done.
+func hashFSFile(ctx context.Context, fsys fs.FS, name string, h *HashWriter, buf []byte) (ManifestEntry, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	// Hide the WriteTo and ReadFrom methods so the copy uses buf.
+	h.Reset(nil)
+	if _, err := io.CopyBuffer(struct{ io.Writer }{h}, ctxReader{ctx, f}, buf); err != nil {
+		return ManifestEntry{}, err
+	}
+	return ManifestEntry{Size: h.BytesWritten(), ModTime: info.ModTime(), Inode: inode(info), Sums: h.Results()}, nil
+}
+
+// FileStatus is the outcome of verifying one file with VerifyDir.
+type FileStatus int
+
+const (
+	// FileOK is reported for a file matching its entry of the manifest.
+	FileOK FileStatus = iota
+	// FileModified is reported for a file whose size or digests don't match
+	// its entry.
+	FileModified
+	// FileMissing is reported for an entry whose file is gone.
+	FileMissing
+	// FileUnexpected is reported for a file that isn't in the manifest.
+	FileUnexpected
+	// FileError is reported for a file that couldn't be read.
+	FileError
+)
+
+var fileStatusNames = [...]string{
+	FileOK:         "ok",
+	FileModified:   "modified",
+	FileMissing:    "missing",
+	FileUnexpected: "unexpected",
+	FileError:      "error",
+}
+
+// String returns the lowercase name of s, such as "modified".
+func (s FileStatus) String() string {
+	if s < 0 || int(s) >= len(fileStatusNames) {
+		return fmt.Sprintf("FileStatus(%d)", int(s))
+	}
+	return fileStatusNames[s]
+}
+
+// FileResult is the outcome of verifying one file with VerifyDir.
+type FileResult struct {
+	// Path is the slash-separated path of the file relative to the root.
+	Path   string
+	Status FileStatus
+	// Size is the size of the file, or -1 if it is missing or couldn't be
+	// read.
+	Size int64
+	// Mismatched holds the names of the algorithms whose digests don't match
+	// the manifest, or that the entry has no digest of, in sorted order. It is empty for a modified file whose
+	// size differs, since it isn't hashed.
+	Mismatched []string
+	// Err is the error reading the file, set if Status is FileError.
+	Err error
+}
+
+// VerifyDir checks the regular files of fsys against m, as returned by
+// HashDir for the same tree. Files are hashed with the algorithms of m, only
+// if their size matches, one at a time; see DirHasher to hash several at
+// once. One FileResult is returned per file of either fsys or m, ordered by
+// path; failures to read single files are reported there. The files matched
+// by the patterns of m.Ignore are left out, whatever the IgnoreFile of fsys
+// says now.
+//
+// An error is returned if m has files but no algorithms, if an algorithm of m
+// isn't known, or a digest of one of its entries isn't of one of them, if a
+// pattern of m.Ignore is invalid, or if a directory of fsys can't be read. An
+// entry missing the digest of an algorithm of m is reported as FileModified,
+// with the algorithm in Mismatched.
+func VerifyDir(fsys fs.FS, m *Manifest) ([]FileResult, error) {
+	var d DirHasher
+	return d.Verify(context.Background(), fsys, m)
+}
+
+// Verify is like VerifyDir, hashing up to d.Workers files at once with the
+// algorithms of m. The results don't depend on the number of workers.
+// ctx.Err() is returned if ctx is done before every file is verified.
+func (d *DirHasher) Verify(ctx context.Context, fsys fs.FS, m *Manifest) ([]FileResult, error) {
+	if len(m.Algorithms) == 0 && len(m.Files) > 0 {
+		return nil, errors.New("hashio: manifest has no algorithms, so its files can't be verified")
+	}
+	for name, e := range m.Files {
+		for alg := range e.Sums {
+			if !slices.Contains(m.Algorithms, alg) {
+				return nil, fmt.Errorf("hashio: manifest entry %q has a %s digest but the manifest algorithms are %v", name, alg, m.Algorithms)
+			}
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		results []FileResult
+		seen    = make(map[string]bool, len(m.Files))
+	)
+	err := d.walk(ctx, fsys, m.Algorithms, m.Ignore, func(h *HashWriter, buf []byte, name string, _ fs.DirEntry) error {
+		res := FileResult{Path: name, Status: FileUnexpected, Size: -1}
+		want, ok := m.Files[name]
+		if ok {
+			res = verifyFSFile(ctx, fsys, name, m.Algorithms, want, h, buf)
+		}
+		mu.Lock()
+		seen[name] = ok
+		results = append(results, res)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range m.Files {
+		if !seen[name] {
+			results = append(results, FileResult{Path: name, Status: FileMissing, Size: -1})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, nil
+}
+
+// verifyFSFile checks the file of fsys at name against want, hashing it with
+// h through buf until ctx is done. The digest of every algorithm in algs must
+// match, so an entry missing one can't pass on its size alone.
+func verifyFSFile(ctx context.Context, fsys fs.FS, name string, algs []string, want ManifestEntry, h *HashWriter, buf []byte) FileResult {
+	res := FileResult{Path: name, Size: -1}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		res.Status, res.Err = FileError, err
+		return res
+	}
+	if info.Size() != want.Size {
+		res.Status, res.Size = FileModified, info.Size()
+		return res
+	}
+
+	got, err := hashFSFile(ctx, fsys, name, h, buf)
+	if err != nil {
+		res.Status, res.Err = FileError, err
+		return res
+	}
+	res.Size = got.Size
+	for _, alg := range algs {
+		sum, ok := want.Sums[alg]
+		if !ok || subtle.ConstantTimeCompare(got.Sums[alg], sum) != 1 {
+			res.Mismatched = append(res.Mismatched, alg)
+		}
+	}
+	slices.Sort(res.Mismatched)
+	if len(res.Mismatched) > 0 || got.Size != want.Size {
+		res.Status = FileModified
+	}
+	return res
+}
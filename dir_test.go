@@ -0,0 +1,370 @@
+package hashio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var testDir = fstest.MapFS{
+	"foo.txt":       {Data: []byte("foo\n")},
+	"sub/bar.bin":   {Data: []byte("bar")},
+	"sub/empty":     {Data: nil},
+	"sub/deeper/.x": {Data: []byte("foo\n")},
+}
+
+func TestHashDir(t *testing.T) {
+	m, err := HashDir(testDir, SHA256, MD5)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	foo := ManifestEntry{Size: 4, Sums: Results{
+		SHA256: mustDecodeHex("b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"),
+		MD5:    mustDecodeHex("d3b07384d113edec49eaa6238ad5ff00"),
+	}}
+	want := &Manifest{
+		Algorithms: []string{MD5, SHA256},
+		Files: map[string]ManifestEntry{
+			"foo.txt":       foo,
+			"sub/deeper/.x": foo,
+			"sub/bar.bin": {Size: 3, Sums: Results{
+				SHA256: mustDecodeHex("fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"),
+				MD5:    mustDecodeHex("37b51d194a7513e45b56f6524f2d51f2"),
+			}},
+			"sub/empty": {Size: 0, Sums: Results{
+				SHA256: mustDecodeHex("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
+				MD5:    mustDecodeHex("d41d8cd98f00b204e9800998ecf8427e"),
+			}},
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("HashDir() got: %+v, wanted %+v", m, want)
+	}
+
+	if _, err := HashDir(testDir, "nope"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("HashDir(nope) got: %v, wanted %v", err, ErrUnknownHash)
+	}
+	if _, err := HashDir(fstest.MapFS{}, SHA256); err != nil {
+		t.Errorf("HashDir() of an empty tree got: %v, wanted nil", err)
+	}
+}
+
+func TestHashDirSkipsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "foo.txt"), []byte("foo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("foo.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	m, err := HashDir(os.DirFS(dir), SHA256)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	if _, ok := m.Files["link"]; ok || len(m.Files) != 1 {
+		t.Errorf("HashDir() got files %v, wanted only foo.txt", m.Files)
+	}
+}
+
+func TestVerifyDir(t *testing.T) {
+	m, err := HashDir(testDir, SHA256, MD5)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+
+	changed := fstest.MapFS{
+		"foo.txt":       {Data: []byte("foo\n")},
+		"sub/bar.bin":   {Data: []byte("baz")},
+		"sub/empty":     {Data: []byte("now full")},
+		"sub/new.txt":   {Data: []byte("new")},
+		"sub/deeper/.x": {Data: []byte("foo\n")},
+	}
+	delete(m.Files, "sub/deeper/.x")
+	m.Files["gone"] = m.Files["foo.txt"]
+	results, err := VerifyDir(changed, m)
+	if err != nil {
+		t.Fatalf("VerifyDir(): %v", err)
+	}
+	want := []FileResult{
+		{Path: "foo.txt", Status: FileOK, Size: 4},
+		{Path: "gone", Status: FileMissing, Size: -1},
+		{Path: "sub/bar.bin", Status: FileModified, Size: 3, Mismatched: []string{MD5, SHA256}},
+		{Path: "sub/deeper/.x", Status: FileUnexpected, Size: -1},
+		{Path: "sub/empty", Status: FileModified, Size: 8},
+		{Path: "sub/new.txt", Status: FileUnexpected, Size: -1},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("VerifyDir() got: %+v, wanted %+v", results, want)
+	}
+
+	results, err = VerifyDir(failingFS{testDir, ""}, &Manifest{Algorithms: []string{SHA256}, Files: map[string]ManifestEntry{"foo.txt": {Size: 4}}})
+	if err != nil {
+		t.Fatalf("VerifyDir() of an unreadable file: %v", err)
+	}
+	if r := results[0]; r.Path != "foo.txt" || r.Status != FileError || !errors.Is(r.Err, errFailingFS) {
+		t.Errorf("VerifyDir() of an unreadable file got: %+v, wanted a FileError", r)
+	}
+	if got := FileError.String(); got != "error" {
+		t.Errorf("FileError.String() got: %q, wanted %q", got, "error")
+	}
+
+	if _, err := VerifyDir(testDir, &Manifest{Algorithms: []string{"nope"}}); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("VerifyDir() with an unknown algorithm got: %v, wanted %v", err, ErrUnknownHash)
+	}
+	// An entry without digests can't pass on its size alone.
+	tampered := fstest.MapFS{"a.txt": {Data: []byte("evil")}}
+	noSums := &Manifest{Algorithms: []string{MD5, SHA256}, Files: map[string]ManifestEntry{"a.txt": {Size: 4, Sums: Results{MD5: mustDecodeHex("d3b07384d113edec49eaa6238ad5ff00")}}}}
+	results, err = VerifyDir(tampered, noSums)
+	if err != nil {
+		t.Fatalf("VerifyDir() of an entry missing a digest: %v", err)
+	}
+	if want := []FileResult{{Path: "a.txt", Status: FileModified, Size: 4, Mismatched: []string{MD5, SHA256}}}; !reflect.DeepEqual(results, want) {
+		t.Errorf("VerifyDir() of an entry missing a digest got: %+v, wanted %+v", results, want)
+	}
+	if _, err := VerifyDir(tampered, &Manifest{Files: map[string]ManifestEntry{"a.txt": {Size: 4}}}); err == nil {
+		t.Errorf("VerifyDir() of a manifest without algorithms got: nil, wanted an error")
+	}
+
+	bad := &Manifest{Algorithms: []string{SHA256}, Files: map[string]ManifestEntry{"foo.txt": {Size: 4, Sums: Results{MD5: nil}}}}
+	if _, err := VerifyDir(testDir, bad); err == nil {
+		t.Errorf("VerifyDir() with an MD5 digest in a SHA256 manifest got: nil, wanted an error")
+	}
+}
+
+var errFailingFS = errors.New("read failed")
+
+// failingFS fails reads of its files whose name contains fail, or of all of
+// them if fail is empty.
+type failingFS struct {
+	fs.FS
+	fail string
+}
+
+func (f failingFS) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := file.Stat(); err == nil && info.IsDir() || !strings.Contains(name, f.fail) {
+		return file, nil
+	}
+	return failingFile{file, name}, nil
+}
+
+type failingFile struct {
+	fs.File
+	name string
+}
+
+func (f failingFile) Read([]byte) (int, error) { return 0, fmt.Errorf("%w: %s", errFailingFS, f.name) }
+
+// manyFiles returns a tree of n small files in nested directories.
+func manyFiles(n int) fstest.MapFS {
+	fsys := make(fstest.MapFS, n)
+	for i := 0; i < n; i++ {
+		fsys[fmt.Sprintf("d%d/e%d/f%d", i%7, i%3, i)] = &fstest.MapFile{Data: bytes.Repeat([]byte{byte(i)}, i)}
+	}
+	return fsys
+}
+
+func TestDirHasher(t *testing.T) {
+	fsys := manyFiles(500)
+	want, err := HashDir(fsys, SHA256, XXH3)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	for _, workers := range []int{-1, 0, 1, 3, 16} {
+		d := DirHasher{Algorithms: []string{XXH3, SHA256}, Workers: workers}
+		m, err := d.Hash(context.Background(), fsys)
+		if err != nil {
+			t.Fatalf("DirHasher{Workers: %d}.Hash(): %v", workers, err)
+		}
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("DirHasher{Workers: %d}.Hash() differs from HashDir()", workers)
+		}
+
+		fsys["d0/e0/f0"] = &fstest.MapFile{Data: []byte("changed")}
+		results, err := d.Verify(context.Background(), fsys, want)
+		if err != nil {
+			t.Fatalf("DirHasher{Workers: %d}.Verify(): %v", workers, err)
+		}
+		sequential, _ := VerifyDir(fsys, want)
+		if len(results) != 500 || !reflect.DeepEqual(results, sequential) {
+			t.Errorf("DirHasher{Workers: %d}.Verify() differs from VerifyDir()", workers)
+		}
+		if r := results[0]; r.Path != "d0/e0/f0" || r.Status != FileModified {
+			t.Errorf("DirHasher{Workers: %d}.Verify() got %+v for the changed file, wanted it modified", workers, r)
+		}
+		fsys["d0/e0/f0"] = &fstest.MapFile{}
+	}
+}
+
+func TestDirHasherErrors(t *testing.T) {
+	// Every file of d3 fails; the error is that of the first in lexical
+	// order whatever the number of workers.
+	fsys := failingFS{manyFiles(200), "d3/"}
+	for _, workers := range []int{1, 4, 32} {
+		d := DirHasher{Workers: workers}
+		if _, err := d.Hash(context.Background(), fsys); err == nil || err.Error() != errFailingFS.Error()+": d3/e0/f108" {
+			t.Errorf("DirHasher{Workers: %d}.Hash() got: %v, wanted the error of d3/e0/f108", workers, err)
+		}
+	}
+
+	d := DirHasher{Algorithms: []string{"nope"}, Workers: 4}
+	if _, err := d.Hash(context.Background(), testDir); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("DirHasher.Hash() with an unknown algorithm got: %v, wanted %v", err, ErrUnknownHash)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d = DirHasher{Workers: 4}
+	if _, err := d.Hash(ctx, manyFiles(100)); err != context.Canceled {
+		t.Errorf("DirHasher.Hash() with a canceled context got: %v, wanted %v", err, context.Canceled)
+	}
+	m, _ := HashDir(testDir)
+	if _, err := d.Verify(ctx, testDir, m); err != context.Canceled {
+		t.Errorf("DirHasher.Verify() with a canceled context got: %v, wanted %v", err, context.Canceled)
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// openLog records the names of the files opened from its fs.FS.
+type openLog struct {
+	fs.FS
+	mu     sync.Mutex
+	opened []string
+}
+
+func (o *openLog) Open(name string) (fs.File, error) {
+	f, err := o.FS.Open(name)
+	if err == nil {
+		if info, err := f.Stat(); err == nil && !info.IsDir() {
+			o.mu.Lock()
+			o.opened = append(o.opened, name)
+			o.mu.Unlock()
+		}
+	}
+	return f, err
+}
+
+func TestUpdateDir(t *testing.T) {
+	t0 := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	fsys := fstest.MapFS{
+		"same":      {Data: []byte("same"), ModTime: t0},
+		"touched":   {Data: []byte("touched"), ModTime: t0},
+		"grown":     {Data: []byte("grown"), ModTime: t0},
+		"sneaky":    {Data: []byte("sneaky"), ModTime: t0},
+		"sub/gone":  {Data: []byte("gone"), ModTime: t0},
+		"sub/other": {Data: []byte("other"), ModTime: t0},
+	}
+	prev, err := HashDir(fsys, SHA256)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	if e := prev.Files["same"]; !e.ModTime.Equal(t0) {
+		t.Errorf("HashDir() got ModTime %v, wanted %v", e.ModTime, t0)
+	}
+
+	fsys["touched"].ModTime = t0.Add(time.Second)
+	fsys["grown"].Data = []byte("grown up")
+	// Same size and time: the change goes unnoticed, as documented.
+	fsys["sneaky"].Data = []byte("SNEAKY")
+	delete(fsys, "sub/gone")
+	fsys["sub/new"] = &fstest.MapFile{Data: []byte("new"), ModTime: t0}
+
+	log := &openLog{FS: fsys}
+	m, err := UpdateDir(log, prev, SHA256)
+	if err != nil {
+		t.Fatalf("UpdateDir(): %v", err)
+	}
+	if want := []string{"grown", "sub/new", "touched"}; !reflect.DeepEqual(log.opened, want) {
+		t.Errorf("UpdateDir() hashed %q, wanted %q", log.opened, want)
+	}
+	full, _ := HashDir(fsys, SHA256)
+	full.Files["sneaky"] = prev.Files["sneaky"]
+	if !reflect.DeepEqual(m, full) {
+		t.Errorf("UpdateDir() got: %+v, wanted %+v", m, full)
+	}
+
+	// Digests of algorithms missing from prev are computed, and those of
+	// others dropped.
+	log.opened = nil
+	m, err = UpdateDir(log, m, MD5)
+	if err != nil {
+		t.Fatalf("UpdateDir(md5): %v", err)
+	}
+	if len(log.opened) != len(fsys) || len(m.Files["same"].Sums) != 1 || m.Files["same"].Sums[MD5] == nil {
+		t.Errorf("UpdateDir(md5) hashed %q and got %+v, wanted every file hashed with MD5 only", log.opened, m.Files["same"])
+	}
+
+	d := DirHasher{Algorithms: []string{SHA256}, Workers: 4}
+	fresh, _ := HashDir(fsys, SHA256)
+	if m, err := d.Update(context.Background(), fsys, nil); err != nil || !reflect.DeepEqual(m, fresh) {
+		t.Errorf("DirHasher.Update() with no previous manifest got: %+v, %v, wanted the result of HashDir()", m, err)
+	}
+}
+
+func TestUpdateDirCheckInode(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "foo.txt")
+	if err := os.WriteFile(name, []byte("foo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fsys := os.DirFS(dir)
+	prev, err := HashDir(fsys, SHA256)
+	if err != nil {
+		t.Fatalf("HashDir(): %v", err)
+	}
+	e := prev.Files["foo.txt"]
+	if e.Inode == 0 {
+		t.Skip("no inode numbers")
+	}
+
+	// Replace the file with another of the same size and time.
+	tmp := filepath.Join(dir, "tmp")
+	if err := os.WriteFile(tmp, []byte("bar\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(tmp, e.ModTime, e.ModTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := UpdateDir(fsys, prev, SHA256)
+	if err != nil {
+		t.Fatalf("UpdateDir(): %v", err)
+	}
+	if !reflect.DeepEqual(m.Files["foo.txt"], e) {
+		t.Errorf("UpdateDir() got: %+v, wanted the previous entry %+v", m.Files["foo.txt"], e)
+	}
+	d := DirHasher{Algorithms: []string{SHA256}, CheckInode: true}
+	m, err = d.Update(context.Background(), fsys, prev)
+	if err != nil {
+		t.Fatalf("DirHasher{CheckInode: true}.Update(): %v", err)
+	}
+	want := sha256.Sum256([]byte("bar\n"))
+	if got := m.Files["foo.txt"]; got.Inode == e.Inode || !bytes.Equal(got.Sums[SHA256], want[:]) {
+		t.Errorf("DirHasher{CheckInode: true}.Update() got: %+v, wanted a new inode and sha256 %x", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package hashio
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// Encoder renders digests as text. *base64.Encoding and *base32.Encoding
+// implement it.
+type Encoder interface {
+	EncodeToString(src []byte) string
+}
+
+// EncoderFunc adapts a function to the Encoder interface.
+type EncoderFunc func(src []byte) string
+
+// EncodeToString returns f(src).
+func (f EncoderFunc) EncodeToString(src []byte) string { return f(src) }
+
+// Names of the built in encodings, for LookupEncoding.
+const (
+	EncodingHex       = "hex"       // lowercase hex, the default
+	EncodingHexUpper  = "hex-upper" // uppercase hex
+	EncodingBase64    = "base64"    // standard, padded base64
+	EncodingBase64URL = "base64url" // URL safe, padded base64
+	EncodingBase32    = "base32"    // standard, padded base32
+	EncodingBase32Hex = "base32hex" // "extended hex", padded base32
+	EncodingBase58    = "base58"    // base58 with the Bitcoin alphabet
+	EncodingZBase32   = "z-base-32" // human oriented base32, unpadded
+)
+
+// hexEncoder is the default encoding.
+var hexEncoder Encoder = EncoderFunc(hex.EncodeToString)
+
+// encodingsMu guards encodings, which RegisterEncoding adds to at run time.
+var encodingsMu sync.RWMutex
+
+var encodings = map[string]Encoder{
+	EncodingHex:       hexEncoder,
+	EncodingHexUpper:  EncoderFunc(func(src []byte) string { return strings.ToUpper(hex.EncodeToString(src)) }),
+	EncodingBase64:    base64.StdEncoding,
+	EncodingBase64URL: base64.URLEncoding,
+	EncodingBase32:    base32.StdEncoding,
+	EncodingBase32Hex: base32.HexEncoding,
+	EncodingBase58:    EncoderFunc(encodeBase58),
+	EncodingZBase32:   base32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769").WithPadding(base32.NoPadding),
+}
+
+// RegisterEncoding makes e available under name to LookupEncoding, so that
+// encodings can be selected from strings such as configuration values.
+// Registering a name that is already known, including that of a built in
+// encoding, replaces it. RegisterEncoding panics if name is empty or e is nil.
+// It is safe to call concurrently with LookupEncoding.
+func RegisterEncoding(name string, e Encoder) {
+	if name == "" || e == nil {
+		panic("hashio: RegisterEncoding needs a name and an Encoder")
+	}
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encodings[name] = e
+}
+
+// LookupEncoding returns the encoding registered under name: one of the
+// Encoding constants or a name passed to RegisterEncoding.
+func LookupEncoding(name string) (Encoder, bool) {
+	encodingsMu.RLock()
+	defer encodingsMu.RUnlock()
+	e, ok := encodings[name]
+	return e, ok
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// encodeBase58 encodes src as a big-endian number in base 58, each leading
+// zero byte becoming a leading "1".
+func encodeBase58(src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+	// Digits in base 58, least significant first. log(256)/log(58) < 1.37.
+	digits := make([]byte, 0, len(src)*137/100+1)
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for ; carry > 0; carry /= 58 {
+			digits = append(digits, byte(carry%58))
+		}
+	}
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
+
+// WithEncoding makes the EncodedHash, LookupEncodedHash and EncodedSums
+// accessors of a HashReader or HashWriter render digests with e instead of
+// lowercase hex. The accessors named after an encoding, such as HexHash and
+// Base64Hash, are unaffected.
+func WithEncoding(e Encoder) Option {
+	return func(c *config) {
+		c.enc = e
+	}
+}
+
+// encoder returns enc, or lowercase hex if it is nil.
+func encoder(enc Encoder) Encoder {
+	if enc == nil {
+		return hexEncoder
+	}
+	return enc
+}
+
+// encodeSums returns the digest of every hash in hashers encoded with enc.
+func encodeSums(hashers map[string]hash.Hash, enc Encoder) map[string]string {
+	m := make(map[string]string, len(hashers))
+	for name, h := range hashers {
+		m[name] = enc.EncodeToString(h.Sum(nil))
+	}
+	return m
+}
+
+// EncodedHash returns the hash identified by name rendered with the encoding
+// set by WithEncoding, lowercase hex by default. It panics and returns the
+// empty string in the same cases as HexHash.
+func (h *HashReader) EncodedHash(name string) string {
+	if h.err != nil {
+		return ""
+	}
+	return encoder(h.enc).EncodeToString(h.Hash(name, nil))
+}
+
+// LookupEncodedHash is like EncodedHash but returns the errors of LookupHash
+// instead of panicking.
+func (h *HashReader) LookupEncodedHash(name string) (string, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return "", err
+	}
+	return encoder(h.enc).EncodeToString(sum), nil
+}
+
+// EncodedSums is like Sums but each digest is rendered with the encoding set
+// by WithEncoding, lowercase hex by default.
+func (h *HashReader) EncodedSums() map[string]string {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return encodeSums(h.hashers, encoder(h.enc))
+}
+
+// EncodedHash returns the hash identified by name rendered with the encoding
+// set by WithEncoding, lowercase hex by default. It panics and returns the
+// empty string in the same cases as HexHash.
+func (h *HashWriter) EncodedHash(name string) string {
+	if h.err != nil {
+		return ""
+	}
+	return encoder(h.enc).EncodeToString(h.Hash(name, nil))
+}
+
+// LookupEncodedHash is like EncodedHash but returns the errors of LookupHash
+// instead of panicking.
+func (h *HashWriter) LookupEncodedHash(name string) (string, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return "", err
+	}
+	return encoder(h.enc).EncodeToString(sum), nil
+}
+
+// EncodedSums is like Sums but each digest is rendered with the encoding set
+// by WithEncoding, lowercase hex by default.
+func (h *HashWriter) EncodedSums() map[string]string {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return encodeSums(h.hashers, encoder(h.enc))
+}
+
+// Base64Hash returns the hash identified by name encoded with standard, padded
+// base64 as used by Content-MD5 headers and GCS object metadata. It panics and
+// returns the empty string in the same cases as HexHash.
+func (h *HashReader) Base64Hash(name string) string {
+	return base64.StdEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base64URLHash is like Base64Hash but uses the URL and filename safe base64
+// alphabet.
+func (h *HashReader) Base64URLHash(name string) string {
+	return base64.URLEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base32Hash returns the hash identified by name encoded with standard, padded
+// base32. It panics and returns the empty string in the same cases as HexHash.
+func (h *HashReader) Base32Hash(name string) string {
+	return base32.StdEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base32HexHash is like Base32Hash but uses the "extended hex" base32 alphabet,
+// which preserves the sort order of the digests.
+func (h *HashReader) Base32HexHash(name string) string {
+	return base32.HexEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base64Hash returns the hash identified by name encoded with standard, padded
+// base64 as used by Content-MD5 headers and GCS object metadata. It panics and
+// returns the empty string in the same cases as HexHash.
+func (h *HashWriter) Base64Hash(name string) string {
+	return base64.StdEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base64URLHash is like Base64Hash but uses the URL and filename safe base64
+// alphabet.
+func (h *HashWriter) Base64URLHash(name string) string {
+	return base64.URLEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base32Hash returns the hash identified by name encoded with standard, padded
+// base32. It panics and returns the empty string in the same cases as HexHash.
+func (h *HashWriter) Base32Hash(name string) string {
+	return base32.StdEncoding.EncodeToString(h.Hash(name, nil))
+}
+
+// Base32HexHash is like Base32Hash but uses the "extended hex" base32 alphabet,
+// which preserves the sort order of the digests.
+func (h *HashWriter) Base32HexHash(name string) string {
+	return base32.HexEncoding.EncodeToString(h.Hash(name, nil))
+}
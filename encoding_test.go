@@ -0,0 +1,119 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBaseEncodedHashes(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write(contents)
+	hr := NewHashReader(bytes.NewReader(contents), StdCryptoHashes())
+	ioutil.ReadAll(hr)
+
+	// Values computed with openssl dgst -binary piped to base64 and basenc.
+	tests := []struct {
+		method string
+		fn     func(string) string
+		name   string
+		want   string
+	}{
+		{"HashWriter.Base64Hash", hw.Base64Hash, SHA256, "U1pkO6KvHwJ+NwuedOsII+qIYyLvDJZzPjdNfuM0olg="},
+		{"HashWriter.Base64URLHash", hw.Base64URLHash, SHA256, "U1pkO6KvHwJ-NwuedOsII-qIYyLvDJZzPjdNfuM0olg="},
+		{"HashWriter.Base32Hash", hw.Base32Hash, MD5, "GHXA3EQFAYRWGGNOLYIAL5EOIU======"},
+		{"HashWriter.Base32HexHash", hw.Base32HexHash, MD5, "67N0R4G50OHM66DEBO80BT4E8K======"},
+		{"HashReader.Base64Hash", hr.Base64Hash, SHA1, "xEentFzFQ+H7J1nVclXEswDGvx4="},
+		{"HashReader.Base64URLHash", hr.Base64URLHash, SHA1, "xEentFzFQ-H7J1nVclXEswDGvx4="},
+		{"HashReader.Base32Hash", hr.Base32Hash, MD5, "GHXA3EQFAYRWGGNOLYIAL5EOIU======"},
+		{"HashReader.Base32HexHash", hr.Base32HexHash, MD5, "67N0R4G50OHM66DEBO80BT4E8K======"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.name); got != tt.want {
+			t.Errorf("%s(%s) got: %q, wanted %q", tt.method, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{EncodingHexUpper, "\xb9\x4d", "B94D"},
+		{EncodingZBase32, "hello world", "pb1sa5dxrb5s6hucco"},
+		// Vectors from Bitcoin Core.
+		{EncodingBase58, "", ""},
+		{EncodingBase58, "a", "2g"},
+		{EncodingBase58, "bbb", "a3gV"},
+		{EncodingBase58, "\x00\x00\x28\x7f\xb4\xcd", "11233QC4"},
+		{EncodingBase58, "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00", "1111111111"},
+		{EncodingBase58, "hello world", "StV1DL6CwTryKyV"},
+	} {
+		e, ok := LookupEncoding(tc.name)
+		if !ok {
+			t.Fatalf("LookupEncoding(%q) got: false, wanted true", tc.name)
+		}
+		if got := e.EncodeToString([]byte(tc.src)); got != tc.want {
+			t.Errorf("%s EncodeToString(%q) got: %q, wanted %q", tc.name, tc.src, got, tc.want)
+		}
+	}
+	if _, ok := LookupEncoding("missing"); ok {
+		t.Errorf("LookupEncoding(missing) got: true, wanted false")
+	}
+}
+
+func TestWithEncoding(t *testing.T) {
+	const sha256Hex = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9" // sha256("hello world")
+	w := NewWriter(nil, WithSHA256())
+	w.WriteString("hello world")
+	if got := w.EncodedHash(SHA256); got != sha256Hex {
+		t.Errorf("HashWriter.EncodedHash(sha256) without WithEncoding got: %q, wanted %q", got, sha256Hex)
+	}
+
+	RegisterEncoding("reversed-hex", EncoderFunc(func(src []byte) string {
+		b := []byte(hex.EncodeToString(src))
+		slices.Reverse(b)
+		return string(b)
+	}))
+	defer func() {
+		encodingsMu.Lock()
+		defer encodingsMu.Unlock()
+		delete(encodings, "reversed-hex")
+	}()
+	for _, tc := range []struct {
+		encoding string
+		want     string
+	}{
+		{EncodingHexUpper, "B94D27B9934D3E08A52E52D7DA7DABFAC484EFE37A5380EE9088F7ACE2EFCDE9"},
+		{EncodingBase64, "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="},
+		{"reversed-hex", "9edcfe2eca7f8809ee0835a73efe484cafbad7ad7d25e25a80e3d4399b72d49b"},
+	} {
+		e, _ := LookupEncoding(tc.encoding)
+		r := NewReader(strings.NewReader("hello world"), WithSHA256(), WithEncoding(e))
+		io.ReadAll(r)
+		if got := r.EncodedHash(SHA256); got != tc.want {
+			t.Errorf("HashReader.EncodedHash(sha256) with %s got: %q, wanted %q", tc.encoding, got, tc.want)
+		}
+		if got, err := r.LookupEncodedHash(SHA256); got != tc.want || err != nil {
+			t.Errorf("HashReader.LookupEncodedHash(sha256) with %s got: %q, %v, wanted %q, nil", tc.encoding, got, err, tc.want)
+		}
+		if got := r.EncodedSums()[SHA256]; got != tc.want {
+			t.Errorf("HashReader.EncodedSums()[sha256] with %s got: %q, wanted %q", tc.encoding, got, tc.want)
+		}
+		// HexHash is always hex.
+		if got := r.HexHash(SHA256); got != sha256Hex {
+			t.Errorf("HashReader.HexHash(sha256) with %s got: %q, wanted %q", tc.encoding, got, sha256Hex)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package ext registers hash algorithms that hashio doesn't support out of
+// the box, for interoperability with tools that still require them:
+// RIPEMD-160 (Bitcoin addresses, OpenPGP), SM3 (the Chinese national
+// standard GB/T 32905) and Whirlpool (ISO/IEC 10118-3).
+//
+// Importing the package, usually for its side effect only, registers the
+// algorithms with hashio.Register under the names below:
+//
+//	import _ "github.com/mikewiacek/hashio/ext"
+//
+//	r, err := hashio.NewByNames(f, hashio.SHA256, ext.SM3)
+//
+// Keeping them in a separate package keeps programs that don't need them
+// from linking them. None of them should be used in new designs.
+package ext
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// Names under which the algorithms are registered.
+const (
+	RIPEMD160 hashio.Algorithm = "ripemd160"
+	SM3       hashio.Algorithm = "sm3"
+	Whirlpool hashio.Algorithm = "whirlpool"
+)
+
+func init() {
+	hashio.Register(RIPEMD160, NewRIPEMD160)
+	hashio.Register(SM3, NewSM3)
+	hashio.Register(Whirlpool, NewWhirlpool)
+}
+
+// blockSize is the block size of all the algorithms of this package.
+const blockSize = 64
+
+var errInvalidState = errors.New("ext: invalid hash state")
+
+// buffer accumulates input into the blocks of a Merkle-Damgård hash.
+type buffer struct {
+	x   [blockSize]byte
+	nx  int    // bytes in x
+	len uint64 // bytes written
+}
+
+// write adds p to the buffer, passing every complete block to block.
+func (b *buffer) write(p []byte, block func([]byte)) {
+	b.len += uint64(len(p))
+	if b.nx > 0 {
+		k := copy(b.x[b.nx:], p)
+		b.nx += k
+		p = p[k:]
+		if b.nx < blockSize {
+			return
+		}
+		block(b.x[:])
+		b.nx = 0
+	}
+	if n := len(p) &^ (blockSize - 1); n > 0 {
+		block(p[:n])
+		p = p[n:]
+	}
+	b.nx = copy(b.x[:], p)
+}
+
+// pad writes the padding of the algorithms: a one bit, zeros, then the
+// length of the input in bits in a field of lenField bytes, big-endian or
+// little-endian, ending the last block.
+func (b *buffer) pad(lenField int, bigEndian bool, block func([]byte)) {
+	bits := b.len << 3
+	var tmp [2 * blockSize]byte
+	tmp[0] = 0x80
+	n := 1 + (blockSize-(b.nx+1+lenField)%blockSize)%blockSize
+	if bigEndian {
+		binary.BigEndian.PutUint64(tmp[n+lenField-8:], bits)
+	} else {
+		binary.LittleEndian.PutUint64(tmp[n:], bits)
+	}
+	b.write(tmp[:n+lenField], block)
+}
+
+// appendBuffer appends the length and the buffered bytes to state.
+func (b *buffer) appendBuffer(state []byte) []byte {
+	state = binary.BigEndian.AppendUint64(state, b.len)
+	return append(state, b.x[:b.nx]...)
+}
+
+// readBuffer restores what appendBuffer appended to a state.
+func (b *buffer) readBuffer(state []byte) error {
+	if len(state) < 8 {
+		return errInvalidState
+	}
+	b.len = binary.BigEndian.Uint64(state)
+	state = state[8:]
+	if len(state) >= blockSize || uint64(len(state)) != b.len%blockSize {
+		return errInvalidState
+	}
+	b.nx = copy(b.x[:], state)
+	return nil
+}
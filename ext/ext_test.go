@@ -0,0 +1,109 @@
+package ext
+
+import (
+	"encoding"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+func testInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+// Vectors from the specifications, and computed with reference
+// implementations for the 200 byte input.
+var testVectors = []struct {
+	name  string
+	new   func() hash.Hash
+	input []byte
+	want  string
+}{
+	{RIPEMD160, NewRIPEMD160, nil, "9c1185a5c5e9fc54612808977ee8f548b2258d31"},
+	{RIPEMD160, NewRIPEMD160, []byte("abc"), "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+	{RIPEMD160, NewRIPEMD160, testInput(200), "c315823ea8fe07a2dd18de4e545255afe3af0738"},
+	{SM3, NewSM3, nil, "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+	{SM3, NewSM3, []byte("abc"), "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+	{SM3, NewSM3, testInput(200), "137c8be9a568df1f999ea75e042359e582990c708027d61f20489a368bf5ced5"},
+	{Whirlpool, NewWhirlpool, nil, "19fa61d75522a4669b44e39c1d2e1726c530232130d407f89afee0964997f7a73e83be698b288febcf88e3e03c4f0757ea8964e59b63d93708b138cc42a66eb3"},
+	{Whirlpool, NewWhirlpool, []byte("abc"), "4e2448a4c6f486bb16b6562c73b4020bf3043e3a731bce721ae1b303d97e6d4c7181eebdb6c57e277d0e34957114cbd6c797fc9d95d8b582d225292076d4eef5"},
+	{Whirlpool, NewWhirlpool, testInput(200), "50cc69782191cb4bda8975391ee7307ba29911d617cc162286864ed40e1e426c90861ff3b48ad8ab966891ef4862441f8747ccbf4d38a0959a13bb9bece698d6"},
+}
+
+func TestVectors(t *testing.T) {
+	for _, tc := range testVectors {
+		h := tc.new()
+		// Write in uneven pieces to cross block boundaries.
+		for p := tc.input; len(p) > 0; {
+			k := min(len(p), 37)
+			h.Write(p[:k])
+			p = p[k:]
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("%s of %d bytes got: %q, wanted %q", tc.name, len(tc.input), got, tc.want)
+		}
+		if h.Size() != len(tc.want)/2 {
+			t.Errorf("%s Size() got: %d, wanted %d", tc.name, h.Size(), len(tc.want)/2)
+		}
+		h.Reset()
+		h.Write(tc.input)
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.want {
+			t.Errorf("%s of %d bytes after Reset got: %q, wanted %q", tc.name, len(tc.input), got, tc.want)
+		}
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, tc := range testVectors {
+		for _, split := range []int{0, len(tc.input) / 3, len(tc.input)} {
+			h := tc.new()
+			h.Write(tc.input[:split])
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s MarshalBinary(): %v", tc.name, err)
+			}
+			resumed := tc.new()
+			if err := resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatalf("%s UnmarshalBinary(): %v", tc.name, err)
+			}
+			resumed.Write(tc.input[split:])
+			if got := hex.EncodeToString(resumed.Sum(nil)); got != tc.want {
+				t.Errorf("%s resumed at %d of %d bytes got: %q, wanted %q", tc.name, split, len(tc.input), got, tc.want)
+			}
+
+			clone, _ := h.(hash.Cloner).Clone()
+			clone.Write(tc.input[split:])
+			if got := hex.EncodeToString(clone.Sum(nil)); got != tc.want {
+				t.Errorf("%s cloned at %d of %d bytes got: %q, wanted %q", tc.name, split, len(tc.input), got, tc.want)
+			}
+		}
+	}
+
+	state, _ := NewSM3().(encoding.BinaryMarshaler).MarshalBinary()
+	if err := NewWhirlpool().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("Whirlpool UnmarshalBinary(SM3 state) got no error")
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	r, err := hashio.NewByNames(strings.NewReader("abc"), hashio.SHA256, RIPEMD160, SM3, Whirlpool)
+	if err != nil {
+		t.Fatalf("hashio.NewByNames(): %v", err)
+	}
+	io.ReadAll(r)
+	for _, tc := range testVectors {
+		if string(tc.input) == "abc" {
+			if got := r.HexHash(tc.name); got != tc.want {
+				t.Errorf("HashReader.HexHash(%s) got: %q, wanted %q", tc.name, got, tc.want)
+			}
+		}
+	}
+}
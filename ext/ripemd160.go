@@ -0,0 +1,148 @@
+package ext
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// RIPEMD160Size is the size of a RIPEMD-160 digest in bytes.
+const RIPEMD160Size = 20
+
+// ripemd160 runs two parallel lines of 80 steps over every block. The tables
+// hold, for each step of the left and right lines, the message word used and
+// the rotation applied.
+var (
+	ripemdR = [80]uint8{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	ripemdRR = [80]uint8{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+	ripemdS = [80]uint8{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	ripemdSS = [80]uint8{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+	ripemdK  = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	ripemdKK = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// ripemd160 is a RIPEMD-160 hash.
+type ripemd160 struct {
+	s [5]uint32
+	buffer
+}
+
+// NewRIPEMD160 returns a RIPEMD-160 hash.
+func NewRIPEMD160() hash.Hash {
+	d := &ripemd160{}
+	d.Reset()
+	return d
+}
+
+func (d *ripemd160) Reset() {
+	d.s = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+	d.buffer = buffer{}
+}
+
+func (d *ripemd160) Size() int { return RIPEMD160Size }
+
+func (d *ripemd160) BlockSize() int { return blockSize }
+
+func (d *ripemd160) Write(p []byte) (int, error) {
+	d.write(p, d.block)
+	return len(p), nil
+}
+
+func (d *ripemd160) Sum(b []byte) []byte {
+	c := *d
+	c.pad(8, false, c.block)
+	for _, v := range c.s {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+// ripemdF is the boolean function of round j/16, which the right line uses
+// in reverse order.
+func ripemdF(round int, x, y, z uint32) uint32 {
+	switch round {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return x&y | ^x&z
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return x&z | y&^z
+	}
+	return x ^ (y | ^z)
+}
+
+func (d *ripemd160) block(p []byte) {
+	var x [16]uint32
+	for ; len(p) >= blockSize; p = p[blockSize:] {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(p[4*i:])
+		}
+		a, b, c, dd, e := d.s[0], d.s[1], d.s[2], d.s[3], d.s[4]
+		aa, bb, cc, ddd, ee := a, b, c, dd, e
+		for j := 0; j < 80; j++ {
+			round := j / 16
+			t := bits.RotateLeft32(a+ripemdF(round, b, c, dd)+x[ripemdR[j]]+ripemdK[round], int(ripemdS[j])) + e
+			a, e, dd, c, b = e, dd, bits.RotateLeft32(c, 10), b, t
+			t = bits.RotateLeft32(aa+ripemdF(4-round, bb, cc, ddd)+x[ripemdRR[j]]+ripemdKK[round], int(ripemdSS[j])) + ee
+			aa, ee, ddd, cc, bb = ee, ddd, bits.RotateLeft32(cc, 10), bb, t
+		}
+		d.s[0], d.s[1], d.s[2], d.s[3], d.s[4] =
+			d.s[1]+c+ddd, d.s[2]+dd+ee, d.s[3]+e+aa, d.s[4]+a+bb, d.s[0]+b+cc
+	}
+}
+
+const magicRIPEMD160 = "rmd160\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (d *ripemd160) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magicRIPEMD160)+5*4+8+blockSize)
+	b = append(b, magicRIPEMD160...)
+	for _, v := range d.s {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return d.appendBuffer(b), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *ripemd160) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magicRIPEMD160)+5*4 || string(b[:len(magicRIPEMD160)]) != magicRIPEMD160 {
+		return errInvalidState
+	}
+	b = b[len(magicRIPEMD160):]
+	for i := range d.s {
+		d.s[i], b = binary.BigEndian.Uint32(b), b[4:]
+	}
+	return d.readBuffer(b)
+}
+
+// Clone implements hash.Cloner.
+func (d *ripemd160) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
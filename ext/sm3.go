@@ -0,0 +1,115 @@
+package ext
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// SM3Size is the size of an SM3 digest in bytes.
+const SM3Size = 32
+
+// sm3 is an SM3 hash.
+type sm3 struct {
+	s [8]uint32
+	buffer
+}
+
+// NewSM3 returns an SM3 hash.
+func NewSM3() hash.Hash {
+	d := &sm3{}
+	d.Reset()
+	return d
+}
+
+func (d *sm3) Reset() {
+	d.s = [8]uint32{0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600, 0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e}
+	d.buffer = buffer{}
+}
+
+func (d *sm3) Size() int { return SM3Size }
+
+func (d *sm3) BlockSize() int { return blockSize }
+
+func (d *sm3) Write(p []byte) (int, error) {
+	d.write(p, d.block)
+	return len(p), nil
+}
+
+func (d *sm3) Sum(b []byte) []byte {
+	c := *d
+	c.pad(8, true, c.block)
+	for _, v := range c.s {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+func sm3P0(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17) }
+
+func sm3P1(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23) }
+
+func (d *sm3) block(p []byte) {
+	var w [68]uint32
+	for ; len(p) >= blockSize; p = p[blockSize:] {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint32(p[4*i:])
+		}
+		for i := 16; i < 68; i++ {
+			w[i] = sm3P1(w[i-16]^w[i-9]^bits.RotateLeft32(w[i-3], 15)) ^ bits.RotateLeft32(w[i-13], 7) ^ w[i-6]
+		}
+		a, b, c, dd, e, f, g, h := d.s[0], d.s[1], d.s[2], d.s[3], d.s[4], d.s[5], d.s[6], d.s[7]
+		for j := 0; j < 64; j++ {
+			var t, ff, gg uint32
+			if j < 16 {
+				t, ff, gg = 0x79cc4519, a^b^c, e^f^g
+			} else {
+				t, ff, gg = 0x7a879d8a, a&b|a&c|b&c, e&f|^e&g
+			}
+			a12 := bits.RotateLeft32(a, 12)
+			ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j), 7)
+			tt1 := ff + dd + (ss1 ^ a12) + (w[j] ^ w[j+4])
+			tt2 := gg + h + ss1 + w[j]
+			dd, c, b, a = c, bits.RotateLeft32(b, 9), a, tt1
+			h, g, f, e = g, bits.RotateLeft32(f, 19), e, sm3P0(tt2)
+		}
+		d.s[0] ^= a
+		d.s[1] ^= b
+		d.s[2] ^= c
+		d.s[3] ^= dd
+		d.s[4] ^= e
+		d.s[5] ^= f
+		d.s[6] ^= g
+		d.s[7] ^= h
+	}
+}
+
+const magicSM3 = "sm3\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (d *sm3) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magicSM3)+8*4+8+blockSize)
+	b = append(b, magicSM3...)
+	for _, v := range d.s {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return d.appendBuffer(b), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *sm3) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magicSM3)+8*4 || string(b[:len(magicSM3)]) != magicSM3 {
+		return errInvalidState
+	}
+	b = b[len(magicSM3):]
+	for i := range d.s {
+		d.s[i], b = binary.BigEndian.Uint32(b), b[4:]
+	}
+	return d.readBuffer(b)
+}
+
+// Clone implements hash.Cloner.
+func (d *sm3) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
@@ -0,0 +1,39 @@
+package hashio
+
+import (
+	"io"
+	"os"
+)
+
+// fileBufSize is the size of the buffer HashFile reads through. Files are
+// usually large and local, so it is bigger than defaultBufSize. It can be
+// overridden with SetBufferSize.
+const fileBufSize = 256 << 10
+
+// HashFile opens the file at path, hashes its contents with a fresh instance
+// of each algorithm in names and closes it. The digests are returned keyed by
+// name. If no names are given, the hashes of StdCryptoHashes are computed.
+//
+// An error is returned if any name is not a known algorithm or if the file
+// cannot be read.
+func HashFile(path string, names ...string) (map[string][]byte, error) {
+	hashers, err := hashersByName(names)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Hide the WriteTo and ReadFrom methods so the copy uses buf.
+	h := NewHasher(hashers)
+	buf := getBuffer(copyBufSize(fileBufSize))
+	defer putBuffer(buf)
+	if _, err := io.CopyBuffer(struct{ io.Writer }{h}, struct{ io.Reader }{f}, *buf); err != nil {
+		return nil, err
+	}
+	return h.Sums(), nil
+}
@@ -0,0 +1,41 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestHashFile(t *testing.T) {
+	for _, tc := range []struct {
+		names []string
+		want  map[string]string
+	}{
+		{nil, map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256}},
+		{[]string{SHA256}, map[string]string{SHA256: dataFileSHA256}},
+	} {
+		sums, err := HashFile(dataFile, tc.names...)
+		if err != nil {
+			t.Fatalf("HashFile(%q, %q): %v", dataFile, tc.names, err)
+		}
+		got := make(map[string]string, len(sums))
+		for name, sum := range sums {
+			got[name] = hex.EncodeToString(sum)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("HashFile(%q, %q) got: %v, wanted %v", dataFile, tc.names, got, tc.want)
+		}
+	}
+}
+
+func TestHashFileErrors(t *testing.T) {
+	var unknown *UnknownHashError
+	if _, err := HashFile(dataFile, "crc0"); !errors.As(err, &unknown) {
+		t.Errorf("HashFile(%q, \"crc0\") got error: %v, wanted *UnknownHashError", dataFile, err)
+	}
+	if _, err := HashFile("testdata/does_not_exist"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("HashFile(missing file) got error: %v, wanted os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,111 @@
+package hashio
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+	"os"
+)
+
+// Sampling selects the parts of an input hashed by Fingerprint.
+type Sampling struct {
+	// Edge is the number of bytes hashed at both the start and the end of the
+	// input.
+	Edge int64
+	// BlockSize is the size of the blocks sampled between the edges.
+	BlockSize int64
+	// Every selects which blocks between the edges are sampled: block i,
+	// counting BlockSize blocks from the start of the input, is hashed if i
+	// is a multiple of Every. If Every is zero, no blocks are sampled.
+	Every int64
+}
+
+// DefaultSampling hashes the first and last 64 KiB of the input and one 4 KiB
+// block out of every 256 (about 0.4%) in between.
+var DefaultSampling = Sampling{Edge: 64 << 10, BlockSize: 4 << 10, Every: 256}
+
+// Fingerprint returns a fast, partial digest of the size bytes of ra computed
+// with h: the total length, the sampling parameters and the bytes selected by
+// s are hashed, the rest is skipped. Inputs no longer than two edges are
+// hashed in full.
+//
+// Two inputs with different fingerprints (computed with the same Sampling and
+// hash) are certainly different, while equal fingerprints only suggest equal
+// inputs. This makes fingerprints useful to pre-filter duplicate candidates
+// before computing a full digest. A fingerprint never equals the plain digest
+// of the same input.
+func Fingerprint(ra io.ReaderAt, size int64, s Sampling, h hash.Hash) ([]byte, error) {
+	var header [32]byte
+	binary.BigEndian.PutUint64(header[0:], uint64(size))
+	binary.BigEndian.PutUint64(header[8:], uint64(s.Edge))
+	binary.BigEndian.PutUint64(header[16:], uint64(s.BlockSize))
+	binary.BigEndian.PutUint64(header[24:], uint64(s.Every))
+	h.Write(header[:])
+
+	if size <= 2*s.Edge {
+		return fingerprintSum(h, ra, 0, size)
+	}
+	if err := hashRange(h, ra, 0, s.Edge); err != nil {
+		return nil, err
+	}
+	end := size - s.Edge
+	if s.Every > 0 && s.BlockSize > 0 {
+		stride := s.Every * s.BlockSize
+		// The first sampled block that ends after the head.
+		off := (s.Edge / stride) * stride
+		for ; off < end; off += stride {
+			lo, hi := max(off, s.Edge), min(off+s.BlockSize, end)
+			if lo >= hi {
+				continue
+			}
+			if err := hashRange(h, ra, lo, hi-lo); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fingerprintSum(h, ra, end, s.Edge)
+}
+
+// fingerprintSum hashes the n bytes of ra at off with h and returns the sum.
+func fingerprintSum(h hash.Hash, ra io.ReaderAt, off, n int64) ([]byte, error) {
+	if err := hashRange(h, ra, off, n); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// hashRange writes the n bytes of ra at off to h, failing with
+// io.ErrUnexpectedEOF if ra is shorter.
+func hashRange(h hash.Hash, ra io.ReaderAt, off, n int64) error {
+	buf := getBuffer(defaultBufSize)
+	defer putBuffer(buf)
+	copied, err := io.CopyBuffer(h, io.NewSectionReader(ra, off, n), *buf)
+	if err != nil {
+		return err
+	}
+	if copied != n {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// FingerprintFile returns the Fingerprint of the file at path computed with a
+// fresh instance of the algorithm alg. An error is returned if alg is not a
+// known algorithm or the file cannot be read.
+func FingerprintFile(path string, alg Algorithm, s Sampling) ([]byte, error) {
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return Fingerprint(f, fi.Size(), s, h)
+}
@@ -0,0 +1,75 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// recordingHash records everything written to it.
+type recordingHash struct {
+	bytes.Buffer
+}
+
+func (h *recordingHash) Sum(b []byte) []byte { return append(b, h.Bytes()...) }
+func (h *recordingHash) Size() int           { return 0 }
+func (h *recordingHash) BlockSize() int      { return 1 }
+
+func TestFingerprintSampling(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	s := Sampling{Edge: 10, BlockSize: 5, Every: 4}
+
+	got, err := Fingerprint(bytes.NewReader(data), int64(len(data)), s, &recordingHash{})
+	if err != nil {
+		t.Fatalf("Fingerprint(): %v", err)
+	}
+	var want []byte
+	for _, v := range []int64{100, 10, 5, 4} {
+		want = binary.BigEndian.AppendUint64(want, uint64(v))
+	}
+	want = append(want, data[0:10]...)  // head
+	want = append(want, data[20:25]...) // blocks 4, 8, ..., 16
+	want = append(want, data[40:45]...)
+	want = append(want, data[60:65]...)
+	want = append(want, data[80:85]...)
+	want = append(want, data[90:100]...) // tail
+	if !bytes.Equal(got, want) {
+		t.Errorf("Fingerprint() hashed: %v, wanted %v", got, want)
+	}
+
+	// Short inputs are hashed in full.
+	got, err = Fingerprint(bytes.NewReader(data[:15]), 15, s, &recordingHash{})
+	if err != nil {
+		t.Fatalf("Fingerprint() of 15 bytes: %v", err)
+	}
+	if !bytes.Equal(got[32:], data[:15]) {
+		t.Errorf("Fingerprint() of 15 bytes hashed: %v, wanted all of them", got[32:])
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := bytes.Repeat([]byte("a"), 1<<20)
+
+	fa, err := FingerprintFile(dataFile, SHA256, DefaultSampling)
+	if err != nil {
+		t.Fatalf("FingerprintFile(%q): %v", dataFile, err)
+	}
+	if len(fa) != sha256.Size {
+		t.Errorf("FingerprintFile(%q) got %d bytes, wanted %d", dataFile, len(fa), sha256.Size)
+	}
+
+	f1, _ := Fingerprint(bytes.NewReader(a), int64(len(a)), DefaultSampling, sha256.New())
+	f2, _ := Fingerprint(bytes.NewReader(a[:len(a)-1]), int64(len(a)-1), DefaultSampling, sha256.New())
+	if bytes.Equal(f1, f2) {
+		t.Errorf("Fingerprint() of inputs with different lengths are equal")
+	}
+
+	if _, err := Fingerprint(bytes.NewReader(a[:100]), 1<<20, DefaultSampling, sha256.New()); err != io.ErrUnexpectedEOF {
+		t.Errorf("Fingerprint() of a short input got error: %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,140 @@
+package hashio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNotFIPSApproved is wrapped by the errors returned when an algorithm is
+// requested by name in FIPS-only mode but isn't FIPS approved. See
+// SetFIPSOnly.
+var ErrNotFIPSApproved = errors.New("hashio: hash algorithm not FIPS approved")
+
+// fipsApproved are the built in algorithms approved by FIPS 140-3 for
+// security functions: the SHA-2 (FIPS 180-4) and SHA-3 (FIPS 202) hashes.
+var fipsApproved = map[string]bool{
+	SHA256:     true,
+	SHA384:     true,
+	SHA512:     true,
+	SHA512_256: true,
+	SHA3_256:   true,
+	SHA3_384:   true,
+	SHA3_512:   true,
+	SHAKE128:   true,
+	SHAKE256:   true,
+}
+
+// nonSecurity are the built in checksums and non-cryptographic hashes. They
+// provide no security, so FIPS 140-3 doesn't restrict them and FIPS-only mode
+// allows them.
+var nonSecurity = map[string]bool{
+	CRC32:     true,
+	CRC32C:    true,
+	CRC64ISO:  true,
+	CRC64ECMA: true,
+	Adler32:   true,
+	XXH64:     true,
+	XXH3:      true,
+	FNV1a32:   true,
+	FNV1a64:   true,
+	FNV1a128:  true,
+}
+
+// fipsOnly is set by SetFIPSOnly.
+var fipsOnly atomic.Bool
+
+// SetFIPSOnly turns FIPS-only mode on or off for the whole program. In
+// FIPS-only mode:
+//
+//   - functions selecting algorithms by name, such as NewByNames, HashBytes,
+//     VerifyFile and NewHMACReader, return an error wrapping
+//     ErrNotFIPSApproved for an algorithm that isn't allowed;
+//   - NewReader and NewWriter panic if an option adds such an algorithm, as
+//     they do with WithFIPSOnly.
+//
+// The allowed algorithms are those for which FIPSApproved reports true, and
+// the checksums and non-cryptographic hashes such as CRC32C and XXH3, which
+// FIPS 140-3 doesn't restrict since they serve no security function. MD5 and
+// SHA-1 on its own are rejected, but HMAC-SHA1 is allowed. A name whose
+// factory was replaced with Register is rejected too, even that of an allowed
+// algorithm.
+//
+// Hashes passed in a map to NewHashReader or NewHashWriter, or with
+// WithHasher, aren't checked, since their algorithm isn't known; ensuring
+// they are approved is up to the caller. Neither are those used internally
+// for interoperability, such as the MD5 of GCS objects.
+//
+// It is safe to call concurrently with the rest of the package, and is
+// meant to be called once at startup.
+func SetFIPSOnly(on bool) {
+	fipsOnly.Store(on)
+}
+
+// FIPSOnly reports whether FIPS-only mode was turned on by SetFIPSOnly.
+func FIPSOnly() bool {
+	return fipsOnly.Load()
+}
+
+// WithFIPSOnly makes NewReader and NewWriter panic if another option adds a
+// hash whose algorithm isn't allowed in FIPS-only mode, whether or not it was
+// turned on with SetFIPSOnly. See SetFIPSOnly for the algorithms allowed and
+// the hashes that aren't checked.
+func WithFIPSOnly() Option {
+	return func(c *config) {
+		c.fips = true
+	}
+}
+
+// FIPSApproved reports whether alg is a FIPS 140-3 approved hash algorithm:
+// SHA256, SHA384, SHA512, SHA512_256, SHA3_256, SHA3_384, SHA3_512, SHAKE128
+// and SHAKE256, and the HMACs named by HMACName of those and of SHA1. Digests
+// can be annotated with it, see Digest.FIPSApproved.
+//
+// A name whose factory was replaced with Register isn't approved, since the
+// algorithm it now stands for isn't known.
+func FIPSApproved(alg Algorithm) bool {
+	if inner, ok := strings.CutPrefix(alg, "hmac-"); ok {
+		return (fipsApproved[inner] || inner == SHA1) && !isReplaced(inner)
+	}
+	return fipsApproved[alg] && !isReplaced(alg)
+}
+
+// FIPSApproved reports whether d.Algorithm is FIPS approved. See FIPSApproved.
+func (d Digest) FIPSApproved() bool {
+	return FIPSApproved(d.Algorithm)
+}
+
+// fipsAllowed reports whether alg may be used in FIPS-only mode.
+func fipsAllowed(alg Algorithm) bool {
+	return FIPSApproved(alg) || nonSecurity[alg] && !isReplaced(alg)
+}
+
+// checkFIPS returns an error wrapping ErrNotFIPSApproved if FIPS-only mode is
+// on and alg isn't allowed.
+func checkFIPS(alg Algorithm) error {
+	if FIPSOnly() && !fipsAllowed(alg) {
+		return fmt.Errorf("%w: %q", ErrNotFIPSApproved, alg)
+	}
+	return nil
+}
+
+// checkFIPS panics if FIPS-only mode is on, by WithFIPSOnly or SetFIPSOnly,
+// and one of the hashes added by algorithm isn't allowed.
+func (c *config) checkFIPS() {
+	if !c.fips && !FIPSOnly() {
+		return
+	}
+	var rejected []string
+	for name, alg := range c.algs {
+		if !fipsAllowed(alg) {
+			rejected = append(rejected, name)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		panic(fmt.Sprintf("hashio: FIPS-only mode rejects the hashes %s", strings.Join(rejected, ", ")))
+	}
+}
@@ -0,0 +1,125 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFIPSApproved(t *testing.T) {
+	for alg, want := range map[string]bool{
+		SHA256:           true,
+		SHA512_256:       true,
+		SHA3_384:         true,
+		SHAKE256:         true,
+		HMACName(SHA256): true,
+		HMACName(SHA1):   true,
+		SHA1:             false,
+		MD5:              false,
+		HMACName(MD5):    false,
+		BLAKE3:           false,
+		CRC32C:           false,
+		"tenantA":        false,
+	} {
+		if got := FIPSApproved(alg); got != want {
+			t.Errorf("FIPSApproved(%q) got: %v, wanted %v", alg, got, want)
+		}
+	}
+
+	w := NewWriter(nil, WithSHA256(), WithMD5())
+	for _, d := range w.Digests() {
+		if got, want := d.FIPSApproved(), d.Algorithm == SHA256; got != want {
+			t.Errorf("Digest{%s}.FIPSApproved() got: %v, wanted %v", d.Algorithm, got, want)
+		}
+	}
+}
+
+func TestWithFIPSOnly(t *testing.T) {
+	// Approved hashes, HMAC-SHA1, checksums and hashes of unknown algorithm
+	// are allowed.
+	NewWriter(nil, WithFIPSOnly(), WithSHA256(), WithNamedHash("crc", CRC32C),
+		WithHMAC("tenantA", SHA1, []byte("key")), WithHasher("custom", md5.New()))
+
+	for _, opt := range []Option{WithMD5(), WithSHA1(), WithNamedHash("fast", BLAKE3), WithHMAC("tenantA", MD5, []byte("key"))} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewWriter(WithFIPSOnly()) with a non-approved hash didn't panic")
+				}
+			}()
+			NewWriter(nil, opt, WithSHA256(), WithFIPSOnly())
+		}()
+	}
+	// A replaced hash is no longer rejected.
+	NewWriter(nil, WithFIPSOnly(), WithMD5(), WithHasher(MD5, md5.New()))
+}
+
+func TestSetFIPSOnly(t *testing.T) {
+	SetFIPSOnly(true)
+	t.Cleanup(func() { SetFIPSOnly(false) })
+	if !FIPSOnly() {
+		t.Fatalf("FIPSOnly() after SetFIPSOnly(true) got: false, wanted true")
+	}
+
+	if _, err := NewByNames(strings.NewReader(""), SHA256, CRC32C); err != nil {
+		t.Errorf("NewByNames(sha256, crc32c): %v", err)
+	}
+	if _, err := HMACs(SHA1, map[string][]byte{"tenantA": []byte("key")}); err != nil {
+		t.Errorf("HMACs(sha1): %v", err)
+	}
+	for desc, err := range map[string]error{
+		"NewByNames(md5)": func() error {
+			_, err := NewByNames(strings.NewReader(""), SHA256, MD5)
+			return err
+		}(),
+		"NewByNames()": func() error {
+			_, err := NewByNames(strings.NewReader(""))
+			return err
+		}(),
+		"HashString(sha1)": func() error {
+			_, err := HashString("", SHA1)
+			return err
+		}(),
+		"NewHMACReader(sha1)": func() error {
+			_, err := NewHMACReader(strings.NewReader(""), []byte("key"), SHA1)
+			return err
+		}(),
+		"HMACs(md5)": func() error {
+			_, err := HMACs(MD5, map[string][]byte{"tenantA": []byte("key")})
+			return err
+		}(),
+	} {
+		if !errors.Is(err, ErrNotFIPSApproved) {
+			t.Errorf("%s in FIPS-only mode got: %v, wanted %v", desc, err, ErrNotFIPSApproved)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewReader(WithMD5()) in FIPS-only mode didn't panic")
+		}
+	}()
+	NewReader(strings.NewReader(""), WithMD5())
+}
+
+func TestFIPSOnlyReplacedFactory(t *testing.T) {
+	replaceForTest(t, SHA256, md5.New)
+	replaceForTest(t, CRC32, md5.New)
+
+	for _, alg := range []string{SHA256, HMACName(SHA256)} {
+		if FIPSApproved(alg) {
+			t.Errorf("FIPSApproved(%q) after Register(sha256, md5.New) got: true, wanted false", alg)
+		}
+	}
+	SetFIPSOnly(true)
+	t.Cleanup(func() { SetFIPSOnly(false) })
+	for _, alg := range []string{SHA256, CRC32} {
+		if _, err := HashString("", alg); !errors.Is(err, ErrNotFIPSApproved) {
+			t.Errorf("HashString(%q) in FIPS-only mode after Register(%q, md5.New) got: %v, wanted %v", alg, alg, err, ErrNotFIPSApproved)
+		}
+	}
+	if _, err := HashString("", SHA384); err != nil {
+		t.Errorf("HashString(sha384) in FIPS-only mode: %v", err)
+	}
+}
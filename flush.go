@@ -0,0 +1,28 @@
+package hashio
+
+// Flush flushes the wrapped io.Writer if it has a Flush method, as
+// *bufio.Writer does, and is a no-op otherwise.
+//
+// A failed Flush means buffered data never reached its destination, so the
+// error is recorded as by Write and the digests are withheld.
+func (h *HashWriter) Flush() error {
+	f, ok := h.dst.(interface{ Flush() error })
+	if !ok {
+		return nil
+	}
+	err := f.Flush()
+	h.setErr(err)
+	return err
+}
+
+// Sync commits the wrapped io.Writer to stable storage if it has a Sync method,
+// as *os.File does, and is a no-op otherwise. Errors are recorded as by Flush.
+func (h *HashWriter) Sync() error {
+	s, ok := h.dst.(interface{ Sync() error })
+	if !ok {
+		return nil
+	}
+	err := s.Sync()
+	h.setErr(err)
+	return err
+}
@@ -0,0 +1,71 @@
+package hashio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashWriterFlush(t *testing.T) {
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	hw := NewWriter(bw, WithSHA256())
+	if _, err := hw.Write([]byte("hello")); err != nil {
+		t.Fatalf("HashWriter.Write(): %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("wrapped writer got %d bytes before Flush, wanted 0", buf.Len())
+	}
+	if err := hw.Flush(); err != nil {
+		t.Fatalf("HashWriter.Flush(): %v", err)
+	}
+	if got := buf.String(); got != "hello" {
+		t.Errorf("wrapped writer got: %q after Flush, wanted %q", got, "hello")
+	}
+
+	// Writers without Flush or Sync are left alone.
+	hw = NewWriter(&buf, WithSHA256())
+	if err := hw.Flush(); err != nil {
+		t.Errorf("HashWriter.Flush() on *bytes.Buffer: %v", err)
+	}
+	if err := hw.Sync(); err != nil {
+		t.Errorf("HashWriter.Sync() on *bytes.Buffer: %v", err)
+	}
+}
+
+func TestHashWriterFlushError(t *testing.T) {
+	boom := errors.New("boom")
+	bw := bufio.NewWriter(&errWriter{limit: 2, err: boom})
+	hw := NewWriter(bw, WithSHA256())
+	if _, err := hw.Write([]byte("hello")); err != nil {
+		t.Fatalf("HashWriter.Write(): %v", err)
+	}
+	if err := hw.Flush(); err != boom {
+		t.Errorf("HashWriter.Flush() got error: %v, wanted %v", err, boom)
+	}
+	if hw.Err() != boom {
+		t.Errorf("HashWriter.Err() got: %v, wanted %v", hw.Err(), boom)
+	}
+	if got := hw.Hash(SHA256, nil); got != nil {
+		t.Errorf("HashWriter.Hash(sha256) after failed Flush got: %x, wanted nil", got)
+	}
+}
+
+func TestHashWriterSync(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("os.Create(): %v", err)
+	}
+	defer f.Close()
+
+	hw := NewWriter(f, WithSHA256())
+	if _, err := hw.Write([]byte("hello")); err != nil {
+		t.Fatalf("HashWriter.Write(): %v", err)
+	}
+	if err := hw.Sync(); err != nil {
+		t.Errorf("HashWriter.Sync(): %v", err)
+	}
+}
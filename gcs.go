@@ -0,0 +1,173 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strings"
+)
+
+// GCSCRC32C is the name NewGCSHashers uses for the CRC32C (Castagnoli)
+// checksum Google Cloud Storage keeps for every object.
+const GCSCRC32C = CRC32C
+
+// GCSHashes holds the checksums Google Cloud Storage reports for an object,
+// base64 encoded exactly as in the crc32c and md5Hash fields of the object
+// metadata and in the x-goog-hash header. The CRC32C is encoded big-endian.
+// MD5 is empty for composite objects, which GCS only checksums with CRC32C.
+type GCSHashes struct {
+	CRC32C string
+	MD5    string
+}
+
+// NewGCSHashers returns the hashers needed for GCSHashes, the CRC32C keyed by
+// GCSCRC32C and the MD5 keyed by MD5, to be passed to NewHashReader or
+// NewHashWriter.
+func NewGCSHashers() map[string]hash.Hash {
+	return map[string]hash.Hash{
+		GCSCRC32C: newCRC32C(),
+		MD5:       md5.New(),
+	}
+}
+
+// GCSHashes returns the GCS checksums of the data read so far. The HashReader
+// must hash GCSCRC32C and MD5 as set up by NewGCSHashers; either value is
+// empty if its hash isn't present or a Read returned an error.
+func (h *HashReader) GCSHashes() GCSHashes {
+	return gcsHashes(h.LookupHash)
+}
+
+// GCSHashes returns the GCS checksums of the data written so far. The
+// HashWriter must hash GCSCRC32C and MD5 as set up by NewGCSHashers; either
+// value is empty if its hash isn't present or a Write returned an error.
+func (h *HashWriter) GCSHashes() GCSHashes {
+	return gcsHashes(h.LookupHash)
+}
+
+func gcsHashes(lookup func(name string) ([]byte, error)) GCSHashes {
+	var g GCSHashes
+	if sum, err := lookup(GCSCRC32C); err == nil {
+		g.CRC32C = base64.StdEncoding.EncodeToString(sum)
+	}
+	if sum, err := lookup(MD5); err == nil {
+		g.MD5 = base64.StdEncoding.EncodeToString(sum)
+	}
+	return g
+}
+
+// ComputeGCSHashes reads r until io.EOF and returns the checksums GCS would
+// report for its content.
+func ComputeGCSHashes(r io.Reader) (GCSHashes, error) {
+	h := NewHashReader(r, NewGCSHashers())
+	if _, err := io.Copy(io.Discard, h); err != nil {
+		return GCSHashes{}, err
+	}
+	return h.GCSHashes(), nil
+}
+
+// Header returns the checksums in the form of the x-goog-hash header, e.g.
+// "crc32c=n03x6A==,md5=Ojk9c3dhfxgoKVVHYwFbHQ==". Empty values are left out.
+func (g GCSHashes) Header() string {
+	var parts []string
+	if g.CRC32C != "" {
+		parts = append(parts, "crc32c="+g.CRC32C)
+	}
+	if g.MD5 != "" {
+		parts = append(parts, "md5="+g.MD5)
+	}
+	return strings.Join(parts, ",")
+}
+
+// GsutilFormat returns the checksums as printed by gsutil hash for a file
+// named filename, so they can be compared with its output:
+//
+//	Hashes [base64] for hello.txt:
+//		Hash (crc32c):		aZmkHw==
+//		Hash (md5):		5NfxtO0uQtFYmPSyewGdpA==
+//
+// Empty values are left out. The result ends with a newline.
+func (g GCSHashes) GsutilFormat(filename string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hashes [base64] for %s:\n", filename)
+	if g.CRC32C != "" {
+		fmt.Fprintf(&b, "\tHash (crc32c):\t\t%s\n", g.CRC32C)
+	}
+	if g.MD5 != "" {
+		fmt.Fprintf(&b, "\tHash (md5):\t\t%s\n", g.MD5)
+	}
+	return b.String()
+}
+
+// WriteGsutilHash reads r until io.EOF, computing its GCS checksums in a single
+// pass, and writes them to w in the format of gsutil hash for a file named
+// filename. See GCSHashes.GsutilFormat.
+func WriteGsutilHash(w io.Writer, filename string, r io.Reader) (GCSHashes, error) {
+	g, err := ComputeGCSHashes(r)
+	if err != nil {
+		return GCSHashes{}, err
+	}
+	if _, err := io.WriteString(w, g.GsutilFormat(filename)); err != nil {
+		return GCSHashes{}, err
+	}
+	return g, nil
+}
+
+// Matches reports whether g and other agree on every checksum both of them
+// have, and have at least one checksum in common.
+func (g GCSHashes) Matches(other GCSHashes) bool {
+	common := false
+	if g.CRC32C != "" && other.CRC32C != "" {
+		if g.CRC32C != other.CRC32C {
+			return false
+		}
+		common = true
+	}
+	if g.MD5 != "" && other.MD5 != "" {
+		if g.MD5 != other.MD5 {
+			return false
+		}
+		common = true
+	}
+	return common
+}
+
+// ParseGCSHashHeader parses the values of one or more x-goog-hash headers,
+// each holding comma separated "<type>=<base64>" pairs. Checksum types other
+// than crc32c and md5 are ignored.
+func ParseGCSHashHeader(values ...string) (GCSHashes, error) {
+	var g GCSHashes
+	for _, v := range values {
+		for _, pair := range strings.Split(v, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			typ, b64, ok := strings.Cut(pair, "=")
+			if !ok {
+				return GCSHashes{}, fmt.Errorf("hashio: invalid x-goog-hash value %q", pair)
+			}
+			var size int
+			var dst *string
+			switch typ {
+			case "crc32c":
+				size, dst = crc32.Size, &g.CRC32C
+			case "md5":
+				size, dst = md5.Size, &g.MD5
+			default:
+				continue
+			}
+			sum, err := base64.StdEncoding.DecodeString(b64)
+			if err != nil {
+				return GCSHashes{}, fmt.Errorf("hashio: invalid x-goog-hash value %q: %w", pair, err)
+			}
+			if len(sum) != size {
+				return GCSHashes{}, fmt.Errorf("hashio: invalid x-goog-hash value %q, %s checksums are %d bytes, not %d", pair, typ, size, len(sum))
+			}
+			*dst = b64
+		}
+	}
+	return g, nil
+}
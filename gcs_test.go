@@ -0,0 +1,89 @@
+package hashio
+
+import (
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+)
+
+func TestGCSHashes(t *testing.T) {
+	want := GCSHashes{CRC32C: "aZmkHw==", MD5: "5NfxtO0uQtFYmPSyewGdpA=="}
+
+	got, err := ComputeGCSHashes(strings.NewReader("hello, world"))
+	if err != nil || got != want {
+		t.Errorf("ComputeGCSHashes() got: (%+v, %v), wanted (%+v, nil)", got, err, want)
+	}
+
+	w := NewHashWriter(nil, NewGCSHashers())
+	w.WriteString("hello, world")
+	if got := w.GCSHashes(); got != want {
+		t.Errorf("HashWriter.GCSHashes() got: %+v, wanted %+v", got, want)
+	}
+	if got, wantHeader := want.Header(), "crc32c=aZmkHw==,md5=5NfxtO0uQtFYmPSyewGdpA=="; got != wantHeader {
+		t.Errorf("GCSHashes.Header() got: %q, wanted %q", got, wantHeader)
+	}
+
+	// A reader without MD5, as for composite objects.
+	r := NewHashReader(strings.NewReader("hello, world"), map[string]hash.Hash{GCSCRC32C: NewGCSHashers()[GCSCRC32C]})
+	r.Read(make([]byte, 64))
+	if got := r.GCSHashes(); got != (GCSHashes{CRC32C: want.CRC32C}) {
+		t.Errorf("HashReader.GCSHashes() without md5 got: %+v, wanted only the CRC32C", got)
+	}
+
+	boom := errors.New("boom")
+	if _, err := ComputeGCSHashes(&errReader{[]byte("partial"), boom}); !errors.Is(err, boom) {
+		t.Errorf("ComputeGCSHashes() with failing reader got error: %v, wanted %v", err, boom)
+	}
+}
+
+func TestParseGCSHashHeader(t *testing.T) {
+	got, err := ParseGCSHashHeader("crc32c=n03x6A==", " md5=Ojk9c3dhfxgoKVVHYwFbHQ==, other=abc")
+	if want := (GCSHashes{CRC32C: "n03x6A==", MD5: "Ojk9c3dhfxgoKVVHYwFbHQ=="}); err != nil || got != want {
+		t.Errorf("ParseGCSHashHeader() got: (%+v, %v), wanted (%+v, nil)", got, err, want)
+	}
+
+	for _, bad := range []string{"crc32c", "crc32c=!!!", "md5=n03x6A=="} {
+		if _, err := ParseGCSHashHeader(bad); err == nil {
+			t.Errorf("ParseGCSHashHeader(%q) got no error", bad)
+		}
+	}
+}
+
+func TestGCSHashesMatches(t *testing.T) {
+	full := GCSHashes{CRC32C: "aZmkHw==", MD5: "5NfxtO0uQtFYmPSyewGdpA=="}
+	for _, tc := range []struct {
+		other GCSHashes
+		want  bool
+	}{
+		{full, true},
+		{GCSHashes{CRC32C: full.CRC32C}, true},
+		{GCSHashes{CRC32C: "n03x6A=="}, false},
+		{GCSHashes{CRC32C: full.CRC32C, MD5: "Ojk9c3dhfxgoKVVHYwFbHQ=="}, false},
+		{GCSHashes{}, false},
+	} {
+		if got := full.Matches(tc.other); got != tc.want {
+			t.Errorf("GCSHashes.Matches(%+v) got: %t, wanted %t", tc.other, got, tc.want)
+		}
+	}
+}
+
+func TestWriteGsutilHash(t *testing.T) {
+	var b strings.Builder
+	g, err := WriteGsutilHash(&b, "hello.txt", strings.NewReader("hello, world"))
+	if err != nil {
+		t.Fatalf("WriteGsutilHash(): %v", err)
+	}
+	want := "Hashes [base64] for hello.txt:\n\tHash (crc32c):\t\taZmkHw==\n\tHash (md5):\t\t5NfxtO0uQtFYmPSyewGdpA==\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteGsutilHash() wrote %q, wanted %q", got, want)
+	}
+	if got := (GCSHashes{CRC32C: g.CRC32C}).GsutilFormat("composite"); got != "Hashes [base64] for composite:\n\tHash (crc32c):\t\taZmkHw==\n" {
+		t.Errorf("GCSHashes.GsutilFormat() without md5 got: %q", got)
+	}
+
+	boom := errors.New("boom")
+	if _, err := WriteGsutilHash(&errWriter{0, boom}, "hello.txt", strings.NewReader("hello, world")); err != boom {
+		t.Errorf("WriteGsutilHash() with failing writer got error: %v, wanted %v", err, boom)
+	}
+}
@@ -0,0 +1,52 @@
+package hashio
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+)
+
+// GitBlobHasher returns a SHA-1 hash.Hash computing the Git object ID of a
+// blob of size bytes: it hashes the "blob <size>\x00" header Git prepends to
+// the content, so that hashing the content yields the ID git hash-object
+// prints. Reset hashes the header again. The ID is only correct if exactly
+// size bytes are hashed.
+//
+// It can be passed to NewHashReader or WithHasher alongside other hashes:
+//
+//	r := hashio.NewReader(f, hashio.WithSHA256(), hashio.WithHasher("git", hashio.GitBlobHasher(size)))
+func GitBlobHasher(size int64) hash.Hash {
+	return gitBlobHasher(sha1.New(), size)
+}
+
+// GitBlobHasherSHA256 is like GitBlobHasher but for repositories using the
+// SHA-256 object format.
+func GitBlobHasherSHA256(size int64) hash.Hash {
+	return gitBlobHasher(sha256.New(), size)
+}
+
+func gitBlobHasher(h hash.Hash, size int64) hash.Hash {
+	header := strconv.AppendInt([]byte("blob "), size, 10)
+	header = append(header, 0)
+	h.Write(header)
+	return &saltedHash{Hash: h, prefix: header}
+}
+
+// ComputeGitBlobID reads r until io.EOF and returns the hex encoded SHA-1 Git
+// object ID of its content as a blob of size bytes. An error is returned if r
+// doesn't hold exactly size bytes.
+func ComputeGitBlobID(r io.Reader, size int64) (string, error) {
+	h := GitBlobHasher(size)
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+	if n != size {
+		return "", fmt.Errorf("hashio: git blob of %d bytes has %d bytes of content", size, n)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,54 @@
+package hashio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGitBlobHasher(t *testing.T) {
+	// As printed by git hash-object for a file holding "hello\n".
+	const (
+		id       = "ce013625030ba8dba906f756967f9e9ca394464a"
+		idSHA256 = "2cf8d83d9ee29543b34a87727421fdecb7e3f3a183d337639025de576db9ebb4"
+	)
+	r := NewReader(strings.NewReader("hello\n"), WithSHA256(),
+		WithHasher("git", GitBlobHasher(6)), WithHasher("git-sha256", GitBlobHasherSHA256(6)))
+	io.ReadAll(r)
+	if got := r.HexHash("git"); got != id {
+		t.Errorf("HashReader.HexHash(git) got: %q, wanted %q", got, id)
+	}
+	if got := r.HexHash("git-sha256"); got != idSHA256 {
+		t.Errorf("HashReader.HexHash(git-sha256) got: %q, wanted %q", got, idSHA256)
+	}
+
+	r.Reset(strings.NewReader("hello\n"))
+	io.ReadAll(r)
+	if got := r.HexHash("git"); got != id {
+		t.Errorf("HashReader.HexHash(git) after Reset got: %q, wanted %q", got, id)
+	}
+}
+
+func TestComputeGitBlobID(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want string
+	}{
+		{"", "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		{"hello\n", "ce013625030ba8dba906f756967f9e9ca394464a"},
+	} {
+		got, err := ComputeGitBlobID(strings.NewReader(tc.data), int64(len(tc.data)))
+		if err != nil || got != tc.want {
+			t.Errorf("ComputeGitBlobID(%q) got: (%q, %v), wanted (%q, nil)", tc.data, got, err, tc.want)
+		}
+	}
+
+	if _, err := ComputeGitBlobID(strings.NewReader("hello\n"), 5); err == nil {
+		t.Errorf("ComputeGitBlobID() with the wrong size got no error")
+	}
+	boom := errors.New("boom")
+	if _, err := ComputeGitBlobID(&errReader{[]byte("hel"), boom}, 6); err != boom {
+		t.Errorf("ComputeGitBlobID() with failing reader got: %v, wanted %v", err, boom)
+	}
+}
@@ -3,50 +3,378 @@
 package hashio
 
 import (
+	"bufio"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
+	"sort"
+	"strings"
+
+	"github.com/mikewiacek/hashio/blake2"
+	"github.com/mikewiacek/hashio/blake3"
 )
 
 // StdCryptoHashes returns a map intended to be passed to NewHashReader or
 // NewHashWriter. It contains the following hashes, "sha256", "sha1", and
-// "md5", with those literal names as keys (without the quotes). It's a
-// function of pure convenience.
+// "md5", with those literal names as keys (without the quotes), which are also
+// available as the constants SHA256, SHA1 and MD5. It's a function of pure
+// convenience.
 func StdCryptoHashes() map[string]hash.Hash {
 	return map[string]hash.Hash{
-		"sha256": sha256.New(),
-		"sha1":   sha1.New(),
-		"md5":    md5.New(),
+		SHA256: sha256.New(),
+		SHA1:   sha1.New(),
+		MD5:    md5.New(),
+	}
+}
+
+// StdCryptoHashFactories is like StdCryptoHashes but returns constructors
+// instead of live hash.Hash objects. It's intended to be passed to
+// NewHashReaderFromFactories or NewHashWriterFromFactories.
+func StdCryptoHashFactories() map[string]func() hash.Hash {
+	return map[string]func() hash.Hash{
+		SHA256: sha256.New,
+		SHA1:   sha1.New,
+		MD5:    md5.New,
 	}
 }
 
+// SecureHashes is like StdCryptoHashes but only contains hashes that are not
+// deprecated for security use: "sha256", "sha384", "sha512", and "sha3-256"
+// (SHA256, SHA384, SHA512 and SHA3_256).
+func SecureHashes() map[string]hash.Hash {
+	return newHashers(SecureHashFactories())
+}
+
+// SecureHashFactories is like SecureHashes but returns constructors instead of
+// live hash.Hash objects.
+func SecureHashFactories() map[string]func() hash.Hash {
+	return map[string]func() hash.Hash{
+		SHA256:   sha256.New,
+		SHA384:   sha512.New384,
+		SHA512:   sha512.New,
+		SHA3_256: newSHA3_256,
+	}
+}
+
+func newSHA3_256() hash.Hash { return sha3.New256() }
+
+func newSHA3_384() hash.Hash { return sha3.New384() }
+
+func newSHA3_512() hash.Hash { return sha3.New512() }
+
+func newBLAKE3() hash.Hash { return blake3.New() }
+
+func newBLAKE2b_384() hash.Hash {
+	h, _ := blake2.NewB(48, nil)
+	return h
+}
+
+// factories maps algorithm names to constructors for fresh hash.Hash objects. It is
+// used by helpers that must pick a hash.Hash from a name alone. Register adds to
+// it, so it must only be accessed with registryMu held.
+var factories = map[string]func() hash.Hash{
+	MD5:         md5.New,
+	SHA1:        sha1.New,
+	SHA256:      sha256.New,
+	SHA384:      sha512.New384,
+	SHA512:      sha512.New,
+	SHA512_256:  sha512.New512_256,
+	SHA3_256:    newSHA3_256,
+	SHA3_384:    newSHA3_384,
+	SHA3_512:    newSHA3_512,
+	SHAKE128:    newSHAKE128,
+	SHAKE256:    newSHAKE256,
+	BLAKE3:      newBLAKE3,
+	BLAKE2b_256: blake2.NewB256,
+	BLAKE2b_384: newBLAKE2b_384,
+	BLAKE2b_512: blake2.NewB512,
+	BLAKE2s_256: blake2.NewS256,
+	CRC32:       newCRC32,
+	CRC32C:      newCRC32C,
+	CRC64ISO:    newCRC64ISO,
+	CRC64ECMA:   newCRC64ECMA,
+	Adler32:     newAdler32,
+	XXH64:       newXXH64,
+	XXH3:        newXXH3,
+	FNV1a32:     newFNV1a32,
+	FNV1a64:     newFNV1a64,
+	FNV1a128:    newFNV1a128,
+	SHA256Tree:  newTreeHash,
+	SHA256d:     NewSHA256d,
+}
+
+// newHashers calls every constructor in fs and returns the resulting hash.Hash
+// objects under the same names.
+func newHashers(fs map[string]func() hash.Hash) map[string]hash.Hash {
+	hashers := make(map[string]hash.Hash, len(fs))
+	for name, f := range fs {
+		hashers[name] = f()
+	}
+	return hashers
+}
+
+// newHash returns a fresh hash.Hash for the algorithm alg. In FIPS-only mode,
+// an error is returned if alg isn't allowed.
+func newHash(alg Algorithm) (hash.Hash, error) {
+	f, ok := lookupFactory(alg)
+	if !ok {
+		return nil, &UnknownHashError{Name: alg, Available: registered()}
+	}
+	if err := checkFIPS(alg); err != nil {
+		return nil, err
+	}
+	return f(), nil
+}
+
+// hashersByName returns a fresh hash.Hash for each algorithm in names, keyed by
+// name. If names is empty, the hashes of StdCryptoHashes are returned.
+func hashersByName(names []string) (map[string]hash.Hash, error) {
+	if len(names) == 0 {
+		// Selected by name all the same, so FIPS-only mode rejects MD5.
+		names = []string{SHA256, SHA1, MD5}
+	}
+	hashers := make(map[string]hash.Hash, len(names))
+	for _, name := range names {
+		h, err := newHash(name)
+		if err != nil {
+			return nil, err
+		}
+		hashers[name] = h
+	}
+	return hashers, nil
+}
+
+// ErrUnknownHash is matched by errors.Is for every *UnknownHashError, so that
+// callers can tell a misspelled hash name from an I/O failure without a type
+// assertion.
+var ErrUnknownHash = errors.New("hashio: unknown hash")
+
+// UnknownHashError is returned when a hash is requested by a name that is not
+// known, either to the wrapper being queried or to the package. It wraps
+// ErrUnknownHash.
+type UnknownHashError struct {
+	Name string
+	// Available holds the names that were known, in sorted order: those of
+	// the hashes of the wrapper, or of the registered algorithms.
+	Available []string
+}
+
+func (e *UnknownHashError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("hashio: unknown hash %q", e.Name)
+	}
+	return fmt.Sprintf("hashio: unknown hash %q, available: %s", e.Name, strings.Join(e.Available, ", "))
+}
+
+// Unwrap returns ErrUnknownHash.
+func (e *UnknownHashError) Unwrap() error {
+	return ErrUnknownHash
+}
+
+// hashWriter returns an io.Writer that writes to every hash.Hash in hashers.
+func hashWriter(hashers map[string]hash.Hash) io.Writer {
+	writers := make([]io.Writer, 0, len(hashers))
+	for _, v := range hashers {
+		writers = append(writers, v)
+	}
+	return io.MultiWriter(writers...)
+}
+
+// resetAll resets every hash.Hash in hashers.
+func resetAll(hashers map[string]hash.Hash) {
+	for _, h := range hashers {
+		h.Reset()
+	}
+}
+
+// sortedNames returns the keys of hashers in sorted order.
+func sortedNames(hashers map[string]hash.Hash) []string {
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sums returns the current digest of every hash.Hash in hashers keyed by name.
+func sums(hashers map[string]hash.Hash) map[string][]byte {
+	m := make(map[string][]byte, len(hashers))
+	for name, h := range hashers {
+		m[name] = h.Sum(nil)
+	}
+	return m
+}
+
+// hexSums is like sums but hex encodes each digest.
+func hexSums(hashers map[string]hash.Hash) map[string]string {
+	m := make(map[string]string, len(hashers))
+	for name, h := range hashers {
+		m[name] = hexSum(h)
+	}
+	return m
+}
+
+// ErrStreamFailed is wrapped by the errors returned from digest accessors after
+// the wrapped stream returned an error, since the digests no longer describe it.
+var ErrStreamFailed = errors.New("hashio: digests are unavailable after a stream error")
+
+// ErrFinalized is returned by Read and Write on a wrapper created with
+// WithStrictFinalize once any of its digests has been requested.
+var ErrFinalized = errors.New("hashio: read or write after digests were finalized")
+
+// errInvalidWrite is recorded when the wrapped io.Writer reports accepting a
+// negative number of bytes or more bytes than it was given.
+var errInvalidWrite = errors.New("hashio: invalid write result")
+
+// streamFailed returns an error wrapping ErrStreamFailed and err.
+func streamFailed(err error) error {
+	return fmt.Errorf("%w: %w", ErrStreamFailed, err)
+}
+
+// lookup returns the hash.Hash identified by name in hashers.
+func lookup(hashers map[string]hash.Hash, name string) (hash.Hash, error) {
+	h, ok := hashers[name]
+	if !ok {
+		return nil, &UnknownHashError{Name: name, Available: sortedNames(hashers)}
+	}
+	return h, nil
+}
+
 // HashReader implements io.Reader by wrapping a provided io.Reader. It keeps
 // running cryptographic hashes of data written to that provided reader.
 type HashReader struct {
-	io.Reader
+	r       io.Reader // the wrapped reader, or br if it is set
 	hashers map[string]hash.Hash
+	hw      io.Writer      // writes to every hash.Hash in hashers
+	br      *bufio.Reader  // buffers the wrapped reader, set by WithBufferSize
+	n       int64          // bytes read
+	err     error          // first error other than io.EOF returned by r
+	chunk   int            // size of the WriteTo buffer, set by WithChunkSize
+	stops   []stop         // set by WithCheckpoint and RecordEvery
+	offsets []OffsetDigest // recorded by RecordEvery
+
+	strict    bool // set by WithStrictFinalize
+	finalized bool // a digest was requested while strict
+	paused    bool // set by PauseHashing
+
+	pending []byte // read from r by ReadRune but not yet returned or hashed
+
+	enc Encoder // set by WithEncoding
 }
 
 // NewHashReader takes an io.Reader and returns a HashReader (which implements
 // io.Reader). It also takes a map of strings (used for identification purposes only)
 // to hash.Hash objects. All data read from r is provided to each of these hash.Hash
 // objects. If there are any errors reading from r, apart from io.EOF, the hash values
-// are going to be undefined and probably incorrect.
+// would be incorrect, so the first such error is recorded (see Err) and the digest
+// accessors stop returning digests.
+//
+// The keys of hashers are identifiers, not algorithms: the constants such as
+// SHA256 are conventional names, but the same algorithm may appear under
+// several keys, for example one HMAC per tenant (see HMACs).
 //
 // The caller should not modify the hashers map nor any of the hash.Hash objects it contains.
 func NewHashReader(r io.Reader, hashers map[string]hash.Hash) *HashReader {
-	writers := make([]io.Writer, 0, len(hashers))
-	for _, v := range hashers {
-		writers = append(writers, v)
+	return &HashReader{
+		r:       r,
+		hashers: hashers,
+		hw:      hashWriter(hashers),
+	}
+}
+
+// Read reads from the wrapped io.Reader and passes the data read to every hash.
+func (h *HashReader) Read(p []byte) (int, error) {
+	if h.finalized {
+		return 0, ErrFinalized
+	}
+	if len(h.pending) > 0 {
+		n := copy(p, h.pending)
+		h.pending = h.pending[n:]
+		h.hash(p[:n])
+		return n, nil
 	}
 
-	return &HashReader{
-		io.TeeReader(r, io.MultiWriter(writers...)),
-		hashers,
+	n, err := h.r.Read(p)
+	h.hash(p[:n])
+	h.setErr(err)
+	return n, err
+}
+
+// hash passes p to every hash, unless hashing is paused, and counts it as read.
+// With WithCheckpoint or RecordEvery, it stops at every boundary in p.
+func (h *HashReader) hash(p []byte) {
+	if h.stops != nil {
+		split(h.stops, h.hashers, h.n, p, h.hashSome)
+		return
+	}
+	h.hashSome(p)
+}
+
+// hashSome hashes p unless hashing is paused, and counts it.
+func (h *HashReader) hashSome(p []byte) {
+	if len(p) > 0 {
+		if !h.paused {
+			h.hw.Write(p)
+		}
+		h.n += int64(len(p))
+	}
+}
+
+// setErr records err if it is the first error other than io.EOF.
+func (h *HashReader) setErr(err error) {
+	if err != nil && err != io.EOF && h.err == nil {
+		h.err = err
+	}
+}
+
+// Err returns the first error other than io.EOF returned by the wrapped
+// io.Reader since h was created or last Reset. Once it is not nil the digests
+// no longer describe the stream and are withheld by the digest accessors.
+func (h *HashReader) Err() error {
+	return h.err
+}
+
+// finalize marks h as finalized if it was created with WithStrictFinalize.
+func (h *HashReader) finalize() {
+	h.finalized = h.finalized || h.strict
+}
+
+// BytesRead returns the number of bytes read through h since it was created or
+// last Reset.
+func (h *HashReader) BytesRead() int64 {
+	return h.n
+}
+
+// Reset resets every hash.Hash passed to NewHashReader and makes h read from r,
+// allowing h to be reused without allocating new hashers.
+func (h *HashReader) Reset(r io.Reader) {
+	resetAll(h.hashers)
+	if h.br != nil {
+		h.br.Reset(r)
+		r = h.br
 	}
+	h.r = r
+	h.n = 0
+	h.err = nil
+	h.finalized = false
+	h.paused = false
+	h.pending = nil
+	h.offsets = nil
+}
+
+// NewHashReaderFromFactories is like NewHashReader but takes a map of names to
+// hash.Hash constructors. Each constructor is called once, so the returned
+// HashReader never shares hash.Hash objects with any other wrapper.
+func NewHashReaderFromFactories(r io.Reader, factories map[string]func() hash.Hash) *HashReader {
+	return NewHashReader(r, newHashers(factories))
 }
 
 // Hash appends the requested hash identified by name to buf and returns the slice.
@@ -55,9 +383,13 @@ func NewHashReader(r io.Reader, hashers map[string]hash.Hash) *HashReader {
 //
 // buf can be nil.
 //
-// The hash value is undefined if any call to Read returned an error
-// (not including io.EOF).
+// If any call to Read returned an error (not including io.EOF), buf is returned
+// unchanged since the hash would be incorrect. See Err.
 func (h *HashReader) Hash(name string, buf []byte) []byte {
+	if h.err != nil {
+		return buf
+	}
+	h.finalize()
 	return h.hashers[name].Sum(buf)
 }
 
@@ -65,10 +397,66 @@ func (h *HashReader) Hash(name string, buf []byte) []byte {
 // If name does not exist in the provided hashers map passed to NewHashReader, the
 // program will panic.
 //
-// The returned hash is undefined if any call to Read returned an error
-// (not including io.EOF).
+// If any call to Read returned an error (not including io.EOF), the empty
+// string is returned. See Err.
 func (h *HashReader) HexHash(name string) string {
-	return fmt.Sprintf("%x", h.Hash(name, nil))
+	if h.err != nil {
+		return ""
+	}
+	h.finalize()
+	return hexSum(h.hashers[name])
+}
+
+// LookupHash is like Hash but returns an *UnknownHashError instead of panicking
+// if name does not exist in the hashers map passed to NewHashReader. If any call
+// to Read returned an error (not including io.EOF) the returned error wraps both
+// ErrStreamFailed and that error.
+func (h *HashReader) LookupHash(name string) ([]byte, error) {
+	hh, err := lookup(h.hashers, name)
+	if err != nil {
+		return nil, err
+	}
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	h.finalize()
+	return hh.Sum(nil), nil
+}
+
+// LookupHexHash is like HexHash but returns an *UnknownHashError instead of
+// panicking if name does not exist in the hashers map passed to NewHashReader.
+func (h *HashReader) LookupHexHash(name string) (string, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// Names returns the names of the hashes passed to NewHashReader in sorted order.
+func (h *HashReader) Names() []string {
+	return sortedNames(h.hashers)
+}
+
+// Sums returns the digest of every hash passed to NewHashReader keyed by name.
+//
+// If any call to Read returned an error (not including io.EOF), nil is returned.
+// See Err.
+func (h *HashReader) Sums() map[string][]byte {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return sums(h.hashers)
+}
+
+// HexSums is like Sums but each digest is a hex encoded ASCII string.
+func (h *HashReader) HexSums() map[string]string {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return hexSums(h.hashers)
 }
 
 // HashWriter implements io.Writer by wrapping a provided io.Writer.
@@ -76,8 +464,21 @@ func (h *HashReader) HexHash(name string) string {
 // to a set of hash.Hash objects. The hashed values are made accessible
 // via methods on HashWriter.
 type HashWriter struct {
-	io.Writer
+	dst     io.Writer // the wrapped writer, nil to only hash
 	hashers map[string]hash.Hash
+	hw      io.Writer      // writes to every hash.Hash in hashers
+	n       int64          // bytes written
+	err     error          // first error returned by dst
+	bufSize int            // size of the ReadFrom buffer, set by WithBufferSize
+	chunk   int            // overrides bufSize, set by WithChunkSize
+	stops   []stop         // set by WithCheckpoint and RecordEvery
+	offsets []OffsetDigest // recorded by RecordEvery
+
+	strict    bool // set by WithStrictFinalize
+	finalized bool // a digest was requested while strict
+	paused    bool // set by PauseHashing
+
+	enc Encoder // set by WithEncoding
 }
 
 // NewHashWriter takes an io.Writer and returns a HashWriter (that also implements
@@ -85,35 +486,139 @@ type HashWriter struct {
 // objects in hashers.
 //
 // Data is passed to each hash.Hash as it's written to w, thus any data buffered by
-// w is considered for the hash function as soon as w.Write is called. If there is
-// an error writing to w, then no data will be written to the hash.Hash(ers) in hashers.
-// If that occurs, then no hash data is reliable and is thus undefined.
+// w is considered for the hash function as soon as w.Write is called. Only the
+// bytes w reports as accepted are hashed, so the hashes always describe exactly
+// what w received, even after a short write. If there is an error writing to w,
+// the hashes no longer describe the whole stream the caller meant to write, so
+// the first such error is recorded (see Err) and the digest accessors stop
+// returning digests.
+//
+// If w is nil, data is only hashed. See NewHasher.
+//
+// The keys of hashers are identifiers, not algorithms: the constants such as
+// SHA256 are conventional names, but the same algorithm may appear under
+// several keys, for example one HMAC per tenant (see HMACs).
 //
 // The caller should not modify the hashers map nor any of the hash.Hash objects it contains.
 func NewHashWriter(w io.Writer, hashers map[string]hash.Hash) *HashWriter {
-	writers := make([]io.Writer, 0, len(hashers)+1)
+	return &HashWriter{
+		dst:     w,
+		hashers: hashers,
+		hw:      hashWriter(hashers),
+	}
+}
 
-	// w must be the first writers in writers so that any errors block hash calculations.
-	writers = append(writers, w)
+// NewHasher returns a HashWriter with no destination: data written to it is
+// only hashed. It is cheaper than wrapping io.Discard, which costs an extra
+// Write call per chunk.
+func NewHasher(hashers map[string]hash.Hash) *HashWriter {
+	return NewHashWriter(nil, hashers)
+}
 
-	for _, v := range hashers {
-		writers = append(writers, v)
+// Write writes p to the wrapped io.Writer and then passes the bytes it
+// accepted to every hash.
+func (h *HashWriter) Write(p []byte) (int, error) {
+	if h.finalized {
+		return 0, ErrFinalized
 	}
+	if h.dst == nil {
+		return h.accept(p, len(p), nil)
+	}
+	n, err := h.dst.Write(p)
+	return h.accept(p, n, err)
+}
 
-	return &HashWriter{
-		io.MultiWriter(writers...),
-		hashers,
+// accept records the result of writing p to the wrapped io.Writer: the n
+// bytes it accepted are hashed and counted, and err is recorded. A short
+// write without an error is reported as io.ErrShortWrite.
+func (h *HashWriter) accept(p []byte, n int, err error) (int, error) {
+	n, err = clampWrite(n, len(p), err)
+	if err == nil && n != len(p) {
+		err = io.ErrShortWrite
+	}
+	h.hash(p[:n])
+	h.setErr(err)
+	return n, err
+}
+
+// hash passes p to the hashes, unless hashing is paused, and counts it as
+// written. With WithCheckpoint or RecordEvery, it stops at every boundary in p.
+func (h *HashWriter) hash(p []byte) {
+	if h.stops != nil {
+		split(h.stops, h.hashers, h.n, p, h.hashSome)
+		return
+	}
+	h.hashSome(p)
+}
+
+// hashSome hashes p unless hashing is paused, and counts it.
+func (h *HashWriter) hashSome(p []byte) {
+	if len(p) > 0 {
+		if !h.paused {
+			h.hw.Write(p)
+		}
+		h.n += int64(len(p))
+	}
+}
+
+// setErr records err if it is the first error.
+func (h *HashWriter) setErr(err error) {
+	if err != nil && h.err == nil {
+		h.err = err
 	}
 }
 
+// Err returns the first error returned by the wrapped io.Writer since h was
+// created or last Reset. Once it is not nil the digests no longer describe the
+// data written and are withheld by the digest accessors.
+func (h *HashWriter) Err() error {
+	return h.err
+}
+
+// finalize marks h as finalized if it was created with WithStrictFinalize.
+func (h *HashWriter) finalize() {
+	h.finalized = h.finalized || h.strict
+}
+
+// BytesWritten returns the number of bytes the wrapped io.Writer accepted
+// through h since it was created or last Reset.
+func (h *HashWriter) BytesWritten() int64 {
+	return h.n
+}
+
+// Reset resets every hash.Hash passed to NewHashWriter and makes h write to w,
+// allowing h to be reused without allocating new hashers. w may be nil, as for
+// NewHashWriter.
+func (h *HashWriter) Reset(w io.Writer) {
+	resetAll(h.hashers)
+	h.dst = w
+	h.n = 0
+	h.err = nil
+	h.finalized = false
+	h.paused = false
+	h.offsets = nil
+}
+
+// NewHashWriterFromFactories is like NewHashWriter but takes a map of names to
+// hash.Hash constructors. Each constructor is called once, so the returned
+// HashWriter never shares hash.Hash objects with any other wrapper.
+func NewHashWriterFromFactories(w io.Writer, factories map[string]func() hash.Hash) *HashWriter {
+	return NewHashWriter(w, newHashers(factories))
+}
+
 // Hash appends the requested hash identified by name to buf and returns the slice.
 // If name does not exist in the provided hashers map passed to NewHashWriter, the
 // program will panic.
 //
 // buf can be nil.
 //
-// The hash value is undefined if any call to Write returned an error.
+// If any call to Write returned an error, buf is returned unchanged since the
+// hash would be incorrect. See Err.
 func (h *HashWriter) Hash(name string, buf []byte) []byte {
+	if h.err != nil {
+		return buf
+	}
+	h.finalize()
 	return h.hashers[name].Sum(buf)
 }
 
@@ -121,7 +626,62 @@ func (h *HashWriter) Hash(name string, buf []byte) []byte {
 // If name does not exist in the provided hashers map passed to NewHashWriter, the
 // program will panic.
 //
-// The returned hash is undefined if any call to Write returned an error.
+// If any call to Write returned an error, the empty string is returned. See Err.
 func (h *HashWriter) HexHash(name string) string {
-	return fmt.Sprintf("%x", h.Hash(name, nil))
+	if h.err != nil {
+		return ""
+	}
+	h.finalize()
+	return hexSum(h.hashers[name])
+}
+
+// LookupHash is like Hash but returns an *UnknownHashError instead of panicking
+// if name does not exist in the hashers map passed to NewHashWriter. If any call
+// to Write returned an error the returned error wraps both ErrStreamFailed and
+// that error.
+func (h *HashWriter) LookupHash(name string) ([]byte, error) {
+	hh, err := lookup(h.hashers, name)
+	if err != nil {
+		return nil, err
+	}
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	h.finalize()
+	return hh.Sum(nil), nil
+}
+
+// LookupHexHash is like HexHash but returns an *UnknownHashError instead of
+// panicking if name does not exist in the hashers map passed to NewHashWriter.
+func (h *HashWriter) LookupHexHash(name string) (string, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// Names returns the names of the hashes passed to NewHashWriter in sorted order.
+func (h *HashWriter) Names() []string {
+	return sortedNames(h.hashers)
+}
+
+// Sums returns the digest of every hash passed to NewHashWriter keyed by name.
+//
+// If any call to Write returned an error, nil is returned. See Err.
+func (h *HashWriter) Sums() map[string][]byte {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return sums(h.hashers)
+}
+
+// HexSums is like Sums but each digest is a hex encoded ASCII string.
+func (h *HashWriter) HexSums() map[string]string {
+	if h.err != nil {
+		return nil
+	}
+	h.finalize()
+	return hexSums(h.hashers)
 }
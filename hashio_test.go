@@ -5,10 +5,13 @@ import (
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"hash"
+	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -154,3 +157,349 @@ func TestHashReaderAndWriter(t *testing.T) {
 	}
 
 }
+
+func TestLookupHash(t *testing.T) {
+	hr := NewHashReader(strings.NewReader("hello I am happy"), StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll: %v", err)
+	}
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	if _, err := hw.Write([]byte("hello I am happy")); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+
+	want := "1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a"
+	for _, h := range []interface {
+		LookupHexHash(string) (string, error)
+	}{hr, hw} {
+		got, err := h.LookupHexHash("sha256")
+		if err != nil {
+			t.Errorf("%T.LookupHexHash(sha256): %v", h, err)
+		}
+		if got != want {
+			t.Errorf("%T.LookupHexHash(sha256) got: %q, wanted %q", h, got, want)
+		}
+
+		_, err = h.LookupHexHash("sha-256")
+		var unknown *UnknownHashError
+		if !errors.As(err, &unknown) || unknown.Name != "sha-256" {
+			t.Errorf("%T.LookupHexHash(sha-256) got error: %v, wanted *UnknownHashError", h, err)
+		}
+		if !errors.Is(err, ErrUnknownHash) {
+			t.Errorf("%T.LookupHexHash(sha-256) got error: %v, wanted %v", h, err, ErrUnknownHash)
+		}
+		if want := []string{"md5", "sha1", "sha256"}; unknown == nil || !reflect.DeepEqual(unknown.Available, want) {
+			t.Errorf("%T.LookupHexHash(sha-256) got error: %#v, wanted Available %q", h, err, want)
+		}
+		if want := `hashio: unknown hash "sha-256", available: md5, sha1, sha256`; err == nil || err.Error() != want {
+			t.Errorf("%T.LookupHexHash(sha-256) got error: %v, wanted %q", h, err, want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	want := []string{"md5", "sha1", "sha256"}
+
+	hr := NewHashReader(strings.NewReader(""), StdCryptoHashes())
+	if got := hr.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HashReader.Names() got: %q, wanted %q", got, want)
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	if got := hw.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HashWriter.Names() got: %q, wanted %q", got, want)
+	}
+}
+
+func TestSums(t *testing.T) {
+	want := map[string]string{
+		"md5":    dataFileMD5,
+		"sha1":   dataFileSHA1,
+		"sha256": dataFileSHA256,
+	}
+
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr := NewHashReader(bytes.NewReader(contents), StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if got := hr.HexSums(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HashReader.HexSums() got: %v, wanted %v", got, want)
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	got := hw.Sums()
+	if len(got) != len(want) {
+		t.Errorf("HashWriter.Sums() returned %d digests, wanted %d", len(got), len(want))
+	}
+	for name, sum := range got {
+		if hex := fmt.Sprintf("%x", sum); hex != want[name] {
+			t.Errorf("HashWriter.Sums()[%q] got: %q, wanted %q", name, hex, want[name])
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr := NewHashReader(strings.NewReader("some other data"), StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll: %v", err)
+	}
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	if _, err := hw.Write([]byte("some other data")); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+
+	hr.Reset(bytes.NewReader(contents))
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if hash := hr.HexHash("sha256"); hash != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) after Reset got: %q, wanted %q", hash, dataFileSHA256)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	hw.Reset(buf)
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	if hash := hw.HexHash("sha256"); hash != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) after Reset got: %q, wanted %q", hash, dataFileSHA256)
+	}
+	if !bytes.Equal(buf.Bytes(), contents) {
+		t.Errorf("HashWriter.Reset did not redirect writes to the new writer")
+	}
+}
+
+func TestFromFactories(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	factories := StdCryptoHashFactories()
+	a := NewHashReaderFromFactories(bytes.NewReader(contents), factories)
+	b := NewHashReaderFromFactories(strings.NewReader("unrelated"), factories)
+	if _, err := ioutil.ReadAll(b); err != nil {
+		t.Fatalf("ioutil.ReadAll: %v", err)
+	}
+	if _, err := ioutil.ReadAll(a); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if hash := a.HexHash("sha256"); hash != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", hash, dataFileSHA256)
+	}
+
+	hw := NewHashWriterFromFactories(ioutil.Discard, factories)
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	if hash := hw.HexHash("md5"); hash != dataFileMD5 {
+		t.Errorf("HashWriter.HexHash(md5) got: %q, wanted %q", hash, dataFileMD5)
+	}
+}
+
+func TestSecureHashes(t *testing.T) {
+	want := map[string]string{
+		"sha256":   "1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a",
+		"sha384":   "cef71e8958c81b9115ea4b89b201c26807958f4652100373dab2bff5f5fd7ac928adb4160cdaf019f6218ca604fa064a",
+		"sha512":   "00c88ac7375d5335087a2ce4791777b03951ead4fd32fc0a1e5a280d86e54f183156c2623b84d4f53c75583dda0b755852e2dc5fa45d99d04b50e0cdb65afe86",
+		"sha3-256": "b741eddd970f416577c67ee1b964f65e3dfe94e866c5e871490b1dd071d54475",
+	}
+
+	hw := NewHashWriter(ioutil.Discard, SecureHashes())
+	if _, err := hw.Write([]byte("hello I am happy")); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	if got := hw.HexSums(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HashWriter.HexSums() got: %v, wanted %v", got, want)
+	}
+}
+
+func TestByteCounters(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr := NewHashReader(bytes.NewReader(contents), StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if n := hr.BytesRead(); n != int64(len(contents)) {
+		t.Errorf("HashReader.BytesRead() got: %d, wanted %d", n, len(contents))
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write(contents)
+	hw.Write(contents[:10])
+	if n := hw.BytesWritten(); n != int64(len(contents)+10) {
+		t.Errorf("HashWriter.BytesWritten() got: %d, wanted %d", n, len(contents)+10)
+	}
+
+	hr.Reset(strings.NewReader("abc"))
+	hw.Reset(ioutil.Discard)
+	if n := hr.BytesRead(); n != 0 {
+		t.Errorf("HashReader.BytesRead() after Reset got: %d, wanted 0", n)
+	}
+	if n := hw.BytesWritten(); n != 0 {
+		t.Errorf("HashWriter.BytesWritten() after Reset got: %d, wanted 0", n)
+	}
+}
+
+// errReader returns its data and then err.
+type errReader struct {
+	data []byte
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// errWriter accepts limit bytes and then returns err.
+type errWriter struct {
+	limit int
+	err   error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, w.err
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestStickyErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	hr := NewHashReader(&errReader{[]byte("partial"), boom}, StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != boom {
+		t.Fatalf("ioutil.ReadAll got error: %v, wanted %v", err, boom)
+	}
+	if err := hr.Err(); err != boom {
+		t.Errorf("HashReader.Err() got: %v, wanted %v", err, boom)
+	}
+	if hash := hr.HexHash(SHA256); hash != "" {
+		t.Errorf("HashReader.HexHash(sha256) after error got: %q, wanted \"\"", hash)
+	}
+	if sums := hr.Sums(); sums != nil {
+		t.Errorf("HashReader.Sums() after error got: %v, wanted nil", sums)
+	}
+	if _, err := hr.LookupHash(SHA256); !errors.Is(err, ErrStreamFailed) || !errors.Is(err, boom) {
+		t.Errorf("HashReader.LookupHash(sha256) got error: %v, wanted one wrapping ErrStreamFailed and %v", err, boom)
+	}
+
+	hr.Reset(strings.NewReader("fine"))
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll: %v", err)
+	}
+	if err := hr.Err(); err != nil {
+		t.Errorf("HashReader.Err() after Reset got: %v, wanted nil", err)
+	}
+
+	hw := NewHashWriter(&errWriter{4, boom}, StdCryptoHashes())
+	if _, err := hw.Write([]byte("too long")); err != boom {
+		t.Fatalf("HashWriter.Write got error: %v, wanted %v", err, boom)
+	}
+	if err := hw.Err(); err != boom {
+		t.Errorf("HashWriter.Err() got: %v, wanted %v", err, boom)
+	}
+	if buf := hw.Hash(SHA256, []byte("x")); string(buf) != "x" {
+		t.Errorf("HashWriter.Hash(sha256) after error got: %q, wanted buf unchanged", buf)
+	}
+	if _, err := hw.LookupHexHash(SHA256); !errors.Is(err, ErrStreamFailed) {
+		t.Errorf("HashWriter.LookupHexHash(sha256) got error: %v, wanted one wrapping ErrStreamFailed", err)
+	}
+}
+
+func TestNewHasher(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("os.Open(%q): %v", dataFile, err)
+	}
+	defer f.Close()
+
+	h := NewHasher(StdCryptoHashes())
+	if _, err := io.Copy(h, f); err != nil {
+		t.Fatalf("io.Copy([from: %q]): %v", dataFile, err)
+	}
+	for name, want := range map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256} {
+		if got := h.HexHash(name); got != want {
+			t.Errorf("HashWriter.HexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+
+	h.Reset(nil)
+	if _, err := h.WriteString("abc"); err != nil {
+		t.Fatalf("HashWriter.WriteString(): %v", err)
+	}
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := h.HexHash(SHA256); got != want {
+		t.Errorf("HashWriter.HexHash(sha256) after WriteString got: %q, wanted %q", got, want)
+	}
+	if got := h.BytesWritten(); got != 3 {
+		t.Errorf("HashWriter.BytesWritten() got: %d, wanted 3", got)
+	}
+	if err := h.Flush(); err != nil {
+		t.Errorf("HashWriter.Flush() with no destination: %v", err)
+	}
+}
+
+// shortWriter accepts at most limit bytes per call without returning an error,
+// breaking the io.Writer contract.
+type shortWriter struct {
+	limit int
+}
+
+func (w shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		return w.limit, nil
+	}
+	return len(p), nil
+}
+
+func TestHashWriterShortWrites(t *testing.T) {
+	boom := errors.New("boom")
+	for _, tc := range []struct {
+		desc    string
+		dst     io.Writer
+		wantErr error
+	}{
+		{"failing writer", &errWriter{3, boom}, boom},
+		{"short writer", shortWriter{3}, io.ErrShortWrite},
+	} {
+		hw := NewHashWriter(tc.dst, map[string]hash.Hash{SHA256: sha256.New()})
+		n, err := hw.Write([]byte("abcdef"))
+		if n != 3 || err != tc.wantErr {
+			t.Errorf("%s: HashWriter.Write() got: (%d, %v), wanted (3, %v)", tc.desc, n, err, tc.wantErr)
+		}
+		// The digests are withheld, but the hashes saw exactly what dst accepted.
+		want := sha256.Sum256([]byte("abc"))
+		if got := hw.hashers[SHA256].Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Errorf("%s: hashed bytes got digest: %x, wanted digest of %q: %x", tc.desc, got, "abc", want)
+		}
+		if hw.Hash(SHA256, nil) != nil {
+			t.Errorf("%s: HashWriter.Hash(sha256) after a short write got a digest, wanted nil", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package hashio
+
+import (
+	"hash"
+	"unsafe"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// appendHexSum appends the digest of h to buf as lowercase hex. If buf has
+// room for it, nothing is allocated: the digest is appended and then expanded
+// in place, back to front, so no scratch buffer is needed.
+func appendHexSum(h hash.Hash, buf []byte) []byte {
+	start := len(buf)
+	buf = h.Sum(buf)
+	n := len(buf) - start
+	buf = append(buf, buf[start:]...)
+	for i := n - 1; i >= 0; i-- {
+		b := buf[start+i]
+		buf[start+2*i] = hexDigits[b>>4]
+		buf[start+2*i+1] = hexDigits[b&0x0f]
+	}
+	return buf
+}
+
+// hexSum returns the digest of h as a lowercase hex string with a single
+// allocation.
+func hexSum(h hash.Hash) string {
+	b := appendHexSum(h, make([]byte, 0, 2*h.Size()))
+	// b is never modified again, so it can back the string directly.
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// AppendHexHash appends the hash identified by name to buf as a hex encoded
+// ASCII string and returns the slice. Nothing is allocated if buf has enough
+// spare capacity. If name does not exist in the provided hashers map passed to
+// NewHashReader, the program will panic.
+//
+// If any call to Read returned an error (not including io.EOF), buf is
+// returned unchanged. See Err.
+func (h *HashReader) AppendHexHash(name string, buf []byte) []byte {
+	if h.err != nil {
+		return buf
+	}
+	h.finalize()
+	return appendHexSum(h.hashers[name], buf)
+}
+
+// AppendHexHash appends the hash identified by name to buf as a hex encoded
+// ASCII string and returns the slice. Nothing is allocated if buf has enough
+// spare capacity. If name does not exist in the provided hashers map passed to
+// NewHashWriter, the program will panic.
+//
+// If any call to Write returned an error, buf is returned unchanged. See Err.
+func (h *HashWriter) AppendHexHash(name string, buf []byte) []byte {
+	if h.err != nil {
+		return buf
+	}
+	h.finalize()
+	return appendHexSum(h.hashers[name], buf)
+}
@@ -0,0 +1,52 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestAppendHexHash(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	hr := NewHashReader(bytes.NewReader(contents), StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	hw := NewHasher(StdCryptoHashes())
+	hw.Write(contents)
+
+	for name, want := range map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256} {
+		if got := string(hr.AppendHexHash(name, []byte("x="))); got != "x="+want {
+			t.Errorf("HashReader.AppendHexHash(%s) got: %q, wanted %q", name, got, "x="+want)
+		}
+		if got := string(hw.AppendHexHash(name, nil)); got != want {
+			t.Errorf("HashWriter.AppendHexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+		if got := hr.HexHash(name); got != want {
+			t.Errorf("HashReader.HexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+
+	buf := make([]byte, 0, 64)
+	if allocs := testing.AllocsPerRun(100, func() { buf = hw.AppendHexHash(SHA256, buf[:0]) }); allocs != 0 {
+		t.Errorf("HashWriter.AppendHexHash() made %v allocations, wanted 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { hw.HexHash(SHA256) }); allocs > 1 {
+		t.Errorf("HashWriter.HexHash() made %v allocations, wanted at most 1", allocs)
+	}
+}
+
+func TestAppendHexHashError(t *testing.T) {
+	hw := NewHashWriter(&errWriter{0, errors.New("boom")}, StdCryptoHashes())
+	hw.Write([]byte("x"))
+	if got := string(hw.AppendHexHash(SHA256, []byte("keep"))); got != "keep" {
+		t.Errorf("HashWriter.AppendHexHash() after error got: %q, wanted %q", got, "keep")
+	}
+	if got := hw.HexHash(SHA256); got != "" {
+		t.Errorf("HashWriter.HexHash() after error got: %q, wanted \"\"", got)
+	}
+}
@@ -0,0 +1,123 @@
+package hashio
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// HMACName returns the name under which NewHMACReader and NewHMACWriter store
+// the HMAC of the algorithm alg, e.g. "hmac-sha256" for SHA256.
+func HMACName(alg Algorithm) string {
+	return "hmac-" + alg
+}
+
+// NewHMACReader returns a HashReader that computes, for each algorithm in
+// algos, both the plain hash, named alg, and its HMAC keyed with key, named
+// HMACName(alg). A single pass over r thus both fingerprints and authenticates
+// it:
+//
+//	r, err := hashio.NewHMACReader(body, key, hashio.SHA256)
+//	// Read r to the end, then
+//	fingerprint := r.HexHash(hashio.SHA256)
+//	ok, err := r.VerifyHex(hashio.HMACName(hashio.SHA256), mac)
+//
+// If algos is empty, SHA256 is used. The key is copied, so the caller may
+// clear or reuse it once NewHMACReader returns. An *UnknownHashError is
+// returned if any of algos isn't registered.
+//
+// The HMACs don't implement encoding.BinaryMarshaler, so the HashReader can't
+// be saved with MarshalBinary.
+func NewHMACReader(r io.Reader, key []byte, algos ...string) (*HashReader, error) {
+	hashers, err := hmacHashers(key, algos)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashReader(r, hashers), nil
+}
+
+// NewHMACWriter is like NewHMACReader but returns a HashWriter that writes to
+// w.
+func NewHMACWriter(w io.Writer, key []byte, algos ...string) (*HashWriter, error) {
+	hashers, err := hmacHashers(key, algos)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashWriter(w, hashers), nil
+}
+
+// hmacHashers returns the plain hash and the HMAC keyed with key of every
+// algorithm in algos.
+func hmacHashers(key []byte, algos []string) (map[string]hash.Hash, error) {
+	if len(algos) == 0 {
+		algos = []string{SHA256}
+	}
+	hashers := make(map[string]hash.Hash, 2*len(algos))
+	for _, alg := range algos {
+		f, ok := lookupFactory(alg)
+		if !ok {
+			return nil, &UnknownHashError{Name: alg, Available: registered()}
+		}
+		if err := checkFIPS(alg); err != nil {
+			return nil, err
+		}
+		hashers[alg] = f()
+		hashers[HMACName(alg)] = newHMAC(f, key)
+	}
+	return hashers, nil
+}
+
+// HMACs returns an HMAC of the algorithm alg for every key in keys, under the
+// same name, to pass to NewHashReader or NewHashWriter. It serves cases where
+// one body must be authenticated under several keys, such as webhook
+// deliveries signed with a secret per tenant, in a single read:
+//
+//	hashers, err := hashio.HMACs(hashio.SHA256, map[string][]byte{"tenantA": keyA, "tenantB": keyB})
+//	r := hashio.NewHashReader(body, hashers)
+//	// Read r to the end, then
+//	ok, err := r.VerifyHex("tenantA", signatureA)
+//
+// The keys are copied. An *UnknownHashError is returned if alg isn't
+// registered.
+func HMACs(alg Algorithm, keys map[string][]byte) (map[string]hash.Hash, error) {
+	f, ok := lookupFactory(alg)
+	if !ok {
+		return nil, &UnknownHashError{Name: alg, Available: registered()}
+	}
+	if err := checkFIPS(HMACName(alg)); err != nil {
+		return nil, err
+	}
+	hashers := make(map[string]hash.Hash, len(keys))
+	for name, key := range keys {
+		hashers[name] = newHMAC(f, key)
+	}
+	return hashers, nil
+}
+
+// WithHMAC adds an HMAC of the registered algorithm alg keyed with key,
+// identified by name. Like WithNamedHash, it can be passed several times with
+// different names to authenticate one stream under several keys. The key is
+// copied, so the caller may clear or reuse it once WithHMAC returns.
+//
+// NewReader and NewWriter panic if alg isn't registered.
+func WithHMAC(name string, alg Algorithm, key []byte) Option {
+	key = append([]byte(nil), key...)
+	return func(c *config) {
+		f, ok := lookupFactory(alg)
+		if !ok {
+			panic(fmt.Sprintf("hashio: unknown hash algorithm %q for %q", alg, name))
+		}
+		c.add(name, HMACName(alg), hmac.New(f, key))
+	}
+}
+
+// newHMAC returns an HMAC of the algorithm created by f, keyed with a private
+// copy of key.
+func newHMAC(f func() hash.Hash, key []byte) hash.Hash {
+	// hmac.New derives its pads from the key without keeping it, but a private
+	// copy guards against the caller changing the key while it runs.
+	key = append([]byte(nil), key...)
+	defer clear(key)
+	return hmac.New(f, key)
+}
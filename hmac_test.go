@@ -0,0 +1,107 @@
+package hashio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const fox = "The quick brown fox jumps over the lazy dog"
+
+func TestNewHMACReader(t *testing.T) {
+	key := []byte("key")
+	r, err := NewHMACReader(strings.NewReader(fox), key, SHA256, MD5)
+	if err != nil {
+		t.Fatalf("NewHMACReader(): %v", err)
+	}
+	// The key was copied, so changing it has no effect.
+	copy(key, "xxx")
+	io.ReadAll(r)
+	for name, want := range map[string]string{
+		SHA256:           "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592",
+		HMACName(SHA256): "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		MD5:              "9e107d9d372bb6826bd81d3542a419d6",
+		HMACName(MD5):    "80070713463e7749b90c2dc24911e275",
+	} {
+		if got := r.HexHash(name); got != want {
+			t.Errorf("HashReader.HexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+	if got, want := strings.Join(r.Names(), ","), "hmac-md5,hmac-sha256,md5,sha256"; got != want {
+		t.Errorf("HashReader.Names() got: %q, wanted %q", got, want)
+	}
+}
+
+func TestNewHMACWriter(t *testing.T) {
+	var buf strings.Builder
+	w, err := NewHMACWriter(&buf, []byte("key"))
+	if err != nil {
+		t.Fatalf("NewHMACWriter(): %v", err)
+	}
+	w.WriteString(fox)
+	if ok, err := w.VerifyHex("hmac-sha256", "F7BC83F430538424B13298E6AA6FB143EF4D59A14946175997479DBC2D1A3CD8"); !ok || err != nil {
+		t.Errorf("HashWriter.VerifyHex(hmac-sha256) got: (%t, %v), wanted (true, nil)", ok, err)
+	}
+	if buf.String() != fox {
+		t.Errorf("NewHMACWriter() wrote %q, wanted %q", buf.String(), fox)
+	}
+
+	var unknown *UnknownHashError
+	if _, err := NewHMACWriter(nil, nil, "sha-256"); !errors.As(err, &unknown) {
+		t.Errorf("NewHMACWriter(sha-256) got: %v, wanted an *UnknownHashError", err)
+	}
+}
+
+func TestHMACs(t *testing.T) {
+	keys := map[string][]byte{"tenantA": []byte("keyA"), "tenantB": []byte("keyB"), "tenantC": []byte("keyC")}
+	hashers, err := HMACs(SHA256, keys)
+	if err != nil {
+		t.Fatalf("HMACs(): %v", err)
+	}
+	clear(keys["tenantA"])
+	r := NewHashReader(strings.NewReader("payload"), hashers)
+	io.ReadAll(r)
+	for name, want := range map[string]string{
+		"tenantA": "3c649d7b4d013cfdaa20230f170ae109d0e3e63634ba68b1d37b955a5b5c10dc",
+		"tenantB": "0d73418d169149425ef3cdea922f30372f2f275587d47b26f7e9502a8aab5687",
+		"tenantC": "aabef45e5a9ac4ad7fff144cf83513c102388987f0ab409326b5822af5b6bef4",
+	} {
+		if ok, err := r.VerifyHex(name, want); !ok || err != nil {
+			t.Errorf("HashReader.VerifyHex(%s, %q) got: %v, %v, wanted true, nil", name, want, ok, err)
+		}
+	}
+
+	var unknown *UnknownHashError
+	if _, err := HMACs("missing", keys); !errors.As(err, &unknown) {
+		t.Errorf("HMACs(missing) got: %v, wanted an *UnknownHashError", err)
+	}
+}
+
+func TestWithHMAC(t *testing.T) {
+	key := []byte("keyA")
+	opt := WithHMAC("tenantA", SHA256, key)
+	copy(key, "xxxx")
+	w := NewWriter(nil, opt, WithHMAC("tenantA-sha1", SHA1, []byte("keyA")), WithNamedHash("body", SHA256))
+	w.WriteString("payload")
+	for name, want := range map[string]string{
+		"tenantA":      "3c649d7b4d013cfdaa20230f170ae109d0e3e63634ba68b1d37b955a5b5c10dc",
+		"tenantA-sha1": "9ab8610d38f188e24c4c5a93ff9bc89c2007a9ff",
+		"body":         "239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5",
+	} {
+		if got := w.HexHash(name); got != want {
+			t.Errorf("HashWriter.HexHash(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+
+	for _, opt := range []Option{WithHMAC("tenant", "missing", key), WithNamedHash("body", "missing")} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewWriter() with an unknown algorithm didn't panic")
+				}
+			}()
+			NewWriter(nil, opt)
+		}()
+	}
+}
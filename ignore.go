@@ -0,0 +1,85 @@
+package hashio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// IgnoreFile is the name of the file at the root of a tree listing patterns
+// of files for HashDir to leave out, one per line, like .gitignore. Blank
+// lines and lines starting with "#" are skipped. See DirHasher.Ignore for the
+// syntax of the patterns.
+const IgnoreFile = ".hashignore"
+
+// ignorePattern is a compiled pattern of DirHasher.Ignore.
+type ignorePattern struct {
+	glob     string
+	anchored bool // matched against the whole path, not the base name
+	dirOnly  bool
+}
+
+// ignoreSet is a compiled list of ignore patterns.
+type ignoreSet []ignorePattern
+
+// compileIgnore compiles patterns, returning an error for an invalid one.
+func compileIgnore(patterns []string) (ignoreSet, error) {
+	s := make(ignoreSet, 0, len(patterns))
+	for _, pat := range patterns {
+		p := ignorePattern{glob: pat}
+		if strings.HasPrefix(pat, "!") {
+			return nil, fmt.Errorf("hashio: ignore pattern %q: negation isn't supported", pat)
+		}
+		p.glob, p.dirOnly = strings.CutSuffix(p.glob, "/")
+		p.anchored = strings.Contains(p.glob, "/")
+		p.glob = strings.TrimPrefix(p.glob, "/")
+		if _, err := path.Match(p.glob, ""); err != nil || p.glob == "" {
+			return nil, fmt.Errorf("hashio: invalid ignore pattern %q", pat)
+		}
+		s = append(s, p)
+	}
+	return s, nil
+}
+
+// match reports whether the file or directory at name, a slash-separated
+// path relative to the root, is ignored.
+func (s ignoreSet) match(name string, isDir bool) bool {
+	for _, p := range s {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		subject := name
+		if !p.anchored {
+			subject = path.Base(name)
+		}
+		if ok, _ := path.Match(p.glob, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readIgnoreFile returns the patterns of the IgnoreFile of fsys, if any.
+func readIgnoreFile(fsys fs.FS) ([]string, error) {
+	data, err := fs.ReadFile(fsys, IgnoreFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, s.Err()
+}
@@ -0,0 +1,69 @@
+package hashio
+
+import (
+	"context"
+	"io/fs"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHashDirIgnore(t *testing.T) {
+	fsys := fstest.MapFS{
+		IgnoreFile:           {Data: []byte("# volatile\n*.log\n\n/build/*.o\ncache/\n")},
+		"app.log":            {Data: []byte("1")},
+		"src/main.go":        {Data: []byte("2")},
+		"src/debug.log":      {Data: []byte("3")},
+		"build/main.o":       {Data: []byte("4")},
+		"build/sub/x.o":      {Data: []byte("5")},
+		"src/cache/entry":    {Data: []byte("6")},
+		"cache":              {Data: []byte("a file, not a directory")},
+		".git/objects/ab/cd": {Data: []byte("7")},
+		"vendor/.git/HEAD":   {Data: []byte("8")},
+		"vendor/lib/lib.go":  {Data: []byte("9")},
+		"vendor/lib/lib.log": {Mode: fs.ModeDir},
+	}
+	d := DirHasher{Algorithms: []string{SHA256}, Ignore: []string{".git"}}
+	m, err := d.Hash(context.Background(), fsys)
+	if err != nil {
+		t.Fatalf("DirHasher.Hash(): %v", err)
+	}
+	var got []string
+	for name := range m.Files {
+		got = append(got, name)
+	}
+	sort.Strings(got)
+	want := []string{IgnoreFile, "build/sub/x.o", "cache", "src/main.go", "vendor/lib/lib.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DirHasher.Hash() got files %q, wanted %q", got, want)
+	}
+	wantIgnore := []string{".git", "*.log", "/build/*.o", "cache/"}
+	if !reflect.DeepEqual(m.Ignore, wantIgnore) {
+		t.Errorf("DirHasher.Hash() got Ignore %q, wanted %q", m.Ignore, wantIgnore)
+	}
+
+	// VerifyDir ignores the same files, even once the IgnoreFile is gone.
+	delete(fsys, IgnoreFile)
+	fsys["new.log"] = &fstest.MapFile{Data: []byte("10")}
+	delete(m.Files, IgnoreFile)
+	results, err := VerifyDir(fsys, m)
+	if err != nil {
+		t.Fatalf("VerifyDir(): %v", err)
+	}
+	for _, r := range results {
+		if r.Status != FileOK {
+			t.Errorf("VerifyDir() got %+v, wanted every file OK", r)
+		}
+	}
+
+	for _, pattern := range []string{"!keep.log", "[", "/", ""} {
+		d := DirHasher{Ignore: []string{pattern}}
+		if _, err := d.Hash(context.Background(), testDir); err == nil {
+			t.Errorf("DirHasher{Ignore: %q}.Hash() got: nil, wanted an error", pattern)
+		}
+	}
+	if _, err := VerifyDir(testDir, &Manifest{Ignore: []string{"["}}); err == nil {
+		t.Errorf("VerifyDir() with an invalid pattern got: nil, wanted an error")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package hashio
+
+import "io/fs"
+
+// inode always returns 0: inode numbers are only reported on Unix.
+func inode(info fs.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package hashio
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// inode returns the inode number of the file described by info, or 0 if its
+// file system doesn't report one.
+func inode(info fs.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
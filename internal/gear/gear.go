@@ -0,0 +1,62 @@
+// Package gear implements the gear rolling hash that the content-defined
+// chunkers of hashio use to find chunk boundaries, so that every API cuts the
+// same data at the same places.
+package gear
+
+// Table maps each byte value to a pseudo-random 64 bit value for the gear
+// rolling hash. It is generated from a fixed seed so chunk boundaries are
+// stable across processes and releases.
+var Table = func() (t [256]uint64) {
+	x := uint64(0x6a09e667f3bcc908)
+	for i := range t {
+		// splitmix64
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		t[i] = z ^ (z >> 31)
+	}
+	return t
+}()
+
+// Mask returns a mask of the n high bits of the gear hash, which a boundary
+// has all clear. Using the high bits gives every mask bit a full 64 byte
+// window of influence. The average distance between boundaries is 2^n bytes.
+func Mask(n int) uint64 {
+	return ^uint64(0) << (64 - n)
+}
+
+// Cut returns the length of the chunk at the start of data: the position
+// after the first byte past min at which the gear hash has the bits of maskS
+// clear, or those of maskL once avg bytes have been hashed, and at most max.
+// Passing the same mask for both disables the normalized chunking of FastCDC.
+// data is a single chunk if it is no longer than min.
+func Cut(data []byte, min, avg, max int, maskS, maskL uint64) int {
+	if len(data) <= min {
+		return len(data)
+	}
+	n := len(data)
+	if n > max {
+		n = max
+	}
+	normal := n
+	if normal > avg {
+		normal = avg
+	}
+
+	var fp uint64
+	i := min
+	for ; i < normal; i++ {
+		fp = fp<<1 + Table[data[i]]
+		if fp&maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = fp<<1 + Table[data[i]]
+		if fp&maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,40 @@
+package gear
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCut(t *testing.T) {
+	data := make([]byte, 1<<16)
+	rand.New(rand.NewSource(1)).Read(data)
+	const min, avg, max = 256, 1024, 4096
+
+	for _, tc := range []struct {
+		name         string
+		maskS, maskL uint64
+	}{
+		{"plain", Mask(10), Mask(10)},
+		{"normalized", Mask(12), Mask(8)},
+	} {
+		var sizes []int
+		for rest := data; len(rest) > 0; {
+			n := Cut(rest, min, avg, max, tc.maskS, tc.maskL)
+			if n > max || n < min && n != len(rest) {
+				t.Fatalf("%s: Cut() got: %d, wanted a length in [%d, %d]", tc.name, n, min, max)
+			}
+			sizes = append(sizes, n)
+			rest = rest[n:]
+		}
+		if len(sizes) < len(data)/max || len(sizes) > len(data)/min {
+			t.Errorf("%s: Cut() cut %d chunks, wanted about %d", tc.name, len(sizes), len(data)/avg)
+		}
+	}
+
+	if got := Cut(data[:min], min, avg, max, Mask(10), Mask(10)); got != min {
+		t.Errorf("Cut() of %d bytes got: %d, wanted them all", min, got)
+	}
+	if got, want := Mask(3), uint64(0xe000000000000000); got != want {
+		t.Errorf("Mask(3) got: %#x, wanted %#x", got, want)
+	}
+}
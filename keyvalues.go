@@ -0,0 +1,32 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+	"sort"
+)
+
+// HashKeyValues returns a hex encoded digest of the entries in kv that does not
+// depend on map iteration order. Keys are sorted and each entry is written to a
+// fresh hash.Hash obtained from hasher as "key=value\n".
+//
+// The encoding is not escaped, so entries whose keys contain '=' or whose keys or
+// values contain '\n' may produce the same digest as a different map. Callers
+// fingerprinting untrusted data should keep that in mind.
+func HashKeyValues(kv map[string]string, hasher func() hash.Hash) string {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := hasher()
+	for _, k := range keys {
+		io.WriteString(h, k)
+		io.WriteString(h, "=")
+		io.WriteString(h, kv[k])
+		io.WriteString(h, "\n")
+	}
+
+	return hexSum(h)
+}
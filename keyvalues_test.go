@@ -0,0 +1,40 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func ExampleHashKeyValues() {
+	fmt.Println(HashKeyValues(map[string]string{"user": "mike", "mode": "ro"}, sha256.New))
+
+	// Output: a988ac7b00a728ad1392ba6c8e3cc5a64628cb987755746a239c02e332f42b00
+}
+
+func TestHashKeyValuesOrderIndependent(t *testing.T) {
+	a := make(map[string]string)
+	a["alpha"] = "1"
+	a["beta"] = "2"
+	a["gamma"] = "3"
+
+	b := make(map[string]string)
+	b["gamma"] = "3"
+	b["alpha"] = "1"
+	b["beta"] = "2"
+
+	ha, hb := HashKeyValues(a, sha256.New), HashKeyValues(b, sha256.New)
+	if ha != hb {
+		t.Errorf("HashKeyValues got: %q and %q for equal maps, wanted equal digests", ha, hb)
+	}
+
+	want := fmt.Sprintf("%x", sha256.Sum256([]byte("alpha=1\nbeta=2\ngamma=3\n")))
+	if ha != want {
+		t.Errorf("HashKeyValues got: %q, wanted %q", ha, want)
+	}
+
+	b["beta"] = "20"
+	if hb := HashKeyValues(b, sha256.New); ha == hb {
+		t.Errorf("HashKeyValues got: %q for different maps, wanted different digests", hb)
+	}
+}
@@ -0,0 +1,113 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+)
+
+// LimitedHashReader is a HashReader that also hashes just the first N bytes of
+// the stream. Every byte is still passed through, and the embedded HashReader
+// reports the digests of the whole stream as usual, while the Prefix methods
+// report the digests of at most the first N bytes. This is useful for dedup
+// heuristics that key on the hash of a fixed size prefix.
+type LimitedHashReader struct {
+	*HashReader
+	prefix    map[string]hash.Hash
+	limit     int64
+	remaining int64     // bytes left to pass to prefix
+	phw       io.Writer // writes to every hash.Hash in prefix
+}
+
+// NewLimitedHashReader returns a LimitedHashReader that reads from r, hashes the
+// whole stream and, separately, its first n bytes. Each constructor in
+// factories is called twice: once for the whole-stream hashes and once for the
+// prefix hashes, both of which are keyed by the same names. A negative n is
+// treated as 0.
+func NewLimitedHashReader(r io.Reader, n int64, factories map[string]func() hash.Hash) *LimitedHashReader {
+	if n < 0 {
+		n = 0
+	}
+	prefix := newHashers(factories)
+	l := &LimitedHashReader{
+		HashReader: NewHashReaderFromFactories(r, factories),
+		prefix:     prefix,
+		limit:      n,
+		remaining:  n,
+		phw:        hashWriter(prefix),
+	}
+	// Everything the HashReader hashes, by any read path, also reaches the
+	// prefix hashes until the limit is hit.
+	l.hw = prefixTee{l.hw, l}
+	return l
+}
+
+// prefixTee writes to hw, the writer of the whole-stream hashes, and passes at
+// most l.remaining bytes to the prefix hashes of l. AddHasher and RemoveHasher
+// rewire hw only, keeping the prefix hashes fed.
+type prefixTee struct {
+	hw io.Writer
+	l  *LimitedHashReader
+}
+
+func (w prefixTee) Write(p []byte) (int, error) {
+	w.hw.Write(p)
+	if int64(len(p)) > w.l.remaining {
+		w.l.phw.Write(p[:w.l.remaining])
+		w.l.remaining = 0
+		return len(p), nil
+	}
+	w.l.phw.Write(p)
+	w.l.remaining -= int64(len(p))
+	return len(p), nil
+}
+
+// Limit returns the number of bytes covered by the prefix digests.
+func (l *LimitedHashReader) Limit() int64 {
+	return l.limit
+}
+
+// prefixValid reports whether the prefix digests describe the data read. A
+// stream error only invalidates them if it happened before the limit.
+func (l *LimitedHashReader) prefixValid() bool {
+	return l.err == nil || l.remaining == 0
+}
+
+// PrefixHash is like Hash but appends the digest of at most the first Limit
+// bytes read. If the stream is shorter than Limit, it is the digest of the
+// whole stream. If name does not exist in the factories passed to
+// NewLimitedHashReader, the program will panic.
+//
+// If a call to Read returned an error (not including io.EOF) before Limit
+// bytes were read, buf is returned unchanged. See Err.
+func (l *LimitedHashReader) PrefixHash(name string, buf []byte) []byte {
+	if !l.prefixValid() {
+		return buf
+	}
+	return l.prefix[name].Sum(buf)
+}
+
+// PrefixHexHash is like PrefixHash but returns the digest as a hex encoded
+// ASCII string, or the empty string if the digest is withheld.
+func (l *LimitedHashReader) PrefixHexHash(name string) string {
+	if !l.prefixValid() {
+		return ""
+	}
+	return hexSum(l.prefix[name])
+}
+
+// PrefixSums is like Sums but returns the prefix digests. nil is returned when
+// they are withheld, as for PrefixHash.
+func (l *LimitedHashReader) PrefixSums() map[string][]byte {
+	if !l.prefixValid() {
+		return nil
+	}
+	return sums(l.prefix)
+}
+
+// Reset resets both the whole-stream and the prefix hashes and makes l read
+// from r.
+func (l *LimitedHashReader) Reset(r io.Reader) {
+	l.HashReader.Reset(r)
+	resetAll(l.prefix)
+	l.remaining = l.limit
+}
@@ -0,0 +1,137 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestLimitedHashReader(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	const limit = 100
+
+	for _, tc := range []struct {
+		desc string
+		read func(io.Reader) ([]byte, error)
+	}{
+		{"ReadAll", func(r io.Reader) ([]byte, error) { return ioutil.ReadAll(r) }},
+		{"WriteTo", func(r io.Reader) ([]byte, error) {
+			var buf bytes.Buffer
+			_, err := io.Copy(&buf, r)
+			return buf.Bytes(), err
+		}},
+		{"ReadByte", func(r io.Reader) ([]byte, error) {
+			var out []byte
+			for {
+				b, err := r.(io.ByteReader).ReadByte()
+				if err == io.EOF {
+					return out, nil
+				}
+				if err != nil {
+					return out, err
+				}
+				out = append(out, b)
+			}
+		}},
+	} {
+		lr := NewLimitedHashReader(bytes.NewReader(contents), limit, StdCryptoHashFactories())
+		got, err := tc.read(lr)
+		if err != nil {
+			t.Fatalf("%s: reading LimitedHashReader: %v", tc.desc, err)
+		}
+		if !bytes.Equal(got, contents) {
+			t.Errorf("%s: LimitedHashReader passed through %d bytes, wanted %d", tc.desc, len(got), len(contents))
+		}
+		if got := lr.HexHash(SHA256); got != dataFileSHA256 {
+			t.Errorf("%s: LimitedHashReader.HexHash(sha256) got: %q, wanted %q", tc.desc, got, dataFileSHA256)
+		}
+		want := fmt.Sprintf("%x", sha256.Sum256(contents[:limit]))
+		if got := lr.PrefixHexHash(SHA256); got != want {
+			t.Errorf("%s: LimitedHashReader.PrefixHexHash(sha256) got: %q, wanted %q", tc.desc, got, want)
+		}
+	}
+}
+
+func TestLimitedHashReaderShortStream(t *testing.T) {
+	lr := NewLimitedHashReader(bytes.NewReader([]byte("abc")), 1<<20, StdCryptoHashFactories())
+	if _, err := ioutil.ReadAll(lr); err != nil {
+		t.Fatalf("ioutil.ReadAll(): %v", err)
+	}
+	if got, want := lr.PrefixHexHash(SHA256), lr.HexHash(SHA256); got != want {
+		t.Errorf("LimitedHashReader.PrefixHexHash(sha256) got: %q, wanted the full digest %q", got, want)
+	}
+
+}
+
+func TestLimitedHashReaderReset(t *testing.T) {
+	lr := NewLimitedHashReader(bytes.NewReader([]byte("xyzxyz")), 3, StdCryptoHashFactories())
+	if _, err := ioutil.ReadAll(lr); err != nil {
+		t.Fatalf("ioutil.ReadAll(): %v", err)
+	}
+	lr.Reset(bytes.NewReader([]byte("abcdef")))
+	if _, err := ioutil.ReadAll(lr); err != nil {
+		t.Fatalf("ioutil.ReadAll() after Reset: %v", err)
+	}
+	want := fmt.Sprintf("%x", sha256.Sum256([]byte("abc")))
+	if got := lr.PrefixHexHash(SHA256); got != want {
+		t.Errorf("LimitedHashReader.PrefixHexHash(sha256) after Reset got: %q, wanted %q", got, want)
+	}
+}
+
+func TestLimitedHashReaderAddHasher(t *testing.T) {
+	lr := NewLimitedHashReader(bytes.NewReader([]byte("hello world")), 10, map[string]func() hash.Hash{SHA256: sha256.New})
+	if _, err := io.ReadFull(lr, make([]byte, 3)); err != nil {
+		t.Fatalf("io.ReadFull(): %v", err)
+	}
+	if err := lr.AddHasher(MD5, md5.New()); err != nil {
+		t.Fatalf("LimitedHashReader.AddHasher(md5): %v", err)
+	}
+	if _, err := io.ReadFull(lr, make([]byte, 4)); err != nil {
+		t.Fatalf("io.ReadFull(): %v", err)
+	}
+	if _, err := lr.RemoveHasher(MD5); err != nil {
+		t.Fatalf("LimitedHashReader.RemoveHasher(md5): %v", err)
+	}
+	if _, err := ioutil.ReadAll(lr); err != nil {
+		t.Fatalf("ioutil.ReadAll(): %v", err)
+	}
+	want := fmt.Sprintf("%x", sha256.Sum256([]byte("hello worl")))
+	if got := lr.PrefixHexHash(SHA256); got != want {
+		t.Errorf("LimitedHashReader.PrefixHexHash(sha256) after AddHasher and RemoveHasher got: %q, wanted %q", got, want)
+	}
+	want = fmt.Sprintf("%x", sha256.Sum256([]byte("hello world")))
+	if got := lr.HexHash(SHA256); got != want {
+		t.Errorf("LimitedHashReader.HexHash(sha256) after AddHasher and RemoveHasher got: %q, wanted %q", got, want)
+	}
+}
+
+func TestLimitedHashReaderErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	// The error comes after the prefix, which is still reported.
+	lr := NewLimitedHashReader(&errReader{[]byte("partial"), boom}, 4, StdCryptoHashFactories())
+	ioutil.ReadAll(lr)
+	if lr.HexHash(SHA256) != "" {
+		t.Errorf("LimitedHashReader.HexHash(sha256) after error got: %q, wanted \"\"", lr.HexHash(SHA256))
+	}
+	want := fmt.Sprintf("%x", sha256.Sum256([]byte("part")))
+	if got := lr.PrefixHexHash(SHA256); got != want {
+		t.Errorf("LimitedHashReader.PrefixHexHash(sha256) got: %q, wanted %q", got, want)
+	}
+
+	// The error comes before the prefix is complete.
+	lr = NewLimitedHashReader(&errReader{[]byte("partial"), boom}, 100, StdCryptoHashFactories())
+	ioutil.ReadAll(lr)
+	if got := lr.PrefixSums(); got != nil {
+		t.Errorf("LimitedHashReader.PrefixSums() got: %x, wanted nil", got)
+	}
+}
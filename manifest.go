@@ -0,0 +1,272 @@
+package hashio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// manifestHeader is the part of a serialized Manifest before its files: the
+// JSON object holding them, or the first line of NDJSON.
+type manifestHeader struct {
+	Algorithms []string `json:"algorithms"`
+	Ignore     []string `json:"ignore,omitempty"`
+}
+
+// manifestRecord is a serialized file of a Manifest.
+type manifestRecord struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime,omitzero"`
+	Inode   uint64    `json:"inode,omitempty"`
+	Sums    Results   `json:"sums"`
+}
+
+// records returns the files of m ordered by path.
+func (m *Manifest) records() []manifestRecord {
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	recs := make([]manifestRecord, len(names))
+	for i, name := range names {
+		e := m.Files[name]
+		recs[i] = manifestRecord{Path: name, Size: e.Size, ModTime: e.ModTime, Inode: e.Inode, Sums: e.Sums}
+	}
+	return recs
+}
+
+// digestSizes returns the size of the digests of each of algs known to the
+// package. The digests of others can't be checked.
+func digestSizes(algs []string) map[string]int {
+	sizes := make(map[string]int, len(algs))
+	for _, alg := range algs {
+		if h, err := newHash(alg); err == nil {
+			sizes[alg] = h.Size()
+		}
+	}
+	return sizes
+}
+
+// add adds rec to m, which must not have a file at the same path. rec must
+// hold a digest of every algorithm of m, and no other, of the size in sizes
+// if known, so that no file can be verified on its size alone.
+func (m *Manifest) add(rec manifestRecord, sizes map[string]int) error {
+	if rec.Path == "" {
+		return errors.New("hashio: manifest file without a path")
+	}
+	if _, ok := m.Files[rec.Path]; ok {
+		return fmt.Errorf("hashio: manifest file %q listed twice", rec.Path)
+	}
+	if len(m.Algorithms) == 0 {
+		return fmt.Errorf("hashio: manifest file %q listed without algorithms", rec.Path)
+	}
+	for _, alg := range m.Algorithms {
+		sum, ok := rec.Sums[alg]
+		if !ok || len(sum) == 0 {
+			return fmt.Errorf("hashio: manifest file %q has no %s digest", rec.Path, alg)
+		}
+		if size, ok := sizes[alg]; ok && len(sum) != size {
+			return fmt.Errorf("hashio: manifest file %q has a %s digest of %d bytes, not %d", rec.Path, alg, len(sum), size)
+		}
+	}
+	if len(rec.Sums) != len(m.Algorithms) {
+		return fmt.Errorf("hashio: manifest file %q has digests of algorithms other than %v", rec.Path, m.Algorithms)
+	}
+	m.Files[rec.Path] = ManifestEntry{Size: rec.Size, ModTime: rec.ModTime, Inode: rec.Inode, Sums: rec.Sums}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The files are listed in an array
+// ordered by path, with their digests hex encoded:
+//
+//	{"algorithms":["sha256"],"files":[{"path":"a.txt","size":4,
+//	"mtime":"2024-01-02T03:04:05Z","inode":1234,"sums":{"sha256":"b5bb..."}}]}
+//
+// The modification time and inode number are left out when zero, as are the
+// ignore patterns when there are none.
+func (m *Manifest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		manifestHeader
+		Files []manifestRecord `json:"files"`
+	}{manifestHeader{m.Algorithms, m.Ignore}, m.records()})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. An error is returned for a file
+// without a digest of every algorithm, or with a digest of the wrong size for
+// an algorithm known to the package.
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	var v struct {
+		manifestHeader
+		Files []manifestRecord `json:"files"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parsed := Manifest{Algorithms: v.Algorithms, Ignore: v.Ignore, Files: make(map[string]ManifestEntry, len(v.Files))}
+	sizes := digestSizes(parsed.Algorithms)
+	for _, rec := range v.Files {
+		if err := parsed.add(rec, sizes); err != nil {
+			return err
+		}
+	}
+	*m = parsed
+	return nil
+}
+
+// WriteNDJSON writes m to w as newline-delimited JSON, which can be processed
+// a line at a time: a first line holding the algorithms and ignore patterns,
+// followed by a line per file ordered by path, in the format of the files of
+// MarshalJSON.
+func (m *Manifest) WriteNDJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(manifestHeader{m.Algorithms, m.Ignore}); err != nil {
+		return err
+	}
+	for _, rec := range m.records() {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadManifestNDJSON reads a manifest written by Manifest.WriteNDJSON. Like
+// UnmarshalJSON, it returns an error for a file without a digest of every
+// algorithm, or with a digest of the wrong size for an algorithm known to the
+// package.
+func ReadManifestNDJSON(r io.Reader) (*Manifest, error) {
+	dec := json.NewDecoder(r)
+	var h manifestHeader
+	if err := dec.Decode(&h); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("hashio: reading manifest header: %w", err)
+	}
+	m := &Manifest{Algorithms: h.Algorithms, Ignore: h.Ignore, Files: make(map[string]ManifestEntry)}
+	sizes := digestSizes(m.Algorithms)
+	for {
+		var rec manifestRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := m.add(rec, sizes); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// csvColumns are the columns of a CSV manifest before those of its digests.
+var csvColumns = []string{"path", "size", "mtime", "inode"}
+
+// WriteCSV writes m to w as CSV, with a header row naming the columns: path,
+// size, mtime (RFC 3339), inode, then one per algorithm holding the hex
+// digests. A row per file follows, ordered by path; zero modification times
+// and inode numbers are left empty. The ignore patterns aren't written, since
+// the format has no place for them.
+func (m *Manifest) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(slices.Clone(csvColumns), m.Algorithms...)); err != nil {
+		return err
+	}
+	row := make([]string, len(csvColumns)+len(m.Algorithms))
+	for _, rec := range m.records() {
+		row[0] = rec.Path
+		row[1] = strconv.FormatInt(rec.Size, 10)
+		row[2], row[3] = "", ""
+		if !rec.ModTime.IsZero() {
+			row[2] = rec.ModTime.Format(time.RFC3339Nano)
+		}
+		if rec.Inode != 0 {
+			row[3] = strconv.FormatUint(rec.Inode, 10)
+		}
+		for i, alg := range m.Algorithms {
+			row[len(csvColumns)+i] = hex.EncodeToString(rec.Sums[alg])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadManifestCSV reads a manifest written by Manifest.WriteCSV. Like
+// UnmarshalJSON and ReadManifestNDJSON, it returns an error for a file
+// without a digest of every algorithm, or with a digest of the wrong size for
+// an algorithm known to the package.
+func ReadManifestCSV(r io.Reader) (*Manifest, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hashio: reading manifest header: %w", err)
+	}
+	if len(header) < len(csvColumns) || !slices.Equal(header[:len(csvColumns)], csvColumns) {
+		return nil, fmt.Errorf("hashio: manifest header %q doesn't start with %q", header, csvColumns)
+	}
+	m := &Manifest{Algorithms: header[len(csvColumns):], Files: make(map[string]ManifestEntry)}
+	sizes := digestSizes(m.Algorithms)
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec, err := parseCSVRecord(row, m.Algorithms)
+		if err != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("hashio: manifest line %d: %w", line, err)
+		}
+		if err := m.add(rec, sizes); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseCSVRecord parses a row of a CSV manifest with digests of algs.
+func parseCSVRecord(row []string, algs []string) (manifestRecord, error) {
+	rec := manifestRecord{Path: row[0], Sums: make(Results, len(algs))}
+	var err error
+	if rec.Size, err = strconv.ParseInt(row[1], 10, 64); err != nil {
+		return rec, fmt.Errorf("invalid size: %w", err)
+	}
+	if row[2] != "" {
+		if rec.ModTime, err = time.Parse(time.RFC3339Nano, row[2]); err != nil {
+			return rec, fmt.Errorf("invalid mtime: %w", err)
+		}
+	}
+	if row[3] != "" {
+		if rec.Inode, err = strconv.ParseUint(row[3], 10, 64); err != nil {
+			return rec, fmt.Errorf("invalid inode: %w", err)
+		}
+	}
+	for i, alg := range algs {
+		cell := row[len(csvColumns)+i]
+		if cell == "" {
+			return rec, fmt.Errorf("no %s digest", alg)
+		}
+		if rec.Sums[alg], err = hex.DecodeString(cell); err != nil {
+			return rec, fmt.Errorf("invalid hex digest for %q: %w", alg, err)
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,144 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testManifest returns a manifest exercising every field.
+func testManifest() *Manifest {
+	return &Manifest{
+		Algorithms: []string{MD5, SHA256},
+		Ignore:     []string{"*.log", ".git/"},
+		Files: map[string]ManifestEntry{
+			"sub/bar.bin": {Size: 3, Sums: Results{
+				MD5:    mustDecodeHex("37b51d194a7513e45b56f6524f2d51f2"),
+				SHA256: mustDecodeHex("fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"),
+			}},
+			`a "b", c.txt`: {Size: 4, ModTime: time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC), Inode: 1234, Sums: Results{
+				MD5:    mustDecodeHex("d3b07384d113edec49eaa6238ad5ff00"),
+				SHA256: mustDecodeHex("b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"),
+			}},
+		},
+	}
+}
+
+func TestManifestJSON(t *testing.T) {
+	m := testManifest()
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	want := `{"algorithms":["md5","sha256"],"ignore":["*.log",".git/"],"files":[` +
+		`{"path":"a \"b\", c.txt","size":4,"mtime":"2024-01-02T03:04:05.0000006Z","inode":1234,"sums":{"md5":"d3b07384d113edec49eaa6238ad5ff00","sha256":"b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"}},` +
+		`{"path":"sub/bar.bin","size":3,"sums":{"md5":"37b51d194a7513e45b56f6524f2d51f2","sha256":"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"}}]}`
+	if string(b) != want {
+		t.Errorf("json.Marshal() got: %s, wanted %s", b, want)
+	}
+
+	var got Manifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(): %v", err)
+	}
+	if !reflect.DeepEqual(&got, m) {
+		t.Errorf("json.Unmarshal() got: %+v, wanted %+v", got, m)
+	}
+
+	dup := `{"algorithms":["md5"],"files":[{"path":"a","size":0},{"path":"a","size":0}]}`
+	if err := json.Unmarshal([]byte(dup), &got); err == nil {
+		t.Errorf("json.Unmarshal() of a file listed twice got: nil, wanted an error")
+	}
+	if err := json.Unmarshal([]byte(`{"files":[{"size":0}]}`), &got); err == nil {
+		t.Errorf("json.Unmarshal() of a file without path got: nil, wanted an error")
+	}
+	for _, in := range []string{
+		`{"algorithms":["sha256"],"files":[{"path":"a.txt","size":4,"sums":{}}]}`,
+		`{"algorithms":["sha256"],"files":[{"path":"a.txt","size":4}]}`,
+		`{"algorithms":["md5","sha256"],"files":[{"path":"a.txt","size":4,"sums":{"md5":"d3b07384d113edec49eaa6238ad5ff00"}}]}`,
+		`{"algorithms":["md5"],"files":[{"path":"a.txt","size":4,"sums":{"md5":"d3b07384"}}]}`,
+		`{"algorithms":["md5"],"files":[{"path":"a.txt","size":4,"sums":{"md5":"d3b07384d113edec49eaa6238ad5ff00","sha1":"00"}}]}`,
+		`{"files":[{"path":"a.txt","size":4}]}`,
+	} {
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("json.Unmarshal(%s) got: nil, wanted an error", in)
+		}
+	}
+}
+
+func TestManifestNDJSON(t *testing.T) {
+	m := testManifest()
+	var b bytes.Buffer
+	if err := m.WriteNDJSON(&b); err != nil {
+		t.Fatalf("Manifest.WriteNDJSON(): %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != `{"algorithms":["md5","sha256"],"ignore":["*.log",".git/"]}` || !strings.HasPrefix(lines[2], `{"path":"sub/bar.bin",`) {
+		t.Errorf("Manifest.WriteNDJSON() got: %q, wanted a header line and a line per file", lines)
+	}
+
+	got, err := ReadManifestNDJSON(&b)
+	if err != nil {
+		t.Fatalf("ReadManifestNDJSON(): %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ReadManifestNDJSON() got: %+v, wanted %+v", got, m)
+	}
+
+	for _, in := range []string{
+		"",
+		"{",
+		`{"algorithms":["md5"]}` + "\n" + `{"path":"a","sums":{"md5":"zz"}}`,
+		`{"algorithms":["md5"]}` + "\n" + `{"path":"a","size":4,"sums":{}}`,
+		`{"algorithms":["md5"]}` + "\n" + `{"path":"a","size":4,"sums":{"md5":"d3b0"}}`,
+	} {
+		if got, err := ReadManifestNDJSON(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadManifestNDJSON(%q) got: %+v, wanted an error", in, got)
+		}
+	}
+}
+
+func TestManifestCSV(t *testing.T) {
+	m := testManifest()
+	var b bytes.Buffer
+	if err := m.WriteCSV(&b); err != nil {
+		t.Fatalf("Manifest.WriteCSV(): %v", err)
+	}
+	want := "path,size,mtime,inode,md5,sha256\n" +
+		`"a ""b"", c.txt",4,2024-01-02T03:04:05.0000006Z,1234,d3b07384d113edec49eaa6238ad5ff00,b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c` + "\n" +
+		"sub/bar.bin,3,,,37b51d194a7513e45b56f6524f2d51f2,fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9\n"
+	if got := b.String(); got != want {
+		t.Errorf("Manifest.WriteCSV() got: %q, wanted %q", got, want)
+	}
+
+	got, err := ReadManifestCSV(&b)
+	if err != nil {
+		t.Fatalf("ReadManifestCSV(): %v", err)
+	}
+	// The ignore patterns aren't recorded.
+	m.Ignore = nil
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ReadManifestCSV() got: %+v, wanted %+v", got, m)
+	}
+
+	for _, in := range []string{
+		"",
+		"name,size\n",
+		"path,size,mtime,inode,md5\na,x,,,\n",
+		"path,size,mtime,inode,md5\na,1,yesterday,,\n",
+		"path,size,mtime,inode,md5\na,1,,-1,\n",
+		"path,size,mtime,inode,md5\na,1,,,zz\n",
+		"path,size,mtime,inode,sha256\na.txt,4,,,\n",
+		"path,size,mtime,inode,md5\na,1,,,d3b07384\n",
+		"path,size,mtime,inode\na,1,,\n",
+		"path,size,mtime,inode,md5\na,1,,\n",
+		"path,size,mtime,inode,md5\na,1,,,\na,1,,,\n",
+	} {
+		if got, err := ReadManifestCSV(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadManifestCSV(%q) got: %+v, wanted an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package merkle builds Merkle trees over streamed data and produces and
+// verifies inclusion proofs for individual leaves.
+//
+// Trees follow the construction of RFC 6962 (Certificate Transparency): a
+// leaf is hashed as H(0x00 || data) and an interior node as
+// H(0x01 || left || right), which keeps leaves and nodes from being confused
+// for one another. The data is split into leaves of a fixed size; only the
+// last leaf may be shorter.
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// Tree is a Merkle tree built from the data written to it. It implements
+// hash.Hash, with the root as the digest, so it can be used as one of the
+// hashes of a hashio.HashReader or hashio.HashWriter. The hash of every leaf
+// is kept so that inclusion proofs can be produced for any leaf.
+type Tree struct {
+	newHash  func() hash.Hash
+	h        hash.Hash // scratch hash used for leaves and nodes
+	leafSize int
+	leaves   [][]byte // hashes of the complete leaves
+	buf      []byte   // data of the incomplete last leaf
+}
+
+var _ hash.Hash = (*Tree)(nil)
+
+// New returns an empty Tree splitting data into leaves of leafSize bytes and
+// hashing them with hashes from newHash. It panics if leafSize is not
+// positive.
+func New(leafSize int, newHash func() hash.Hash) *Tree {
+	if leafSize <= 0 {
+		panic("merkle: leaf size must be positive")
+	}
+	return &Tree{
+		newHash:  newHash,
+		h:        newHash(),
+		leafSize: leafSize,
+		buf:      make([]byte, 0, leafSize),
+	}
+}
+
+// Write adds p to the data of the tree. It never returns an error.
+func (t *Tree) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		k := copy(t.buf[len(t.buf):t.leafSize], p)
+		t.buf = t.buf[:len(t.buf)+k]
+		p = p[k:]
+		// A full leaf is only hashed once more data arrives, so the last
+		// leaf is always in buf.
+		if len(t.buf) == t.leafSize && len(p) > 0 {
+			t.leaves = append(t.leaves, t.hashLeaf(t.buf))
+			t.buf = t.buf[:0]
+		}
+	}
+	return n, nil
+}
+
+// Leaves returns the number of leaves in the tree.
+func (t *Tree) Leaves() int {
+	if len(t.leaves) == 0 && len(t.buf) == 0 {
+		return 0
+	}
+	return len(t.leaves) + 1
+}
+
+// leafHashes returns the hashes of all leaves, including the last one.
+func (t *Tree) leafHashes() [][]byte {
+	if len(t.buf) == 0 && len(t.leaves) == 0 {
+		return nil
+	}
+	return append(t.leaves[:len(t.leaves):len(t.leaves)], t.hashLeaf(t.buf))
+}
+
+// LeafHash returns the hash of leaf i.
+func (t *Tree) LeafHash(i int) ([]byte, error) {
+	if i < 0 || i >= t.Leaves() {
+		return nil, fmt.Errorf("merkle: leaf %d out of range [0, %d)", i, t.Leaves())
+	}
+	if i < len(t.leaves) {
+		return bytes.Clone(t.leaves[i]), nil
+	}
+	return t.hashLeaf(t.buf), nil
+}
+
+// Root returns the root of the tree. The root of an empty tree is the hash of
+// no data.
+func (t *Tree) Root() []byte {
+	return t.Sum(nil)
+}
+
+// Sum appends the root of the tree to b. It does not change the tree.
+func (t *Tree) Sum(b []byte) []byte {
+	leaves := t.leafHashes()
+	if len(leaves) == 0 {
+		t.h.Reset()
+		return t.h.Sum(b)
+	}
+	return append(b, t.subtree(leaves)...)
+}
+
+// subtree returns the root of the tree over leaves.
+func (t *Tree) subtree(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return t.hashNode(t.subtree(leaves[:k]), t.subtree(leaves[k:]))
+}
+
+// Proof returns the inclusion proof of leaf i: the hashes of the sibling
+// subtrees on the path from the leaf to the root, deepest first, as defined
+// by RFC 6962.
+func (t *Tree) Proof(i int) ([][]byte, error) {
+	if i < 0 || i >= t.Leaves() {
+		return nil, fmt.Errorf("merkle: leaf %d out of range [0, %d)", i, t.Leaves())
+	}
+	return t.path(i, t.leafHashes()), nil
+}
+
+func (t *Tree) path(i int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := split(len(leaves))
+	if i < k {
+		return append(t.path(i, leaves[:k]), t.subtree(leaves[k:]))
+	}
+	return append(t.path(i-k, leaves[k:]), t.subtree(leaves[:k]))
+}
+
+// Reset empties the tree.
+func (t *Tree) Reset() {
+	t.leaves = nil
+	t.buf = t.buf[:0]
+}
+
+// Size returns the size of the root in bytes.
+func (t *Tree) Size() int { return t.h.Size() }
+
+// BlockSize returns the leaf size.
+func (t *Tree) BlockSize() int { return t.leafSize }
+
+func (t *Tree) hashLeaf(data []byte) []byte {
+	return hashLeaf(t.h, data)
+}
+
+func (t *Tree) hashNode(left, right []byte) []byte {
+	return hashNode(t.h, left, right)
+}
+
+func hashLeaf(h hash.Hash, data []byte) []byte {
+	h.Reset()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func hashNode(h hash.Hash, left, right []byte) []byte {
+	h.Reset()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// split returns the number of leaves in the left subtree of a tree of n > 1
+// leaves: the largest power of two smaller than n.
+func split(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// ErrInvalidProof is returned by Verify when a proof doesn't show that the
+// leaf is part of the tree.
+var ErrInvalidProof = errors.New("merkle: invalid inclusion proof")
+
+// Verify checks that leaf, the data of leaf index in a tree of count leaves,
+// is included in the tree with the given root according to proof, as returned
+// by Tree.Proof. It returns nil if so and ErrInvalidProof otherwise.
+func Verify(root []byte, index, count int, leaf []byte, proof [][]byte, newHash func() hash.Hash) error {
+	h := newHash()
+	return VerifyLeafHash(root, index, count, hashLeaf(h, leaf), proof, newHash)
+}
+
+// VerifyLeafHash is like Verify but takes the hash of the leaf, as returned
+// by Tree.LeafHash, instead of its data.
+func VerifyLeafHash(root []byte, index, count int, leafHash []byte, proof [][]byte, newHash func() hash.Hash) error {
+	if index < 0 || index >= count {
+		return ErrInvalidProof
+	}
+	h := newHash()
+	// The verification algorithm of RFC 9162, section 2.1.3.2.
+	fn, sn := index, count-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			r = hashNode(h, p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = hashNode(h, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root) {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/mikewiacek/hashio"
+)
+
+// rfc6962Leaves and rfc6962Roots are the test vectors used by Certificate
+// Transparency implementations: rfc6962Roots[i] is the root of the tree over
+// the first i+1 leaves.
+var (
+	rfc6962Leaves = []string{
+		"", "00", "10", "2021", "3031", "40414243",
+		"5051525354555657", "606162636465666768696a6b6c6d6e6f",
+	}
+	rfc6962Roots = []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+)
+
+func TestRFC6962Vectors(t *testing.T) {
+	tree := New(1, sha256.New)
+	var leaves [][]byte
+	for i, l := range rfc6962Leaves {
+		data, _ := hex.DecodeString(l)
+		leaves = append(leaves, tree.hashLeaf(data))
+		if got := hex.EncodeToString(tree.subtree(leaves)); got != rfc6962Roots[i] {
+			t.Errorf("root of %d leaves got: %q, wanted %q", i+1, got, rfc6962Roots[i])
+		}
+		for j := range leaves {
+			proof := tree.path(j, leaves)
+			root := tree.subtree(leaves)
+			if err := VerifyLeafHash(root, j, len(leaves), leaves[j], proof, sha256.New); err != nil {
+				t.Errorf("VerifyLeafHash(leaf %d of %d): %v", j, len(leaves), err)
+			}
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	data := make([]byte, 10*1000+7)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	const leafSize = 1000
+
+	tree := New(leafSize, sha256.New)
+	for i := 0; i < len(data); i += 333 {
+		tree.Write(data[i:min(i+333, len(data))])
+	}
+	if got := tree.Leaves(); got != 11 {
+		t.Fatalf("Tree.Leaves() got: %d, wanted 11", got)
+	}
+	root := tree.Root()
+
+	for i := 0; i < tree.Leaves(); i++ {
+		leaf := data[i*leafSize : min((i+1)*leafSize, len(data))]
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatalf("Tree.Proof(%d): %v", i, err)
+		}
+		if err := Verify(root, i, tree.Leaves(), leaf, proof, sha256.New); err != nil {
+			t.Errorf("Verify(leaf %d): %v", i, err)
+		}
+		bad := append(bytes.Clone(leaf[:len(leaf)-1]), leaf[len(leaf)-1]^1)
+		if err := Verify(root, i, tree.Leaves(), bad, proof, sha256.New); err != ErrInvalidProof {
+			t.Errorf("Verify(corrupted leaf %d) got error: %v, wanted %v", i, err, ErrInvalidProof)
+		}
+		if i > 0 {
+			if err := Verify(root, i-1, tree.Leaves(), leaf, proof, sha256.New); err != ErrInvalidProof {
+				t.Errorf("Verify(leaf %d as leaf %d) got error: %v, wanted %v", i, i-1, err, ErrInvalidProof)
+			}
+		}
+	}
+
+	if _, err := tree.Proof(11); err == nil {
+		t.Errorf("Tree.Proof(11) got no error, wanted an out of range error")
+	}
+
+	tree.Reset()
+	if got, want := tree.Root(), sha256.Sum256(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("Tree.Root() of an empty tree got: %x, wanted %x", got, want)
+	}
+}
+
+func TestTreeAsHash(t *testing.T) {
+	data := bytes.Repeat([]byte("merkle"), 1000)
+	tree := New(256, sha256.New)
+	tree.Write(data)
+	want := hex.EncodeToString(tree.Root())
+
+	hw := hashio.NewHasher(map[string]hash.Hash{"merkle": New(256, sha256.New)})
+	if _, err := hw.Write(data); err != nil {
+		t.Fatalf("HashWriter.Write(): %v", err)
+	}
+	if got := hw.HexHash("merkle"); got != want {
+		t.Errorf("HashWriter.HexHash(merkle) got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package hashio
+
+import (
+	"hash"
+	"os"
+)
+
+// mmapWindow is the size of the file regions HashFileMmap maps at a time, so
+// that hashing a very large file doesn't need a mapping of its full size.
+const mmapWindow = 256 << 20
+
+// HashFileMmap is like HashFile but passes the contents of the file at path
+// to every hash.Hash in hashers straight from a memory mapping, avoiding the
+// copy through a userspace buffer that reading requires. The digests are
+// returned keyed by name.
+//
+// Memory mapping is used on Unix systems. Elsewhere, or if the file cannot be
+// mapped (for example because it is empty or not a regular file), the file is
+// streamed as by HashFile instead.
+//
+// The file must not be truncated while it is being hashed: on most systems
+// accessing a mapped region past the end of the file kills the program.
+func HashFileMmap(path string, hashers map[string]hash.Hash) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := NewHasher(hashers)
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode().IsRegular() && fi.Size() > 0 {
+		ok, err := hashMmap(h, f, fi.Size())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return h.Sums(), nil
+		}
+	}
+
+	if _, err := h.ReadFrom(f); err != nil {
+		return nil, err
+	}
+	return h.Sums(), nil
+}
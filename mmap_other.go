@@ -0,0 +1,10 @@
+//go:build !unix
+
+package hashio
+
+import "os"
+
+// hashMmap always reports false: memory mapping is only supported on Unix.
+func hashMmap(h *HashWriter, f *os.File, size int64) (bool, error) {
+	return false, nil
+}
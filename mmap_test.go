@@ -0,0 +1,39 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashFileMmap(t *testing.T) {
+	sums, err := HashFileMmap(dataFile, StdCryptoHashes())
+	if err != nil {
+		t.Fatalf("HashFileMmap(%q): %v", dataFile, err)
+	}
+	for name, want := range map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256} {
+		if got := hex.EncodeToString(sums[name]); got != want {
+			t.Errorf("HashFileMmap(%q)[%s] got: %q, wanted %q", dataFile, name, got, want)
+		}
+	}
+
+	// Empty files can't be mapped and are streamed instead.
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatalf("os.WriteFile(%q): %v", empty, err)
+	}
+	sums, err = HashFileMmap(empty, map[string]hash.Hash{SHA256: sha256.New()})
+	if err != nil {
+		t.Fatalf("HashFileMmap(%q): %v", empty, err)
+	}
+	if got, want := sums[SHA256], sha256.Sum256(nil); string(got) != string(want[:]) {
+		t.Errorf("HashFileMmap(empty file) got: %x, wanted %x", got, want)
+	}
+
+	if _, err := HashFileMmap("testdata/does_not_exist", StdCryptoHashes()); !os.IsNotExist(err) {
+		t.Errorf("HashFileMmap(missing file) got error: %v, wanted a not exist error", err)
+	}
+}
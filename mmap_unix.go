@@ -0,0 +1,29 @@
+//go:build unix
+
+package hashio
+
+import (
+	"os"
+	"syscall"
+)
+
+// hashMmap writes the size bytes of f to h by mapping them into memory
+// mmapWindow bytes at a time. It reports false, having written nothing, if
+// the first mapping fails, so the caller can fall back to reading f.
+func hashMmap(h *HashWriter, f *os.File, size int64) (bool, error) {
+	for off := int64(0); off < size; off += mmapWindow {
+		n := min(size-off, mmapWindow)
+		b, err := syscall.Mmap(int(f.Fd()), off, int(n), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			if off == 0 {
+				return false, nil
+			}
+			return false, &os.PathError{Op: "mmap", Path: f.Name(), Err: err}
+		}
+		h.Write(b)
+		if err := syscall.Munmap(b); err != nil {
+			return false, &os.PathError{Op: "munmap", Path: f.Name(), Err: err}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,96 @@
+package hashio
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// multihashCodes maps algorithm names to their code in the multiformats
+// multihash table (https://github.com/multiformats/multicodec).
+var multihashCodes = map[string]uint64{
+	SHA1:         0x11,
+	SHA256:       0x12,
+	SHA512:       0x13,
+	SHA3_512:     0x14,
+	SHA3_384:     0x15,
+	SHA3_256:     0x16,
+	"sha3-224":   0x17,
+	SHAKE128:     0x18,
+	SHAKE256:     0x19,
+	SHA384:       0x20,
+	BLAKE3:       0x1e,
+	MD5:          0xd5,
+	"sha224":     0x1013,
+	"sha512-224": 0x1014,
+	SHA512_256:   0x1015,
+	"ripemd160":  0x1053,
+	BLAKE2b_256:  0xb220,
+	BLAKE2b_384:  0xb230,
+	BLAKE2b_512:  0xb240,
+	BLAKE2s_256:  0xb260,
+}
+
+// EncodeMultihash encodes d in the multihash format: the varint multicodec
+// code of d.Algorithm, the varint length of d.Sum and then d.Sum itself. An
+// error is returned if d.Algorithm has no multihash code known to this package.
+func EncodeMultihash(d Digest) ([]byte, error) {
+	code, ok := multihashCodes[d.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("hashio: no multihash code for %q", d.Algorithm)
+	}
+
+	b := make([]byte, 0, 2*binary.MaxVarintLen64+len(d.Sum))
+	b = binary.AppendUvarint(b, code)
+	b = binary.AppendUvarint(b, uint64(len(d.Sum)))
+	return append(b, d.Sum...), nil
+}
+
+// DecodeMultihash decodes a multihash produced by EncodeMultihash or any other
+// multihash implementation using one of the codes known to this package.
+func DecodeMultihash(b []byte) (Digest, error) {
+	code, n := binary.Uvarint(b)
+	if n <= 0 {
+		return Digest{}, fmt.Errorf("hashio: invalid multihash code")
+	}
+	b = b[n:]
+
+	size, n := binary.Uvarint(b)
+	if n <= 0 {
+		return Digest{}, fmt.Errorf("hashio: invalid multihash length")
+	}
+	b = b[n:]
+	if uint64(len(b)) != size {
+		return Digest{}, fmt.Errorf("hashio: multihash length is %d, but %d bytes follow", size, len(b))
+	}
+
+	for name, c := range multihashCodes {
+		if c == code {
+			return Digest{Algorithm: name, Sum: b}, nil
+		}
+	}
+	return Digest{}, fmt.Errorf("hashio: unknown multihash code %#x", code)
+}
+
+// Multihash returns the hash identified by name in multihash format, for IPFS
+// and libp2p interoperability. name must be the name of an algorithm with a
+// multihash code, such as "sha256" or "blake2b-256". An error is returned in
+// the same cases as LookupHash, or if name has no multihash code.
+func (h *HashReader) Multihash(name string) ([]byte, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeMultihash(Digest{Algorithm: name, Sum: sum})
+}
+
+// Multihash returns the hash identified by name in multihash format, for IPFS
+// and libp2p interoperability. name must be the name of an algorithm with a
+// multihash code, such as "sha256" or "blake2b-256". An error is returned in
+// the same cases as LookupHash, or if name has no multihash code.
+func (h *HashWriter) Multihash(name string) ([]byte, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeMultihash(Digest{Algorithm: name, Sum: sum})
+}
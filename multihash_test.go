@@ -0,0 +1,46 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+)
+
+func TestMultihash(t *testing.T) {
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write([]byte("hello I am happy"))
+
+	mh, err := hw.Multihash(SHA256)
+	if err != nil {
+		t.Fatalf("HashWriter.Multihash(sha256): %v", err)
+	}
+	want := "1220" + "1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a"
+	if got := hex.EncodeToString(mh); got != want {
+		t.Errorf("HashWriter.Multihash(sha256) got: %s, wanted %s", got, want)
+	}
+
+	d, err := DecodeMultihash(mh)
+	if err != nil {
+		t.Fatalf("DecodeMultihash: %v", err)
+	}
+	if d.Algorithm != SHA256 || !bytes.Equal(d.Sum, mh[2:]) {
+		t.Errorf("DecodeMultihash got: %v, wanted sha256:%x", d, mh[2:])
+	}
+
+	// Codes above 127 need more than one varint byte.
+	mh, err = EncodeMultihash(Digest{Algorithm: "blake2b-256", Sum: make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("EncodeMultihash(blake2b-256): %v", err)
+	}
+	if got := hex.EncodeToString(mh[:4]); got != "a0e40220" {
+		t.Errorf("EncodeMultihash(blake2b-256) prefix got: %s, wanted a0e40220", got)
+	}
+
+	if _, err := EncodeMultihash(Digest{Algorithm: "custom", Sum: []byte{1}}); err == nil {
+		t.Errorf("EncodeMultihash(custom) got: nil error, wanted an error")
+	}
+	if _, err := DecodeMultihash([]byte{0x12, 0x20, 0x01}); err == nil {
+		t.Errorf("DecodeMultihash(truncated) got: nil error, wanted an error")
+	}
+}
@@ -0,0 +1,83 @@
+package hashio
+
+import (
+	"fmt"
+	"hash"
+	"io"
+)
+
+// DestinationError is returned by a HashWriter created with
+// NewHashMultiWriter when one of its destinations fails. Index is the position
+// of that destination in the list passed to NewHashMultiWriter.
+type DestinationError struct {
+	Index int
+	Err   error
+}
+
+func (e *DestinationError) Error() string {
+	return fmt.Sprintf("hashio: destination %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error returned by the destination.
+func (e *DestinationError) Unwrap() error {
+	return e.Err
+}
+
+// NewHashMultiWriter returns a HashWriter that writes to every destination in
+// dst, in order, and hashes the data once. It behaves like wrapping an
+// io.MultiWriter, except that errors identify which destination failed.
+//
+// A Write stops at the first destination that fails or accepts fewer bytes
+// than given, and returns a *DestinationError wrapping that destination's
+// error (io.ErrShortWrite for a short write), along with the number of bytes
+// that destination accepted. Earlier destinations got all of the data and
+// later ones none of it; only the bytes accepted by the failed destination,
+// which every destination up to it got, are hashed and counted by
+// BytesWritten. The error is recorded as for any other HashWriter. Flush and
+// Sync are forwarded to every destination that implements them.
+func NewHashMultiWriter(hashers map[string]hash.Hash, dst ...io.Writer) *HashWriter {
+	return NewHashWriter(multiDst(append([]io.Writer(nil), dst...)), hashers)
+}
+
+// multiDst is the io.Writer behind NewHashMultiWriter.
+type multiDst []io.Writer
+
+func (m multiDst) Write(p []byte) (int, error) {
+	for i, w := range m {
+		n, err := w.Write(p)
+		if err == nil && n != len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return n, &DestinationError{Index: i, Err: err}
+		}
+	}
+	return len(p), nil
+}
+
+// Flush flushes every destination with a Flush method and returns the first
+// error.
+func (m multiDst) Flush() error {
+	var first error
+	for i, w := range m {
+		if f, ok := w.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil && first == nil {
+				first = &DestinationError{Index: i, Err: err}
+			}
+		}
+	}
+	return first
+}
+
+// Sync is like Flush for destinations with a Sync method.
+func (m multiDst) Sync() error {
+	var first error
+	for i, w := range m {
+		if s, ok := w.(interface{ Sync() error }); ok {
+			if err := s.Sync(); err != nil && first == nil {
+				first = &DestinationError{Index: i, Err: err}
+			}
+		}
+	}
+	return first
+}
@@ -0,0 +1,58 @@
+package hashio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestHashMultiWriter(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("os.Open(%q): %v", dataFile, err)
+	}
+	defer f.Close()
+
+	var local, remote bytes.Buffer
+	bw := bufio.NewWriter(&remote)
+	hw := NewHashMultiWriter(StdCryptoHashes(), &local, bw)
+	if _, err := io.Copy(hw, f); err != nil {
+		t.Fatalf("io.Copy([from: %q]): %v", dataFile, err)
+	}
+	if err := hw.Flush(); err != nil {
+		t.Fatalf("HashWriter.Flush(): %v", err)
+	}
+	if !bytes.Equal(local.Bytes(), remote.Bytes()) || int64(local.Len()) != hw.BytesWritten() {
+		t.Errorf("destinations got %d and %d bytes, wanted %d each", local.Len(), remote.Len(), hw.BytesWritten())
+	}
+	if got := hw.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+}
+
+func TestHashMultiWriterErrors(t *testing.T) {
+	boom := errors.New("boom")
+	var first, third bytes.Buffer
+	hw := NewHashMultiWriter(StdCryptoHashes(), &first, &errWriter{2, boom}, &third)
+
+	n, err := hw.Write([]byte("hello"))
+	var de *DestinationError
+	if !errors.As(err, &de) || de.Index != 1 || !errors.Is(err, boom) {
+		t.Fatalf("HashWriter.Write() got error: %v, wanted *DestinationError{Index: 1} wrapping %v", err, boom)
+	}
+	if third.Len() != 0 {
+		t.Errorf("destination after the failed one got %q, wanted nothing", third.String())
+	}
+	if n != 2 || hw.BytesWritten() != 2 || first.String() != "hello" {
+		t.Errorf("HashWriter.Write() got: %d, BytesWritten() %d and a first destination with %q, wanted 2, 2 and %q", n, hw.BytesWritten(), first.String(), "hello")
+	}
+	if hw.Err() != err {
+		t.Errorf("HashWriter.Err() got: %v, wanted %v", hw.Err(), err)
+	}
+	if got := hw.HexHash(SHA256); got != "" {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted \"\"", got)
+	}
+}
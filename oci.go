@@ -0,0 +1,85 @@
+package hashio
+
+import (
+	"crypto/subtle"
+	"hash"
+	"io"
+)
+
+// Digest returns the hash identified by name as an OCI style digest string,
+// "<name>:<hex>", e.g. "sha256:1963f25b...", as used by container registries
+// and github.com/opencontainers/go-digest. It panics and returns the empty
+// string in the same cases as HexHash.
+func (h *HashReader) Digest(name string) string {
+	sum := h.Hash(name, nil)
+	if sum == nil {
+		return ""
+	}
+	return Digest{name, sum}.String()
+}
+
+// Digest returns the hash identified by name as an OCI style digest string,
+// "<name>:<hex>", e.g. "sha256:1963f25b...", as used by container registries
+// and github.com/opencontainers/go-digest. It panics and returns the empty
+// string in the same cases as HexHash.
+func (h *HashWriter) Digest(name string) string {
+	sum := h.Hash(name, nil)
+	if sum == nil {
+		return ""
+	}
+	return Digest{name, sum}.String()
+}
+
+// NewVerifierReader returns a VerifyingReader that reads from r and checks the
+// data against ociDigest, an OCI style digest string such as
+// "sha256:1963f25b...". See NewVerifyingReader.
+func NewVerifierReader(r io.Reader, ociDigest string) (*VerifyingReader, error) {
+	d, err := ParseDigest(ociDigest)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(r, map[string][]byte{d.Algorithm: d.Sum})
+}
+
+// Verified reports whether the wrapped io.Reader has been read to io.EOF and
+// every digest matched. It matches the Verified method of go-digest's
+// Verifier interface.
+func (v *VerifyingReader) Verified() bool {
+	return v.done == io.EOF
+}
+
+// DigestVerifier is an io.Writer that checks the data written to it against an
+// expected digest. It implements the Verifier interface of
+// github.com/opencontainers/go-digest.
+type DigestVerifier struct {
+	h        *HashWriter
+	expected Digest
+}
+
+// NewDigestVerifier returns a DigestVerifier for ociDigest, an OCI style digest
+// string such as "sha256:1963f25b...". An error is returned if ociDigest can't
+// be parsed or uses an algorithm not known to this package.
+func NewDigestVerifier(ociDigest string) (*DigestVerifier, error) {
+	d, err := ParseDigest(ociDigest)
+	if err != nil {
+		return nil, err
+	}
+	h, err := newHash(d.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &DigestVerifier{
+		h:        NewHashWriter(io.Discard, map[string]hash.Hash{d.Algorithm: h}),
+		expected: d,
+	}, nil
+}
+
+// Write hashes p. It never returns an error.
+func (v *DigestVerifier) Write(p []byte) (int, error) {
+	return v.h.Write(p)
+}
+
+// Verified reports whether the data written so far matches the expected digest.
+func (v *DigestVerifier) Verified() bool {
+	return subtle.ConstantTimeCompare(v.h.Hash(v.expected.Algorithm, nil), v.expected.Sum) == 1
+}
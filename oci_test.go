@@ -0,0 +1,74 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestOCIDigest(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	want := "sha256:" + dataFileSHA256
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write(contents)
+	if got := hw.Digest(SHA256); got != want {
+		t.Errorf("HashWriter.Digest(sha256) got: %q, wanted %q", got, want)
+	}
+
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+	defer f.Close()
+	vr, err := NewVerifierReader(f, want)
+	if err != nil {
+		t.Fatalf("NewVerifierReader(%q): %v", want, err)
+	}
+	if vr.Verified() {
+		t.Errorf("VerifyingReader.Verified() before reading got: true, wanted false")
+	}
+	if _, err := io.Copy(ioutil.Discard, vr); err != nil {
+		t.Errorf("io.Copy from VerifyingReader: %v", err)
+	}
+	if !vr.Verified() {
+		t.Errorf("VerifyingReader.Verified() got: false, wanted true")
+	}
+	if got := vr.Digest(SHA256); got != want {
+		t.Errorf("VerifyingReader.Digest(sha256) got: %q, wanted %q", got, want)
+	}
+
+	vr, err = NewVerifierReader(bytes.NewReader(contents[1:]), want)
+	if err != nil {
+		t.Fatalf("NewVerifierReader(%q): %v", want, err)
+	}
+	if _, err := io.Copy(ioutil.Discard, vr); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("io.Copy from VerifyingReader with truncated data got error: %v, wanted ErrDigestMismatch", err)
+	}
+	if vr.Verified() {
+		t.Errorf("VerifyingReader.Verified() after a mismatch got: true, wanted false")
+	}
+
+	v, err := NewDigestVerifier(want)
+	if err != nil {
+		t.Fatalf("NewDigestVerifier(%q): %v", want, err)
+	}
+	v.Write(contents[:10])
+	if v.Verified() {
+		t.Errorf("DigestVerifier.Verified() after a partial write got: true, wanted false")
+	}
+	v.Write(contents[10:])
+	if !v.Verified() {
+		t.Errorf("DigestVerifier.Verified() got: false, wanted true")
+	}
+
+	if _, err := NewDigestVerifier("whirlpool:00"); err == nil {
+		t.Errorf("NewDigestVerifier(whirlpool:00) got: nil error, wanted an error")
+	}
+}
@@ -0,0 +1,226 @@
+package hashio
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+)
+
+// An Option configures a HashReader or HashWriter created by NewReader or
+// NewWriter.
+type Option func(*config)
+
+// config holds the settings accumulated from a list of Options.
+type config struct {
+	hashers   map[string]hash.Hash
+	bufSize   int
+	chunk     int
+	strict    bool
+	parallel  bool
+	pipelined bool
+
+	stops  []stop
+	record int64
+	salts  []saltConfig
+
+	truncations map[string]int
+	enc         Encoder
+
+	algs map[string]Algorithm // algorithms of the hashes, if known
+	fips bool
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{hashers: make(map[string]hash.Hash), algs: make(map[string]Algorithm)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.checkFIPS()
+	c.applySalts()
+	c.applyTruncations()
+	return c
+}
+
+// add adds h, a hash of the algorithm alg, or of an unknown one if alg is
+// empty, identified by name.
+func (c *config) add(name string, alg Algorithm, h hash.Hash) {
+	c.hashers[name] = h
+	if alg == "" {
+		delete(c.algs, name)
+	} else {
+		c.algs[name] = alg
+	}
+}
+
+// WithHasher adds h to the set of hashes, identified by name. If name was
+// already added by an earlier option it is replaced. name needn't be the name
+// of the algorithm of h, so several hashes of one algorithm, such as HMACs
+// with different keys, can be told apart.
+//
+// h is used as is, so an Option returned by WithHasher must not be passed to
+// more than one constructor. The algorithm specific options such as WithSHA256
+// allocate a new hash.Hash every time they are applied and don't have this
+// restriction.
+func WithHasher(name string, h hash.Hash) Option {
+	return func(c *config) {
+		c.add(name, "", h)
+	}
+}
+
+// WithNamedHash adds a fresh hash of the registered algorithm alg, identified
+// by name. It is how several independent instances of one algorithm are
+// attached to a single wrapper, since names are identifiers chosen by the
+// caller rather than algorithms:
+//
+//	r := hashio.NewReader(f, hashio.WithNamedHash("part1", hashio.SHA256), hashio.WithNamedHash("part2", hashio.SHA256))
+//
+// NewReader and NewWriter panic if alg isn't registered.
+func WithNamedHash(name string, alg Algorithm) Option {
+	return func(c *config) {
+		f, ok := lookupFactory(alg)
+		if !ok {
+			panic(fmt.Sprintf("hashio: unknown hash algorithm %q for %q", alg, name))
+		}
+		c.add(name, alg, f())
+	}
+}
+
+// WithSHA256 adds a SHA-256 hash named "sha256".
+func WithSHA256() Option {
+	return func(c *config) {
+		c.add(SHA256, SHA256, sha256.New())
+	}
+}
+
+// WithSHA1 adds a SHA-1 hash named "sha1".
+func WithSHA1() Option {
+	return func(c *config) {
+		c.add(SHA1, SHA1, sha1.New())
+	}
+}
+
+// WithSHA384 adds a SHA-384 hash named "sha384".
+func WithSHA384() Option {
+	return func(c *config) {
+		c.add(SHA384, SHA384, sha512.New384())
+	}
+}
+
+// WithSHA512 adds a SHA-512 hash named "sha512".
+func WithSHA512() Option {
+	return func(c *config) {
+		c.add(SHA512, SHA512, sha512.New())
+	}
+}
+
+// WithSHA512_256 adds a SHA-512/256 hash named "sha512-256".
+func WithSHA512_256() Option {
+	return func(c *config) {
+		c.add(SHA512_256, SHA512_256, sha512.New512_256())
+	}
+}
+
+// WithMD5 adds an MD5 hash named "md5".
+func WithMD5() Option {
+	return func(c *config) {
+		c.add(MD5, MD5, md5.New())
+	}
+}
+
+// WithBufferSize makes a HashReader read from its underlying io.Reader through a
+// buffer of n bytes, which avoids many small reads from the source when the
+// caller reads in small pieces. Values of n less than or equal to zero disable
+// buffering, which is the default.
+//
+// For a HashWriter it sets the size of the buffer ReadFrom copies through,
+// which otherwise defaults to 32 KiB like io.Copy.
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		c.bufSize = n
+	}
+}
+
+// WithChunkSize sets the size of the chunks a HashReader or HashWriter copies
+// through on its own fast paths: HashReader.WriteTo when the wrapped reader
+// has no WriteTo method, and HashWriter.ReadFrom when the source has none.
+// Larger chunks (for example 4 MiB) mean fewer, larger reads, which suits
+// large-object workloads on fast storage or network mounts; smaller ones
+// (for example 64 KiB) suit spinning disks.
+//
+// For a HashWriter it takes precedence over WithBufferSize. Values of n less
+// than or equal to zero use the size set by SetBufferSize, which also governs
+// the package-level helpers such as CopyAndHash and HashFile.
+func WithChunkSize(n int) Option {
+	return func(c *config) {
+		c.chunk = n
+	}
+}
+
+// WithStrictFinalize makes requesting any digest from a HashReader or
+// HashWriter (through Hash, LookupHash, Sums, Verify and the other accessors
+// built on them) finalize it: every later Read or Write fails with
+// ErrFinalized until the wrapper is Reset. This catches code that mistakes a
+// digest taken mid-stream for the digest of the whole stream. Names, Err,
+// BytesRead, BytesWritten and String don't finalize.
+func WithStrictFinalize() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// NewReader returns a HashReader that reads from r and computes the hashes
+// selected by opts.
+func NewReader(r io.Reader, opts ...Option) *HashReader {
+	c := newConfig(opts)
+
+	var br *bufio.Reader
+	if c.bufSize > 0 {
+		br = bufio.NewReaderSize(r, c.bufSize)
+		r = br
+	}
+
+	h := NewHashReader(r, c.hashers)
+	h.br = br
+	h.chunk = c.chunk
+	h.stops = c.stops
+	if c.record > 0 {
+		h.stops = append(slices.Clip(h.stops), recordStop(c.record, &h.offsets))
+	}
+	h.strict = c.strict
+	h.enc = c.enc
+	if c.parallel {
+		h.hw = newParallelHashWriter(c.hashers)
+	}
+	if c.pipelined {
+		h.hashers, h.hw = pipelined(c.hashers, h.hw)
+	}
+	return h
+}
+
+// NewWriter returns a HashWriter that writes to w and computes the hashes
+// selected by opts.
+func NewWriter(w io.Writer, opts ...Option) *HashWriter {
+	c := newConfig(opts)
+	h := NewHashWriter(w, c.hashers)
+	h.strict = c.strict
+	h.enc = c.enc
+	h.bufSize = c.bufSize
+	h.chunk = c.chunk
+	h.stops = c.stops
+	if c.record > 0 {
+		h.stops = append(slices.Clip(h.stops), recordStop(c.record, &h.offsets))
+	}
+	if c.parallel {
+		h.hw = newParallelHashWriter(c.hashers)
+	}
+	if c.pipelined {
+		h.hashers, h.hw = pipelined(c.hashers, h.hw)
+	}
+	return h
+}
@@ -0,0 +1,125 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ExampleNewReader() {
+	reader := NewReader(strings.NewReader("hello I am happy"), WithSHA256(), WithMD5())
+	if _, err := io.ReadAll(reader); err != nil {
+		// handle error
+	}
+
+	fmt.Println(reader.HexHash("sha256"))
+
+	// Output: 1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a
+}
+
+func TestNewReaderAndWriter(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	want := map[string]string{
+		"md5":    dataFileMD5,
+		"sha1":   dataFileSHA1,
+		"sha256": dataFileSHA256,
+		"custom": dataFileSHA256,
+	}
+
+	opts := []Option{WithSHA256(), WithSHA1(), WithMD5(), WithBufferSize(7)}
+	hr := NewReader(bytes.NewReader(contents), append(opts, WithHasher("custom", sha256.New()))...)
+	got, err := ioutil.ReadAll(hr)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Errorf("NewReader with WithBufferSize returned different data than it was given")
+	}
+	if sums := hr.HexSums(); !reflect.DeepEqual(sums, want) {
+		t.Errorf("HashReader.HexSums() got: %v, wanted %v", sums, want)
+	}
+
+	// The same options must not share hash state between wrappers.
+	hw := NewWriter(ioutil.Discard, append(opts, WithHasher("custom", sha256.New()))...)
+	if _, err := hw.Write(contents); err != nil {
+		t.Fatalf("HashWriter.Write: %v", err)
+	}
+	if sums := hw.HexSums(); !reflect.DeepEqual(sums, want) {
+		t.Errorf("HashWriter.HexSums() got: %v, wanted %v", sums, want)
+	}
+}
+
+func TestStrictFinalize(t *testing.T) {
+	hr := NewReader(strings.NewReader("hello I am happy"), WithSHA256(), WithStrictFinalize())
+	buf := make([]byte, 5)
+	if _, err := hr.Read(buf); err != nil {
+		t.Fatalf("HashReader.Read: %v", err)
+	}
+	if names := hr.Names(); len(names) != 1 {
+		t.Errorf("HashReader.Names() got: %q, wanted one name", names)
+	}
+	if _, err := hr.Read(buf); err != nil {
+		t.Errorf("HashReader.Read after Names got error: %v, wanted nil", err)
+	}
+
+	hr.HexHash(SHA256)
+	if _, err := hr.Read(buf); err != ErrFinalized {
+		t.Errorf("HashReader.Read after HexHash got error: %v, wanted ErrFinalized", err)
+	}
+	if err := hr.Err(); err != nil {
+		t.Errorf("HashReader.Err() got: %v, wanted nil", err)
+	}
+
+	hr.Reset(strings.NewReader("again"))
+	if _, err := hr.Read(buf); err != nil {
+		t.Errorf("HashReader.Read after Reset got error: %v, wanted nil", err)
+	}
+
+	hw := NewWriter(ioutil.Discard, WithSHA256(), WithStrictFinalize())
+	hw.Write([]byte("hello"))
+	hw.Sums()
+	if _, err := hw.Write([]byte(" I am happy")); err != ErrFinalized {
+		t.Errorf("HashWriter.Write after Sums got error: %v, wanted ErrFinalized", err)
+	}
+
+	// Without the option, mid-stream digests don't finalize.
+	hw = NewWriter(ioutil.Discard, WithSHA256())
+	hw.Write([]byte("hello"))
+	hw.Sums()
+	if _, err := hw.Write([]byte(" I am happy")); err != nil {
+		t.Errorf("HashWriter.Write after Sums without WithStrictFinalize got error: %v, wanted nil", err)
+	}
+}
+
+func TestSHA512Options(t *testing.T) {
+	// FIPS 180-4 example vectors for "abc".
+	want := map[string]string{
+		SHA384:     "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+		SHA512:     "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		SHA512_256: "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23",
+	}
+	w := NewWriter(nil, WithSHA384(), WithSHA512(), WithSHA512_256())
+	w.WriteString("abc")
+	if got := w.HexSums(); !reflect.DeepEqual(got, want) {
+		t.Errorf("HashWriter.HexSums() got: %v, wanted %v", got, want)
+	}
+
+	// They can also be requested by name.
+	sums, err := HashString("abc", SHA384, SHA512, SHA512_256)
+	if err != nil {
+		t.Fatalf("HashString(): %v", err)
+	}
+	for name, sum := range want {
+		if got := fmt.Sprintf("%x", sums[name]); got != sum {
+			t.Errorf("HashString(%s) got: %q, wanted %q", name, got, sum)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hashio
+
+import (
+	"hash"
+	"sync"
+)
+
+// minParallelChunk is the smallest chunk worth spreading across goroutines.
+// Below it the cost of starting them exceeds the hashing time saved.
+const minParallelChunk = 16 << 10
+
+// WithParallelHashing makes a HashReader or HashWriter hash each chunk with
+// all of its hashes concurrently, one goroutine per hash, instead of one after
+// another. With several expensive hashes attached (for example SHA-256, SHA-1
+// and MD5) this lets them run on separate cores so hashing stops being the
+// bottleneck on fast storage.
+//
+// Every chunk is fully hashed before the Read or Write that produced it
+// returns, so no data is copied or retained and the digests are always up to
+// date. Chunks smaller than 16 KiB and wrappers with a single hash are hashed
+// serially, as the goroutine overhead would outweigh the gain.
+func WithParallelHashing() Option {
+	return func(c *config) {
+		c.parallel = true
+	}
+}
+
+// parallelHashWriter is an io.Writer that writes every chunk to all of its
+// hashes concurrently and waits for them to finish.
+type parallelHashWriter []hash.Hash
+
+func newParallelHashWriter(hashers map[string]hash.Hash) parallelHashWriter {
+	w := make(parallelHashWriter, 0, len(hashers))
+	for _, h := range hashers {
+		w = append(w, h)
+	}
+	return w
+}
+
+func (w parallelHashWriter) Write(p []byte) (int, error) {
+	if len(w) < 2 || len(p) < minParallelChunk {
+		for _, h := range w {
+			h.Write(p)
+		}
+		return len(p), nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(w) - 1)
+	for _, h := range w[1:] {
+		go func(h hash.Hash) {
+			defer wg.Done()
+			h.Write(p)
+		}(h)
+	}
+	// Use the calling goroutine for one of the hashes.
+	w[0].Write(p)
+	wg.Wait()
+	return len(p), nil
+}
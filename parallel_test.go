@@ -0,0 +1,62 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWithParallelHashing(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	// Make sure some chunks are large enough to be hashed in parallel.
+	big := bytes.Repeat(contents, 4*minParallelChunk/len(contents)+1)
+
+	for _, data := range [][]byte{contents, big} {
+		ref := NewHashReader(bytes.NewReader(data), StdCryptoHashes())
+		if _, err := ioutil.ReadAll(ref); err != nil {
+			t.Fatalf("ioutil.ReadAll(): %v", err)
+		}
+		want := ref.HexSums()
+
+		hr := NewReader(bytes.NewReader(data), WithSHA256(), WithSHA1(), WithMD5(), WithParallelHashing())
+		var buf bytes.Buffer
+		hw := NewWriter(&buf, WithSHA256(), WithSHA1(), WithMD5(), WithParallelHashing())
+		if _, err := io.Copy(hw, hr); err != nil {
+			t.Fatalf("io.Copy(): %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("HashWriter wrote %d bytes, wanted %d", buf.Len(), len(data))
+		}
+		for name, w := range want {
+			if got := hr.HexHash(name); got != w {
+				t.Errorf("parallel HashReader.HexHash(%s) of %d bytes got: %q, wanted %q", name, len(data), got, w)
+			}
+			if got := hw.HexHash(name); got != w {
+				t.Errorf("parallel HashWriter.HexHash(%s) of %d bytes got: %q, wanted %q", name, len(data), got, w)
+			}
+		}
+	}
+}
+
+func BenchmarkParallelHashing(b *testing.B) {
+	data := bytes.Repeat([]byte("x"), 1<<20)
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"serial", []Option{WithSHA256(), WithSHA1(), WithMD5()}},
+		{"parallel", []Option{WithSHA256(), WithSHA1(), WithMD5(), WithParallelHashing()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			hw := NewWriter(io.Discard, bc.opts...)
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				hw.Write(data)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package hashio
+
+// PauseHashing stops passing data read through h to its hashes until
+// ResumeHashing is called. Data read while paused is still returned to the
+// caller and counted by BytesRead, but is left out of every digest. This makes
+// it possible to exclude a byte range, such as a mutable header in a container
+// format, without unwrapping the reader.
+func (h *HashReader) PauseHashing() {
+	h.paused = true
+}
+
+// ResumeHashing undoes PauseHashing. Reset also resumes hashing.
+func (h *HashReader) ResumeHashing() {
+	h.paused = false
+}
+
+// HashingPaused reports whether hashing is paused by PauseHashing.
+func (h *HashReader) HashingPaused() bool {
+	return h.paused
+}
+
+// PauseHashing stops passing data written through h to its hashes until
+// ResumeHashing is called. Data written while paused is still passed to the
+// wrapped io.Writer and counted by BytesWritten, but is left out of every
+// digest.
+func (h *HashWriter) PauseHashing() {
+	h.paused = true
+}
+
+// ResumeHashing undoes PauseHashing. Reset also resumes hashing.
+func (h *HashWriter) ResumeHashing() {
+	h.paused = false
+}
+
+// HashingPaused reports whether hashing is paused by PauseHashing.
+func (h *HashWriter) HashingPaused() bool {
+	return h.paused
+}
@@ -0,0 +1,52 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPauseHashing(t *testing.T) {
+	want := sha256.Sum256([]byte("headerbody"))
+
+	hr := NewReader(strings.NewReader("header[mutable]body"), WithSHA256())
+	var out bytes.Buffer
+	hw := NewWriter(&out, WithSHA256())
+	for _, part := range []struct {
+		n      int64
+		paused bool
+	}{{6, false}, {9, true}, {4, false}} {
+		if part.paused {
+			hr.PauseHashing()
+			hw.PauseHashing()
+		}
+		if _, err := io.CopyN(hw, hr, part.n); err != nil {
+			t.Fatalf("io.CopyN(%d): %v", part.n, err)
+		}
+		hr.ResumeHashing()
+		hw.ResumeHashing()
+	}
+
+	if got := out.String(); got != "header[mutable]body" {
+		t.Errorf("HashWriter passed through: %q, wanted every byte", got)
+	}
+	if hr.BytesRead() != 19 || hw.BytesWritten() != 19 {
+		t.Errorf("BytesRead() = %d, BytesWritten() = %d, wanted 19", hr.BytesRead(), hw.BytesWritten())
+	}
+	wantHex := hex.EncodeToString(want[:])
+	if got := hr.HexHash(SHA256); got != wantHex {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, wantHex)
+	}
+	if got := hw.HexHash(SHA256); got != wantHex {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, wantHex)
+	}
+
+	hw.PauseHashing()
+	hw.Reset(nil)
+	if hw.HashingPaused() {
+		t.Errorf("HashWriter.HashingPaused() after Reset got: true, wanted false")
+	}
+}
@@ -0,0 +1,97 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+)
+
+// WithPipelining makes a HashReader or HashWriter hash in the background, so
+// that hashing one chunk overlaps with fetching (or writing) the next one. For
+// network sources, where Read mostly waits on the socket, this can nearly
+// double throughput compared to hashing in line.
+//
+// Each chunk is copied into one of two buffers (double buffering) and hashed by
+// a goroutine while Read or Write returns. At most two chunks are in flight;
+// a third Read or Write waits for the oldest to be hashed. Requesting a digest
+// waits for every outstanding chunk, so digests always describe all data read
+// or written so far.
+func WithPipelining() Option {
+	return func(c *config) {
+		c.pipelined = true
+	}
+}
+
+// pipeline is an io.Writer that copies every chunk and passes it to w on a
+// separate goroutine, in order.
+type pipeline struct {
+	w    io.Writer
+	bufs [2][]byte
+	done [2]chan struct{} // closed once the chunk in bufs[i] has been hashed
+	next int              // index of the buffer to use next
+	last chan struct{}    // done channel of the most recent chunk
+}
+
+func (p *pipeline) Write(b []byte) (int, error) {
+	i := p.next
+	p.next = 1 - i
+	if p.done[i] != nil {
+		<-p.done[i]
+	}
+	if cap(p.bufs[i]) < len(b) {
+		p.bufs[i] = make([]byte, len(b))
+	}
+	buf := p.bufs[i][:len(b)]
+	copy(buf, b)
+
+	prev, done := p.last, make(chan struct{})
+	p.done[i], p.last = done, done
+	go func() {
+		if prev != nil {
+			<-prev
+		}
+		p.w.Write(buf)
+		close(done)
+	}()
+	return len(b), nil
+}
+
+// wait blocks until every chunk passed to Write has been hashed.
+func (p *pipeline) wait() {
+	if p.last != nil {
+		<-p.last
+	}
+}
+
+// pipelinedHash is a hash.Hash fed by a pipeline. Sum and Reset wait for the
+// pipeline to drain so they never race with the background goroutine.
+type pipelinedHash struct {
+	hash.Hash
+	p *pipeline
+}
+
+func (h pipelinedHash) Sum(b []byte) []byte {
+	h.p.wait()
+	return h.Hash.Sum(b)
+}
+
+func (h pipelinedHash) Reset() {
+	h.p.wait()
+	h.Hash.Reset()
+}
+
+// Write is only used if something other than the pipeline writes to the hash.
+func (h pipelinedHash) Write(b []byte) (int, error) {
+	h.p.wait()
+	return h.Hash.Write(b)
+}
+
+// pipelined returns a pipeline feeding hw and a copy of hashers whose hashes
+// wait on it.
+func pipelined(hashers map[string]hash.Hash, hw io.Writer) (map[string]hash.Hash, *pipeline) {
+	p := &pipeline{w: hw}
+	m := make(map[string]hash.Hash, len(hashers))
+	for name, h := range hashers {
+		m[name] = pipelinedHash{h, p}
+	}
+	return m, p
+}
@@ -0,0 +1,50 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWithPipelining(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	want := map[string]string{MD5: dataFileMD5, SHA1: dataFileSHA1, SHA256: dataFileSHA256}
+
+	for _, opts := range [][]Option{
+		{WithSHA256(), WithSHA1(), WithMD5(), WithPipelining()},
+		{WithSHA256(), WithSHA1(), WithMD5(), WithPipelining(), WithParallelHashing()},
+	} {
+		// onlyReader forces many small Reads, so chunks are really in flight.
+		hr := NewReader(&onlyReader{bytes.NewReader(contents)}, opts...)
+		var buf bytes.Buffer
+		hw := NewWriter(&buf, opts...)
+		p := make([]byte, 13)
+		if _, err := io.CopyBuffer(struct{ io.Writer }{hw}, struct{ io.Reader }{hr}, p); err != nil {
+			t.Fatalf("io.CopyBuffer(): %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), contents) {
+			t.Errorf("pipelined HashWriter wrote %d bytes, wanted %d", buf.Len(), len(contents))
+		}
+		for name, w := range want {
+			if got := hr.HexHash(name); got != w {
+				t.Errorf("pipelined HashReader.HexHash(%s) got: %q, wanted %q", name, got, w)
+			}
+			if got := hw.HexHash(name); got != w {
+				t.Errorf("pipelined HashWriter.HexHash(%s) got: %q, wanted %q", name, got, w)
+			}
+		}
+
+		// Reset must not race with chunks still being hashed.
+		hr.Reset(bytes.NewReader(contents))
+		if _, err := ioutil.ReadAll(hr); err != nil {
+			t.Fatalf("ioutil.ReadAll() after Reset: %v", err)
+		}
+		if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+			t.Errorf("pipelined HashReader.HexHash(sha256) after Reset got: %q, wanted %q", got, dataFileSHA256)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package hashio
+
+import (
+	"io"
+	"sync"
+)
+
+// Pool hands out HashReaders and HashWriters computing a fixed set of hashes
+// and takes them back for reuse, so services hashing every request don't
+// allocate new hash state each time. A Pool is safe for concurrent use.
+type Pool struct {
+	names   []string
+	readers sync.Pool
+	writers sync.Pool
+}
+
+// NewPool returns a Pool of wrappers computing the algorithms in names. If no
+// names are given, the hashes of StdCryptoHashes are computed. An error is
+// returned if a name is not a known algorithm.
+func NewPool(names ...string) (*Pool, error) {
+	if _, err := hashersByName(names); err != nil {
+		return nil, err
+	}
+	p := &Pool{names: append([]string(nil), names...)}
+	p.readers.New = func() any {
+		hashers, _ := hashersByName(p.names)
+		return NewHashReader(nil, hashers)
+	}
+	p.writers.New = func() any {
+		hashers, _ := hashersByName(p.names)
+		return NewHashWriter(nil, hashers)
+	}
+	return p, nil
+}
+
+// GetReader returns a HashReader reading from r, either reused from the pool
+// or newly allocated. It should be returned with PutReader once its digests
+// are no longer needed.
+func (p *Pool) GetReader(r io.Reader) *HashReader {
+	h := p.readers.Get().(*HashReader)
+	h.Reset(r)
+	return h
+}
+
+// PutReader returns h, which must have come from GetReader, to the pool. h
+// must not be used afterwards.
+func (p *Pool) PutReader(h *HashReader) {
+	// Drop the reference to the wrapped reader so it can be collected.
+	h.Reset(nil)
+	p.readers.Put(h)
+}
+
+// GetWriter returns a HashWriter writing to w, either reused from the pool or
+// newly allocated. w may be nil, as for NewHashWriter. It should be returned
+// with PutWriter once its digests are no longer needed.
+func (p *Pool) GetWriter(w io.Writer) *HashWriter {
+	h := p.writers.Get().(*HashWriter)
+	h.Reset(w)
+	return h
+}
+
+// PutWriter returns h, which must have come from GetWriter, to the pool. h
+// must not be used afterwards.
+func (p *Pool) PutWriter(h *HashWriter) {
+	h.Reset(nil)
+	p.writers.Put(h)
+}
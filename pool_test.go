@@ -0,0 +1,62 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	p, err := NewPool(SHA256, MD5)
+	if err != nil {
+		t.Fatalf("NewPool(sha256, md5): %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		hr := p.GetReader(bytes.NewReader(contents))
+		if _, err := ioutil.ReadAll(hr); err != nil {
+			t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+		}
+		if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+			t.Errorf("pooled HashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+		}
+		p.PutReader(hr)
+
+		var buf bytes.Buffer
+		hw := p.GetWriter(&buf)
+		if _, err := io.Copy(hw, bytes.NewReader(contents)); err != nil {
+			t.Fatalf("io.Copy(): %v", err)
+		}
+		if got := hw.HexHash(MD5); got != dataFileMD5 {
+			t.Errorf("pooled HashWriter.HexHash(md5) got: %q, wanted %q", got, dataFileMD5)
+		}
+		if got := hw.Names(); len(got) != 2 {
+			t.Errorf("pooled HashWriter.Names() got: %q, wanted [md5 sha256]", got)
+		}
+		p.PutWriter(hw)
+	}
+
+	if _, err := NewPool("nope"); err == nil {
+		t.Errorf("NewPool(\"nope\") got no error, wanted *UnknownHashError")
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	p, err := NewPool(SHA256, MD5)
+	if err != nil {
+		b.Fatalf("NewPool(): %v", err)
+	}
+	data := []byte("request body")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hw := p.GetWriter(nil)
+		hw.Write(data)
+		hw.Hash(SHA256, nil)
+		p.PutWriter(hw)
+	}
+}
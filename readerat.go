@@ -0,0 +1,95 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+	"sort"
+	"sync"
+)
+
+// RangeDigest is the digest of the bytes read by one call to
+// HashReaderAt.ReadAt.
+type RangeDigest struct {
+	Offset int64
+	Length int64
+	Sum    []byte
+}
+
+// HashReaderAt implements io.ReaderAt by wrapping a provided io.ReaderAt. It
+// records a digest for every range read, which is useful for verifying ranged
+// or parallel reads of a large object against known per-range digests.
+//
+// Like any io.ReaderAt, it is safe for concurrent use if the wrapped
+// io.ReaderAt is.
+type HashReaderAt struct {
+	ra      io.ReaderAt
+	newHash func() hash.Hash
+
+	mu     sync.Mutex
+	ranges []RangeDigest
+}
+
+// NewHashReaderAt returns a HashReaderAt that reads from ra. newHash is called
+// once per ReadAt to hash the bytes it returns.
+func NewHashReaderAt(ra io.ReaderAt, newHash func() hash.Hash) *HashReaderAt {
+	return &HashReaderAt{ra: ra, newHash: newHash}
+}
+
+// ReadAt reads from the wrapped io.ReaderAt and records the digest of the n
+// bytes read, if n is greater than zero, even if an error is returned.
+func (h *HashReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := h.ra.ReadAt(p, off)
+	if n > 0 {
+		hh := h.newHash()
+		hh.Write(p[:n])
+		rd := RangeDigest{Offset: off, Length: int64(n), Sum: hh.Sum(nil)}
+
+		h.mu.Lock()
+		h.ranges = append(h.ranges, rd)
+		h.mu.Unlock()
+	}
+	return n, err
+}
+
+// RangeDigests returns the digest of every range read so far, ordered by offset
+// and then length. Ranges read more than once appear more than once.
+func (h *HashReaderAt) RangeDigests() []RangeDigest {
+	h.mu.Lock()
+	ranges := append([]RangeDigest(nil), h.ranges...)
+	h.mu.Unlock()
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Offset != ranges[j].Offset {
+			return ranges[i].Offset < ranges[j].Offset
+		}
+		return ranges[i].Length < ranges[j].Length
+	})
+	return ranges
+}
+
+// Reset forgets every recorded range and makes h read from ra.
+func (h *HashReaderAt) Reset(ra io.ReaderAt) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ra = ra
+	h.ranges = nil
+}
+
+// HashSection hashes the n bytes of ra starting at offset off with every
+// hash.Hash in hashers and returns the digests keyed by name. It is a shorthand
+// for hashing an io.SectionReader, for example to verify one record inside a
+// large container file.
+//
+// If ra ends before off+n, io.ErrUnexpectedEOF is returned, since the digests
+// would not cover the requested section. Any other error from ra is returned
+// as is. No digests are returned with an error.
+func HashSection(ra io.ReaderAt, off, n int64, hashers map[string]hash.Hash) (map[string][]byte, error) {
+	read, sums, err := CopyAndHash(nil, io.NewSectionReader(ra, off, n), hashers)
+	if err != nil {
+		return nil, err
+	}
+	if read != n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return sums, nil
+}
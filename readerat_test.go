@@ -0,0 +1,73 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestHashReaderAt(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr := NewHashReaderAt(bytes.NewReader(contents), sha256.New)
+
+	const size = 32
+	var wg sync.WaitGroup
+	for off := 0; off < len(contents); off += size {
+		wg.Add(1)
+		go func(off int) {
+			defer wg.Done()
+			if _, err := hr.ReadAt(make([]byte, size), int64(off)); err != nil && err != io.EOF {
+				t.Errorf("HashReaderAt.ReadAt(%d): %v", off, err)
+			}
+		}(off)
+	}
+	wg.Wait()
+
+	ranges := hr.RangeDigests()
+	if want := (len(contents) + size - 1) / size; len(ranges) != want {
+		t.Fatalf("HashReaderAt.RangeDigests() returned %d ranges, wanted %d", len(ranges), want)
+	}
+	var next int64
+	for _, r := range ranges {
+		if r.Offset != next {
+			t.Errorf("range at offset %d, wanted offset %d", r.Offset, next)
+		}
+		want := sha256.Sum256(contents[r.Offset : r.Offset+r.Length])
+		if !bytes.Equal(r.Sum, want[:]) {
+			t.Errorf("range %d+%d digest got: %x, wanted %x", r.Offset, r.Length, r.Sum, want)
+		}
+		next = r.Offset + r.Length
+	}
+	if next != int64(len(contents)) {
+		t.Errorf("ranges end at %d, wanted %d", next, len(contents))
+	}
+}
+
+func TestHashSection(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	ra := bytes.NewReader(contents)
+
+	sums, err := HashSection(ra, 10, 50, map[string]hash.Hash{SHA256: sha256.New()})
+	if err != nil {
+		t.Fatalf("HashSection(10, 50): %v", err)
+	}
+	if got, want := sums[SHA256], sha256.Sum256(contents[10:60]); !bytes.Equal(got, want[:]) {
+		t.Errorf("HashSection(10, 50) got: %x, wanted %x", got, want)
+	}
+
+	size := int64(len(contents))
+	if _, err := HashSection(ra, size-10, 20, map[string]hash.Hash{SHA256: sha256.New()}); err != io.ErrUnexpectedEOF {
+		t.Errorf("HashSection past EOF got error: %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+}
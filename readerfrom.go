@@ -0,0 +1,39 @@
+package hashio
+
+import "io"
+
+// defaultBufSize is the size of the buffers used to copy data when no other
+// size was configured. It matches io.Copy.
+const defaultBufSize = 32 << 10
+
+// ReadFrom implements io.ReaderFrom so that io.Copy to a HashWriter copies
+// through a single internal buffer that feeds both the wrapped io.Writer and
+// the hashes, rather than going through io.Copy's generic path. The buffer is
+// pooled and its size can be set with WithChunkSize, WithBufferSize or
+// SetBufferSize. If r implements io.WriterTo, r.WriteTo is used instead and no
+// buffer is needed.
+//
+// Errors from the wrapped io.Writer are recorded as by Write. Errors from r
+// are returned but don't affect the digests, which describe the data written.
+func (h *HashWriter) ReadFrom(r io.Reader) (int64, error) {
+	if h.finalized {
+		return 0, ErrFinalized
+	}
+
+	// Hide h's own ReadFrom so nothing calls back into it.
+	w := struct{ io.Writer }{h}
+	if wt, ok := r.(io.WriterTo); ok {
+		return wt.WriteTo(w)
+	}
+
+	size := h.chunk
+	if size <= 0 {
+		size = h.bufSize
+	}
+	if size <= 0 {
+		size = copyBufSize(defaultBufSize)
+	}
+	buf := getBuffer(size)
+	defer putBuffer(buf)
+	return io.CopyBuffer(w, struct{ io.Reader }{r}, *buf)
+}
@@ -0,0 +1,57 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// readSizes records the size of every buffer Read is called with.
+type readSizes struct {
+	io.Reader
+	sizes []int
+}
+
+func (r *readSizes) Read(p []byte) (int, error) {
+	r.sizes = append(r.sizes, len(p))
+	return r.Reader.Read(p)
+}
+
+func TestHashWriterReadFrom(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	src := &readSizes{Reader: bytes.NewReader(contents)}
+	var buf bytes.Buffer
+	hw := NewWriter(&buf, WithSHA256(), WithBufferSize(16))
+	n, err := io.Copy(hw, src)
+	if err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	if n != int64(len(contents)) || hw.BytesWritten() != n || !bytes.Equal(buf.Bytes(), contents) {
+		t.Errorf("io.Copy copied %d bytes (BytesWritten %d), wanted %d", n, hw.BytesWritten(), len(contents))
+	}
+	for _, size := range src.sizes {
+		if size != 16 {
+			t.Errorf("HashWriter.ReadFrom read with a %d byte buffer, wanted 16", size)
+			break
+		}
+	}
+	if hash := hw.HexHash(SHA256); hash != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", hash, dataFileSHA256)
+	}
+
+	// Source errors don't poison the digests of what was written.
+	boom := errors.New("boom")
+	hw = NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	if _, err := hw.ReadFrom(&errReader{contents, boom}); err != boom {
+		t.Errorf("HashWriter.ReadFrom got error: %v, wanted %v", err, boom)
+	}
+	if hash := hw.HexHash(MD5); hash != dataFileMD5 {
+		t.Errorf("HashWriter.HexHash(md5) got: %q, wanted %q", hash, dataFileMD5)
+	}
+}
@@ -0,0 +1,69 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+)
+
+// HashReadWriter implements io.ReadWriter by wrapping a provided io.ReadWriter.
+// It keeps independent running hashes of the data read from and written to
+// that io.ReadWriter, which is useful for bidirectional streams such as a
+// net.Conn.
+type HashReadWriter struct {
+	r *HashReader
+	w *HashWriter
+}
+
+// NewHashReadWriter returns a HashReadWriter that reads from and writes to rw.
+// Data read is passed to the hashes in readHashers and data written is passed
+// to the hashes in writeHashers, with the same semantics as NewHashReader and
+// NewHashWriter respectively. The two maps must not share hash.Hash objects.
+//
+// The caller should not modify either map nor any of the hash.Hash objects they
+// contain.
+func NewHashReadWriter(rw io.ReadWriter, readHashers, writeHashers map[string]hash.Hash) *HashReadWriter {
+	return &HashReadWriter{
+		r: NewHashReader(rw, readHashers),
+		w: NewHashWriter(rw, writeHashers),
+	}
+}
+
+func (h *HashReadWriter) Read(p []byte) (int, error) {
+	return h.r.Read(p)
+}
+
+func (h *HashReadWriter) Write(p []byte) (int, error) {
+	return h.w.Write(p)
+}
+
+// ReadHash is like HashReader.Hash for the data read from h.
+func (h *HashReadWriter) ReadHash(name string, buf []byte) []byte {
+	return h.r.Hash(name, buf)
+}
+
+// ReadHexHash is like HashReader.HexHash for the data read from h.
+func (h *HashReadWriter) ReadHexHash(name string) string {
+	return h.r.HexHash(name)
+}
+
+// WriteHash is like HashWriter.Hash for the data written to h.
+func (h *HashReadWriter) WriteHash(name string, buf []byte) []byte {
+	return h.w.Hash(name, buf)
+}
+
+// WriteHexHash is like HashWriter.HexHash for the data written to h.
+func (h *HashReadWriter) WriteHexHash(name string) string {
+	return h.w.HexHash(name)
+}
+
+// Reader returns the HashReader for the read side of h, giving access to the
+// rest of the HashReader API. Reading from it is equivalent to reading from h.
+func (h *HashReadWriter) Reader() *HashReader {
+	return h.r
+}
+
+// Writer returns the HashWriter for the write side of h, giving access to the
+// rest of the HashWriter API. Writing to it is equivalent to writing to h.
+func (h *HashReadWriter) Writer() *HashWriter {
+	return h.w
+}
@@ -0,0 +1,47 @@
+package hashio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestHashReadWriter(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	rw := NewHashReadWriter(buf, StdCryptoHashes(), StdCryptoHashes())
+
+	if _, err := rw.Write(contents); err != nil {
+		t.Fatalf("HashReadWriter.Write: %v", err)
+	}
+	if _, err := rw.Write([]byte("trailer")); err != nil {
+		t.Fatalf("HashReadWriter.Write: %v", err)
+	}
+
+	// Only read back the original contents so the two sides differ.
+	got := make([]byte, len(contents))
+	if _, err := rw.Read(got); err != nil {
+		t.Fatalf("HashReadWriter.Read: %v", err)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Fatalf("HashReadWriter.Read returned different data than was written")
+	}
+
+	if hash := rw.ReadHexHash(SHA256); hash != dataFileSHA256 {
+		t.Errorf("HashReadWriter.ReadHexHash(sha256) got: %q, wanted %q", hash, dataFileSHA256)
+	}
+	if hash := rw.WriteHexHash(SHA256); hash == dataFileSHA256 {
+		t.Errorf("HashReadWriter.WriteHexHash(sha256) got: %q, wanted the digest to include the trailer", hash)
+	}
+
+	want := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	want.Write(contents)
+	want.Write([]byte("trailer"))
+	if got, want := rw.Writer().HexSums(), want.HexSums(); got[MD5] != want[MD5] {
+		t.Errorf("HashReadWriter.Writer().HexSums()[md5] got: %q, wanted %q", got[MD5], want[MD5])
+	}
+}
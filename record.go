@@ -0,0 +1,81 @@
+package hashio
+
+import (
+	"bytes"
+	"hash"
+	"slices"
+)
+
+// OffsetDigest is the digest of every hash of a HashReader or HashWriter at
+// some offset of its stream, as recorded by RecordEvery.
+type OffsetDigest struct {
+	// Offset is the number of bytes hashed.
+	Offset int64
+	// Sums holds the digest of the first Offset bytes keyed by hash name.
+	Sums map[string][]byte
+}
+
+// RecordEvery makes a HashReader or HashWriter record the digest of each of
+// its hashes every n bytes, as returned by Snapshot. The recorded digests are
+// returned by OffsetDigests and cleared by Reset.
+//
+// Comparing the digests recorded for two copies of a stream with
+// LocateMismatch narrows a corruption down to n bytes without keeping either
+// copy around. Each record costs one digest computation per hash, so n should
+// be large compared to the Read or Write sizes. RecordEvery does nothing if n
+// is not positive.
+func RecordEvery(n int64) Option {
+	return func(c *config) {
+		c.record = max(n, 0)
+	}
+}
+
+// recordStop returns a stop appending a snapshot of the hashes to *dst every
+// `every` bytes.
+func recordStop(every int64, dst *[]OffsetDigest) stop {
+	return stop{every, func(hashers map[string]hash.Hash, offset int64) {
+		*dst = append(*dst, OffsetDigest{Offset: offset, Sums: snapshot(hashers)})
+	}}
+}
+
+// OffsetDigests returns the digests recorded since h was created or last Reset
+// with RecordEvery, in increasing offset order.
+func (h *HashReader) OffsetDigests() []OffsetDigest {
+	return slices.Clone(h.offsets)
+}
+
+// OffsetDigests returns the digests recorded since h was created or last Reset
+// with RecordEvery, in increasing offset order.
+func (h *HashWriter) OffsetDigests() []OffsetDigest {
+	return slices.Clone(h.offsets)
+}
+
+// LocateMismatch compares two lists of digests recorded by RecordEvery, for
+// instance for the original and a copy of the same stream, and finds where
+// they first differ. Digests are compared at the offsets present in both
+// lists, for the hashes present in both.
+//
+// If some digests differ, the first difference between the streams lies in
+// the bytes [from, to): from is the last offset at which all digests match (0
+// if none does) and to the first at which one differs. Otherwise found is
+// false.
+func LocateMismatch(want, got []OffsetDigest) (from, to int64, found bool) {
+	for i, j := 0, 0; i < len(want) && j < len(got); {
+		switch w, g := want[i], got[j]; {
+		case w.Offset < g.Offset:
+			i++
+		case w.Offset > g.Offset:
+			j++
+		default:
+			for name, sum := range w.Sums {
+				if other, ok := g.Sums[name]; ok && !bytes.Equal(sum, other) {
+					return from, w.Offset, true
+				}
+			}
+			from = w.Offset
+			i++
+			j++
+		}
+	}
+	return 0, 0, false
+}
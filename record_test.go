@@ -0,0 +1,71 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRecordEvery(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hr := NewReader(&onlyReader{bytes.NewReader(contents)}, WithSHA256(), WithMD5(), RecordEvery(16))
+	buf := make([]byte, 25)
+	if _, err := io.CopyBuffer(io.Discard, struct{ io.Reader }{hr}, buf); err != nil {
+		t.Fatalf("io.CopyBuffer(): %v", err)
+	}
+	got := hr.OffsetDigests()
+	if want := len(contents) / 16; len(got) != want {
+		t.Fatalf("HashReader.OffsetDigests() got %d digests for %d bytes, wanted %d", len(got), len(contents), want)
+	}
+	for i, d := range got {
+		want, _ := HashBytes(contents[:d.Offset], SHA256, MD5)
+		if d.Offset != int64(16*(i+1)) || !bytes.Equal(d.Sums[SHA256], want[SHA256]) || !bytes.Equal(d.Sums[MD5], want[MD5]) {
+			t.Errorf("HashReader.OffsetDigests()[%d] got: {%d, %x}, wanted {%d, %x}", i, d.Offset, d.Sums[SHA256], 16*(i+1), want[SHA256])
+		}
+	}
+	if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+
+	hr.Reset(bytes.NewReader(nil))
+	if got := hr.OffsetDigests(); len(got) != 0 {
+		t.Errorf("HashReader.OffsetDigests() after Reset got %d digests, wanted none", len(got))
+	}
+}
+
+func TestLocateMismatch(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	corrupt := bytes.Clone(contents)
+	corrupt[37] ^= 1
+
+	record := func(data []byte, every int64, opts ...Option) []OffsetDigest {
+		// RecordEvery and WithCheckpoint stop at the same boundaries.
+		opts = append(opts, RecordEvery(every), WithCheckpoint(7, func(int64, []byte) {}))
+		w := NewWriter(nil, opts...)
+		w.Write(data)
+		return w.OffsetDigests()
+	}
+	want := record(contents, 10, WithSHA256())
+
+	if from, to, found := LocateMismatch(want, record(corrupt, 10, WithSHA256())); !found || from != 30 || to != 40 {
+		t.Errorf("LocateMismatch() got: (%d, %d, %t), wanted (30, 40, true)", from, to, found)
+	}
+	// Only common offsets and hashes are compared.
+	if from, to, found := LocateMismatch(want, record(corrupt, 20, WithSHA256(), WithMD5())); !found || from != 20 || to != 40 {
+		t.Errorf("LocateMismatch() at other offsets got: (%d, %d, %t), wanted (20, 40, true)", from, to, found)
+	}
+	if _, _, found := LocateMismatch(want, record(contents, 5, WithSHA256())); found {
+		t.Errorf("LocateMismatch() of identical streams got: found, wanted none")
+	}
+	if _, _, found := LocateMismatch(want, record(corrupt, 10, WithMD5())); found {
+		t.Errorf("LocateMismatch() without common hashes got: found, wanted none")
+	}
+}
@@ -0,0 +1,89 @@
+package hashio
+
+import (
+	"hash"
+	"io"
+	"slices"
+	"sync"
+)
+
+// registryMu guards factories, which Register adds to at run time, and
+// replaced.
+var registryMu sync.RWMutex
+
+// replaced holds the names already known when passed to Register, such as
+// those of built in algorithms, whose algorithm is no longer the one the name
+// stands for.
+var replaced = map[string]bool{}
+
+// Register makes the hash algorithm created by factory available under name
+// to every function of this package that selects hashes by name, such as
+// NewByNames, HashFile and HashBytes. It is meant to be called once per
+// algorithm, typically from an init function, so that the rest of a program
+// can pick algorithms from strings such as configuration values.
+//
+// Registering a name that is already known, including that of a built in
+// algorithm, replaces its factory; FIPS-only mode then rejects the name, as
+// FIPSApproved no longer reports it as approved. Register panics if name is empty or factory
+// is nil. It is safe to call concurrently with the functions using the
+// registry.
+func Register(name string, factory func() hash.Hash) {
+	if name == "" || factory == nil {
+		panic("hashio: Register needs a name and a factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := factories[name]; ok {
+		replaced[name] = true
+	}
+	factories[name] = factory
+}
+
+// isReplaced reports whether the factory of alg was replaced by Register.
+func isReplaced(alg Algorithm) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return replaced[alg]
+}
+
+// lookupFactory returns the factory registered for alg.
+func lookupFactory(alg Algorithm) (func() hash.Hash, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := factories[alg]
+	return f, ok
+}
+
+// registered returns the names of all registered algorithms, sorted.
+func registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NewByNames returns a HashReader that reads from r and computes a fresh hash
+// for each of the registered algorithms in names, or those of StdCryptoHashes
+// if names is empty. It returns an *UnknownHashError if a name isn't
+// registered.
+func NewByNames(r io.Reader, names ...string) (*HashReader, error) {
+	hashers, err := hashersByName(names)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashReader(r, hashers), nil
+}
+
+// NewWriterByNames is like NewByNames but returns a HashWriter that writes to
+// w.
+func NewWriterByNames(w io.Writer, names ...string) (*HashWriter, error) {
+	hashers, err := hashersByName(names)
+	if err != nil {
+		return nil, err
+	}
+	return NewHashWriter(w, hashers), nil
+}
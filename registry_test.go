@@ -0,0 +1,103 @@
+package hashio
+
+import (
+	"errors"
+	"hash"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// registerForTest registers factory under name until the end of the test.
+func registerForTest(t *testing.T, name string, factory func() hash.Hash) {
+	t.Helper()
+	Register(name, factory)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(factories, name)
+	})
+}
+
+// replaceForTest registers factory over the built in algorithm name until the
+// end of the test.
+func replaceForTest(t *testing.T, name string, factory func() hash.Hash) {
+	t.Helper()
+	registryMu.RLock()
+	builtin := factories[name]
+	registryMu.RUnlock()
+	Register(name, factory)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		factories[name] = builtin
+		delete(replaced, name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerForTest(t, "fnv-1a-64", func() hash.Hash { return fnv.New64a() })
+
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("os.Open(%q): %v", dataFile, err)
+	}
+	defer f.Close()
+	hr, err := NewByNames(f, SHA256, "fnv-1a-64")
+	if err != nil {
+		t.Fatalf("NewByNames(sha256, fnv-1a-64): %v", err)
+	}
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if got := hr.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+	want := fnv.New64a()
+	contents, _ := os.ReadFile(dataFile)
+	want.Write(contents)
+	if got := hr.Hash("fnv-1a-64", nil); string(got) != string(want.Sum(nil)) {
+		t.Errorf("HashReader.Hash(fnv-1a-64) got: %x, wanted %x", got, want.Sum(nil))
+	}
+
+	// Registered names work with every helper selecting hashes by name.
+	if sums, err := HashBytes(contents, "fnv-1a-64"); err != nil || string(sums["fnv-1a-64"]) != string(want.Sum(nil)) {
+		t.Errorf("HashBytes(fnv-1a-64) got: (%x, %v), wanted %x", sums["fnv-1a-64"], err, want.Sum(nil))
+	}
+
+	hw, err := NewWriterByNames(nil)
+	if err != nil || len(hw.Names()) != len(StdCryptoHashes()) {
+		t.Errorf("NewWriterByNames() got: (%v, %v), wanted the StdCryptoHashes", hw.Names(), err)
+	}
+
+	var unknown *UnknownHashError
+	if _, err := NewByNames(strings.NewReader(""), "nope"); !errors.As(err, &unknown) || unknown.Name != "nope" {
+		t.Errorf("NewByNames(nope) got error: %v, wanted an *UnknownHashError", err)
+	}
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registerForTest(t, "fnv-1-32", func() hash.Hash { return fnv.New32() })
+		}()
+		go func() {
+			defer wg.Done()
+			NewWriterByNames(nil, SHA256, MD5)
+		}()
+	}
+	wg.Wait()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register(\"\", nil) did not panic")
+		}
+	}()
+	Register("", nil)
+}
@@ -0,0 +1,105 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Results holds a set of digests keyed by name, as returned by Sums. It
+// marshals to JSON as an object mapping each name to its hex encoded digest,
+// e.g. {"md5":"31ee...","sha256":"535a..."}, and to text as a space separated
+// list of "name:hex" pairs ordered by name.
+type Results map[string][]byte
+
+// Results returns the digest of every hash as a Results. It is equivalent to
+// Results(h.Sums()).
+func (h *HashReader) Results() Results {
+	return Results(h.Sums())
+}
+
+// Results returns the digest of every hash as a Results. It is equivalent to
+// Results(h.Sums()).
+func (h *HashWriter) Results() Results {
+	return Results(h.Sums())
+}
+
+// Hex returns the digests in r hex encoded.
+func (r Results) Hex() map[string]string {
+	m := make(map[string]string, len(r))
+	for name, sum := range r {
+		m[name] = hex.EncodeToString(sum)
+	}
+	return m
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Results) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Hex())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Results) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	res := make(Results, len(m))
+	for name, h := range m {
+		sum, err := hex.DecodeString(h)
+		if err != nil {
+			return fmt.Errorf("hashio: invalid hex digest for %q: %w", name, err)
+		}
+		res[name] = sum
+	}
+	*r = res
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. An error is returned for a
+// name UnmarshalText couldn't read back: an empty one, or one containing ":"
+// or white space.
+func (r Results) MarshalText() ([]byte, error) {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		if name == "" || strings.Contains(name, ":") || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("hashio: name %q can't be marshaled as text", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b []byte
+	for i, name := range names {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, name...)
+		b = append(b, ':')
+		b = hex.AppendEncode(b, r[name])
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Results) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	res := make(Results, len(fields))
+	for _, f := range fields {
+		name, h, ok := strings.Cut(f, ":")
+		if !ok || name == "" {
+			return fmt.Errorf("hashio: invalid digest %q, expected name:hex", f)
+		}
+		sum, err := hex.DecodeString(h)
+		if err != nil {
+			return fmt.Errorf("hashio: invalid hex digest for %q: %w", name, err)
+		}
+		res[name] = sum
+	}
+	*r = res
+	return nil
+}
@@ -0,0 +1,62 @@
+package hashio
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestResultsMarshaling(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write(contents)
+	res := hw.Results()
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal(Results): %v", err)
+	}
+	wantJSON := `{"md5":"` + dataFileMD5 + `","sha1":"` + dataFileSHA1 + `","sha256":"` + dataFileSHA256 + `"}`
+	if string(b) != wantJSON {
+		t.Errorf("json.Marshal(Results) got: %s, wanted %s", b, wantJSON)
+	}
+
+	var fromJSON Results
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal(Results): %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, res) {
+		t.Errorf("json round trip got: %v, wanted %v", fromJSON, res)
+	}
+
+	text, err := res.MarshalText()
+	if err != nil {
+		t.Fatalf("Results.MarshalText: %v", err)
+	}
+	wantText := "md5:" + dataFileMD5 + " sha1:" + dataFileSHA1 + " sha256:" + dataFileSHA256
+	if string(text) != wantText {
+		t.Errorf("Results.MarshalText() got: %s, wanted %s", text, wantText)
+	}
+
+	var fromText Results
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatalf("Results.UnmarshalText: %v", err)
+	}
+	if !reflect.DeepEqual(fromText, res) {
+		t.Errorf("text round trip got: %v, wanted %v", fromText, res)
+	}
+
+	for _, name := range []string{"", "a:b", "a b", "a\rb", "a\vb", "a\u00a0b"} {
+		if _, err := (Results{name: []byte{1}}).MarshalText(); err == nil {
+			t.Errorf("Results{%q}.MarshalText() got: nil error, wanted an error", name)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"md5":"zz"}`), &fromJSON); err == nil {
+		t.Errorf("json.Unmarshal with invalid hex got: nil error, wanted an error")
+	}
+}
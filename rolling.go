@@ -0,0 +1,107 @@
+package hashio
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/mikewiacek/hashio/internal/gear"
+)
+
+// buzTable maps each byte value to a pseudo-random 32 bit value for the
+// buzhash rolling checksum. It reuses the fixed gear table so values are
+// stable across processes and releases.
+var buzTable = func() (t [256]uint32) {
+	for i, v := range gear.Table {
+		t[i] = uint32(v >> 32)
+	}
+	return t
+}()
+
+// RollingHasher implements io.Reader by wrapping a provided io.Reader and
+// computing a buzhash rolling checksum over the last Window bytes read. Each
+// time the checksum matches a mask, a callback is told where. This is the
+// primitive behind content-defined chunking for deduplication and delta
+// synchronisation tools.
+type RollingHasher struct {
+	r       io.Reader
+	window  []byte // the last len(window) bytes, as a ring buffer
+	pos     int    // index in window of the oldest byte
+	filled  bool   // window holds len(window) bytes
+	sum     uint32
+	n       int64
+	mask    uint32
+	onMatch func(offset int64, sum uint32)
+}
+
+// NewRollingHasher returns a RollingHasher reading from r with a window of
+// window bytes (at least 1). Once the window is full, onMatch is called for
+// every byte after which the checksum ANDed with mask is zero, with the
+// number of bytes read up to and including that byte and the checksum. A mask
+// with k bits set matches about once every 2^k bytes. onMatch may be nil.
+func NewRollingHasher(r io.Reader, window int, mask uint32, onMatch func(offset int64, sum uint32)) *RollingHasher {
+	if window < 1 {
+		window = 1
+	}
+	return &RollingHasher{
+		r:       r,
+		window:  make([]byte, window),
+		mask:    mask,
+		onMatch: onMatch,
+	}
+}
+
+// Read reads from the wrapped io.Reader and rolls the checksum over the data.
+func (h *RollingHasher) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	for _, b := range p[:n] {
+		h.roll(b)
+	}
+	return n, err
+}
+
+// roll adds b to the window, evicting the oldest byte once it is full.
+func (h *RollingHasher) roll(b byte) {
+	w := len(h.window)
+	out := h.window[h.pos]
+	h.sum = bits.RotateLeft32(h.sum, 1) ^ buzTable[b]
+	if h.filled {
+		h.sum ^= bits.RotateLeft32(buzTable[out], w)
+	}
+	h.window[h.pos] = b
+	h.pos++
+	if h.pos == w {
+		h.pos = 0
+		h.filled = true
+	}
+	h.n++
+	if h.filled && h.sum&h.mask == 0 && h.onMatch != nil {
+		h.onMatch(h.n, h.sum)
+	}
+}
+
+// Sum32 returns the checksum of the last Window bytes read, or of every byte
+// read if fewer than that.
+func (h *RollingHasher) Sum32() uint32 {
+	return h.sum
+}
+
+// Window returns the size of the window in bytes.
+func (h *RollingHasher) Window() int {
+	return len(h.window)
+}
+
+// BytesRead returns the number of bytes read through h since it was created
+// or last Reset.
+func (h *RollingHasher) BytesRead() int64 {
+	return h.n
+}
+
+// Reset clears the window and checksum and makes h read from r.
+func (h *RollingHasher) Reset(r io.Reader) {
+	h.r = r
+	clear(h.window)
+	h.pos = 0
+	h.filled = false
+	h.sum = 0
+	h.n = 0
+}
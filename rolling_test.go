@@ -0,0 +1,66 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// buzhash computes the checksum of p from scratch.
+func buzhash(p []byte) uint32 {
+	var sum uint32
+	for _, b := range p {
+		sum = sum<<1 | sum>>31
+		sum ^= buzTable[b]
+	}
+	return sum
+}
+
+func TestRollingHasher(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	const window = 16
+
+	data := contents
+	var matches []int64
+	rh := NewRollingHasher(bytes.NewReader(data), window, 0x7, func(off int64, sum uint32) {
+		if want := buzhash(data[off-window : off]); sum != want {
+			t.Errorf("onMatch(%d) got sum: %#x, wanted %#x", off, sum, want)
+		}
+		matches = append(matches, off)
+	})
+	if _, err := io.Copy(io.Discard, rh); err != nil {
+		t.Fatalf("io.Copy(): %v", err)
+	}
+
+	if want := buzhash(contents[len(contents)-window:]); rh.Sum32() != want {
+		t.Errorf("RollingHasher.Sum32() got: %#x, wanted %#x", rh.Sum32(), want)
+	}
+	var want []int64
+	for off := window; off <= len(contents); off++ {
+		if buzhash(contents[off-window:off])&0x7 == 0 {
+			want = append(want, int64(off))
+		}
+	}
+	if len(matches) == 0 || len(matches) != len(want) {
+		t.Fatalf("RollingHasher found %d matches, wanted %d", len(matches), len(want))
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("match %d got offset: %d, wanted %d", i, matches[i], want[i])
+		}
+	}
+
+	// The checksum depends only on the window contents, not on earlier data.
+	data = append([]byte("prefix"), contents...)
+	rh.Reset(bytes.NewReader(data))
+	if _, err := io.Copy(io.Discard, rh); err != nil {
+		t.Fatalf("io.Copy() after Reset: %v", err)
+	}
+	if got := buzhash(contents[len(contents)-window:]); rh.Sum32() != got {
+		t.Errorf("RollingHasher.Sum32() after Reset got: %#x, wanted %#x", rh.Sum32(), got)
+	}
+}
@@ -0,0 +1,82 @@
+package hashio
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// ReadByte implements io.ByteReader. It uses the wrapped io.Reader's ReadByte
+// method when it has one, so wrapping a *bufio.Reader keeps byte at a time
+// reads cheap, and otherwise reads a single byte with Read. The byte is hashed
+// like any other data read.
+func (h *HashReader) ReadByte() (byte, error) {
+	if h.finalized {
+		return 0, ErrFinalized
+	}
+
+	var b byte
+	if len(h.pending) > 0 {
+		b, h.pending = h.pending[0], h.pending[1:]
+	} else {
+		var err error
+		if b, err = h.readByte(); err != nil {
+			return 0, err
+		}
+	}
+
+	h.hash([]byte{b})
+	return b, nil
+}
+
+// ReadRune implements io.RuneReader. It reads the UTF-8 encoded rune one byte
+// at a time with the same strategy as ReadByte and hashes exactly the bytes
+// that make up the returned rune, including for invalid encodings, which are
+// returned as utf8.RuneError with size 1 like bufio.Reader does.
+func (h *HashReader) ReadRune() (r rune, size int, err error) {
+	if h.finalized {
+		return 0, 0, ErrFinalized
+	}
+
+	var buf [utf8.UTFMax]byte
+	n := 0
+	for n < len(buf) && !utf8.FullRune(buf[:n]) {
+		if len(h.pending) > 0 {
+			buf[n], h.pending = h.pending[0], h.pending[1:]
+		} else if buf[n], err = h.readByte(); err != nil {
+			if n == 0 {
+				return 0, 0, err
+			}
+			break
+		}
+		n++
+	}
+
+	r, size = utf8.DecodeRune(buf[:n])
+	if size < n {
+		// Keep the bytes that weren't part of the rune for the next read.
+		h.pending = append(append([]byte(nil), buf[size:n]...), h.pending...)
+	}
+	h.hash(buf[:size])
+	return r, size, nil
+}
+
+// readByte reads one byte from the wrapped io.Reader without hashing it.
+func (h *HashReader) readByte() (byte, error) {
+	if br, ok := h.r.(io.ByteReader); ok {
+		b, err := br.ReadByte()
+		h.setErr(err)
+		return b, err
+	}
+
+	var b [1]byte
+	for {
+		n, err := h.r.Read(b[:])
+		if n == 1 {
+			return b[0], nil
+		}
+		if err != nil {
+			h.setErr(err)
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package hashio
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// onlyReader hides every method but Read.
+type onlyReader struct {
+	io.Reader
+}
+
+func TestReadByteAndRune(t *testing.T) {
+	// Valid multi-byte runes, an invalid byte and a truncated rune at the end.
+	const input = "héllo, 世界 \xff\xe4\xb8"
+
+	sources := map[string]func() io.Reader{
+		"bufio.Reader": func() io.Reader { return bufio.NewReader(strings.NewReader(input)) },
+		"plain reader": func() io.Reader { return onlyReader{strings.NewReader(input)} },
+	}
+	for desc, src := range sources {
+		want := sha256.Sum256([]byte(input))
+
+		hr := NewHashReader(src(), StdCryptoHashes())
+		wr := bufio.NewReader(strings.NewReader(input))
+		for i := 0; ; i++ {
+			var got, exp rune
+			var gotSize, expSize int
+			var err, expErr error
+			if i%3 == 2 {
+				var b, eb byte
+				b, err = hr.ReadByte()
+				eb, expErr = wr.ReadByte()
+				got, exp, gotSize, expSize = rune(b), rune(eb), 1, 1
+			} else {
+				got, gotSize, err = hr.ReadRune()
+				exp, expSize, expErr = wr.ReadRune()
+			}
+			if err != expErr {
+				t.Fatalf("%s: read %d got error: %v, wanted %v", desc, i, err, expErr)
+			}
+			if err != nil {
+				break
+			}
+			if got != exp || gotSize != expSize {
+				t.Errorf("%s: read %d got: %q (%d bytes), wanted %q (%d bytes)", desc, i, got, gotSize, exp, expSize)
+			}
+		}
+
+		if hash := hr.HexHash(SHA256); hash != hex.EncodeToString(want[:]) {
+			t.Errorf("%s: HashReader.HexHash(sha256) got: %q, wanted %x", desc, hash, want)
+		}
+		if n := hr.BytesRead(); n != int64(len(input)) {
+			t.Errorf("%s: HashReader.BytesRead() got: %d, wanted %d", desc, n, len(input))
+		}
+	}
+
+	// Bytes buffered by ReadRune are returned by Read.
+	hr := NewHashReader(onlyReader{strings.NewReader("\xe4abc")}, StdCryptoHashes())
+	if r, size, _ := hr.ReadRune(); r != utf8.RuneError || size != 1 {
+		t.Errorf("HashReader.ReadRune() got: %q, %d, wanted RuneError, 1", r, size)
+	}
+	rest, err := io.ReadAll(hr)
+	if err != nil || string(rest) != "abc" {
+		t.Errorf("io.ReadAll after ReadRune got: %q, %v, wanted \"abc\", nil", rest, err)
+	}
+}
@@ -0,0 +1,253 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultS3PartSize is the part size the AWS CLI and SDKs use by default for
+// multipart uploads, 8 MiB.
+const DefaultS3PartSize = 8 << 20
+
+// S3ETag is a hash.Hash computing the ETag Amazon S3 assigns to an object
+// uploaded with a multipart upload: the MD5 digest of the concatenated binary
+// MD5 digests of every part, followed by "-" and the number of parts. Sum
+// appends the MD5 of the part digests; ETag returns the complete value.
+//
+// An S3ETag can be passed to NewHashWriter alongside other hashes to compute
+// the ETag while uploading:
+//
+//	etag := hashio.NewS3ETag(partSize)
+//	w := hashio.NewHashWriter(upload, map[string]hash.Hash{"md5": md5.New(), "s3-etag": etag})
+//
+// Objects uploaded with a single PutObject request have the plain hex MD5 of
+// their content as ETag instead; S3ETag only computes the multipart form.
+// Neither applies to objects encrypted with SSE-KMS or SSE-C.
+type S3ETag struct {
+	parts
+}
+
+// NewS3ETag returns an S3ETag for parts of partSize bytes, the last part
+// holding whatever remains. If partSize is not positive, DefaultS3PartSize is
+// used.
+func NewS3ETag(partSize int64) *S3ETag {
+	if partSize <= 0 {
+		partSize = DefaultS3PartSize
+	}
+	return &S3ETag{parts{partSize: partSize, part: md5.New()}}
+}
+
+// Sum appends the MD5 digest of the part digests to b and returns the
+// resulting slice. It does not change the underlying hash state.
+func (e *S3ETag) Sum(b []byte) []byte {
+	sum := md5.Sum(e.partSums())
+	return append(b, sum[:]...)
+}
+
+// ETag returns the multipart ETag of the data written so far, e.g.
+// "d8e8fca2dc0f896fd7cb4cb0031ba249-3", without the double quotes S3 puts
+// around it in HTTP headers.
+func (e *S3ETag) ETag() string {
+	return hex.EncodeToString(e.Sum(nil)) + "-" + strconv.Itoa(e.Parts())
+}
+
+// Matches reports whether etag, as returned by S3 with or without its double
+// quotes, is the ETag of the data written so far.
+func (e *S3ETag) Matches(etag string) bool {
+	return strings.EqualFold(strings.Trim(etag, `"`), e.ETag())
+}
+
+// Size returns the number of bytes Sum appends, md5.Size.
+func (e *S3ETag) Size() int { return md5.Size }
+
+// BlockSize returns the MD5 block size.
+func (e *S3ETag) BlockSize() int { return md5.BlockSize }
+
+// ComputeS3ETag reads r until io.EOF and returns the multipart ETag S3 would
+// assign to its content uploaded in parts of partSize bytes. See S3ETag.
+func ComputeS3ETag(r io.Reader, partSize int64) (string, error) {
+	e := NewS3ETag(partSize)
+	if _, err := io.Copy(e, r); err != nil {
+		return "", err
+	}
+	return e.ETag(), nil
+}
+
+// S3ChecksumAlgorithm is an algorithm of S3's additional checksums, as named
+// in the x-amz-checksum-algorithm header.
+type S3ChecksumAlgorithm string
+
+// The S3 additional checksum algorithms.
+const (
+	S3CRC32  S3ChecksumAlgorithm = "CRC32"
+	S3CRC32C S3ChecksumAlgorithm = "CRC32C"
+	S3SHA1   S3ChecksumAlgorithm = "SHA1"
+	S3SHA256 S3ChecksumAlgorithm = "SHA256"
+)
+
+var s3ChecksumFactories = map[S3ChecksumAlgorithm]func() hash.Hash{
+	S3CRC32:  newCRC32,
+	S3CRC32C: newCRC32C,
+	S3SHA1:   sha1.New,
+	S3SHA256: sha256.New,
+}
+
+// S3Checksum is a hash.Hash computing one of the additional checksums S3
+// stores with an object (the x-amz-checksum-crc32, -crc32c, -sha1 and -sha256
+// headers). For a single part upload the checksum is the checksum of the
+// content. For a multipart upload S3 reports a composite checksum: the
+// checksum of the concatenated binary checksums of every part, followed by
+// "-" and the number of parts. Sum appends the binary checksum; Value returns
+// the base64 encoded form S3 expects and reports.
+type S3Checksum struct {
+	parts
+	alg       S3ChecksumAlgorithm
+	multipart bool
+	h         hash.Hash // combines the part checksums
+}
+
+// NewS3Checksum returns an S3Checksum computing alg over parts of partSize
+// bytes, or over the whole content, as for a single part upload, if partSize
+// is not positive.
+func NewS3Checksum(alg S3ChecksumAlgorithm, partSize int64) (*S3Checksum, error) {
+	f, ok := s3ChecksumFactories[alg]
+	if !ok {
+		return nil, fmt.Errorf("hashio: unsupported S3 checksum algorithm %q", alg)
+	}
+	c := &S3Checksum{alg: alg, multipart: partSize > 0, h: f()}
+	if !c.multipart {
+		partSize = math.MaxInt64
+	}
+	c.parts = parts{partSize: partSize, part: f()}
+	return c, nil
+}
+
+// Sum appends the binary checksum of the data written so far to b and returns
+// the resulting slice. It does not change the underlying hash state.
+func (c *S3Checksum) Sum(b []byte) []byte {
+	if !c.multipart {
+		return c.part.Sum(b)
+	}
+	c.h.Reset()
+	c.h.Write(c.partSums())
+	return c.h.Sum(b)
+}
+
+// Value returns the checksum of the data written so far as S3 reports it,
+// base64 encoded and, for multipart uploads, followed by "-" and the number
+// of parts, e.g. "X+6o8g==-3".
+func (c *S3Checksum) Value() string {
+	v := base64.StdEncoding.EncodeToString(c.Sum(nil))
+	if c.multipart {
+		v += "-" + strconv.Itoa(c.Parts())
+	}
+	return v
+}
+
+// PartValues returns the base64 encoded checksum of every part of the data
+// written so far, as sent with each UploadPart request.
+func (c *S3Checksum) PartValues() []string {
+	sums := c.partSums()
+	size := c.part.Size()
+	values := make([]string, 0, len(sums)/size)
+	for ; len(sums) > 0; sums = sums[size:] {
+		values = append(values, base64.StdEncoding.EncodeToString(sums[:size]))
+	}
+	return values
+}
+
+// Matches reports whether v, as reported by S3, is the checksum of the data
+// written so far.
+func (c *S3Checksum) Matches(v string) bool {
+	return v == c.Value()
+}
+
+// Algorithm returns the checksum algorithm.
+func (c *S3Checksum) Algorithm() S3ChecksumAlgorithm { return c.alg }
+
+// Header returns the name of the HTTP header carrying the checksum, e.g.
+// "x-amz-checksum-crc32c".
+func (c *S3Checksum) Header() string {
+	return "x-amz-checksum-" + strings.ToLower(string(c.alg))
+}
+
+// Size returns the number of bytes Sum appends.
+func (c *S3Checksum) Size() int { return c.h.Size() }
+
+// BlockSize returns the block size of the checksum algorithm.
+func (c *S3Checksum) BlockSize() int { return c.h.BlockSize() }
+
+// ComputeS3Checksum reads r until io.EOF and returns the checksum S3 would
+// report for its content uploaded in parts of partSize bytes, or in a single
+// part if partSize is not positive. See S3Checksum.
+func ComputeS3Checksum(r io.Reader, alg S3ChecksumAlgorithm, partSize int64) (string, error) {
+	c, err := NewS3Checksum(alg, partSize)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(c, r); err != nil {
+		return "", err
+	}
+	return c.Value(), nil
+}
+
+// parts splits the data written to it into parts of partSize bytes and keeps
+// the digest of every part, as S3 multipart uploads do. Its methods are shared
+// by S3ETag and S3Checksum.
+type parts struct {
+	partSize int64
+	n        int64     // bytes written to the current part
+	part     hash.Hash // hash of the current part
+	sums     []byte    // digests of the completed parts
+}
+
+// Write adds p to the current part, starting a new part whenever it is full.
+// It never returns an error.
+func (p *parts) Write(b []byte) (int, error) {
+	written := len(b)
+	for len(b) > 0 {
+		k := min(int64(len(b)), p.partSize-p.n)
+		p.part.Write(b[:k])
+		p.n += k
+		b = b[k:]
+		if p.n == p.partSize {
+			p.sums = p.part.Sum(p.sums)
+			p.part.Reset()
+			p.n = 0
+		}
+	}
+	return written, nil
+}
+
+// partSums returns the digests of every part of the data written so far,
+// including the final, incomplete part. An empty upload has one empty part.
+func (p *parts) partSums() []byte {
+	if p.n == 0 && len(p.sums) > 0 {
+		return p.sums
+	}
+	return p.part.Sum(p.sums[:len(p.sums):len(p.sums)])
+}
+
+// Parts returns the number of parts of the data written so far.
+func (p *parts) Parts() int {
+	return len(p.partSums()) / p.part.Size()
+}
+
+// PartSize returns the part size.
+func (p *parts) PartSize() int64 { return p.partSize }
+
+// Reset discards the data written so far, keeping the part size.
+func (p *parts) Reset() {
+	p.part.Reset()
+	p.n = 0
+	p.sums = p.sums[:0]
+}
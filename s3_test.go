@@ -0,0 +1,124 @@
+package hashio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+)
+
+func TestS3ETag(t *testing.T) {
+	for _, tc := range []struct {
+		data     string
+		partSize int64
+		want     string
+	}{
+		{"hello, world", 5, "909ce955bd5188668b0191809affc873-3"},
+		// The last part is full, so there is no trailing empty part.
+		{"hello, world!!!", 5, "d00c78f9d774766c16988a3295813b6f-3"},
+		// An empty upload has a single empty part.
+		{"", 5, "59adb24ef3cdbe0297f05b395827453f-1"},
+	} {
+		e := NewS3ETag(tc.partSize)
+		// Write in uneven pieces to cross part boundaries.
+		for data := tc.data; len(data) > 0; {
+			k := min(len(data), 3)
+			e.Write([]byte(data[:k]))
+			data = data[k:]
+		}
+		if got := e.ETag(); got != tc.want {
+			t.Errorf("S3ETag.ETag() of %q got: %q, wanted %q", tc.data, got, tc.want)
+		}
+		if !e.Matches(`"` + strings.ToUpper(tc.want) + `"`) {
+			t.Errorf("S3ETag.Matches(%q) of %q got: false, wanted true", tc.want, tc.data)
+		}
+
+		got, err := ComputeS3ETag(strings.NewReader(tc.data), tc.partSize)
+		if err != nil || got != tc.want {
+			t.Errorf("ComputeS3ETag(%q) got: (%q, %v), wanted (%q, nil)", tc.data, got, err, tc.want)
+		}
+	}
+}
+
+func TestS3ETagHashWriter(t *testing.T) {
+	etag := NewS3ETag(0)
+	if got := etag.PartSize(); got != DefaultS3PartSize {
+		t.Errorf("NewS3ETag(0).PartSize() got: %d, wanted %d", got, DefaultS3PartSize)
+	}
+	etag = NewS3ETag(4)
+	w := NewHashWriter(nil, map[string]hash.Hash{MD5: md5.New(), "s3-etag": etag})
+	w.Write([]byte("0123456789"))
+	if got, want := etag.Parts(), 3; got != want {
+		t.Errorf("S3ETag.Parts() got: %d, wanted %d", got, want)
+	}
+	if got := w.HexHash("s3-etag") + "-3"; got != etag.ETag() {
+		t.Errorf("HashWriter.HexHash(s3-etag) got: %q, wanted the ETag %q", got, etag.ETag())
+	}
+
+	etag.Reset()
+	if got, want := etag.ETag(), "59adb24ef3cdbe0297f05b395827453f-1"; got != want {
+		t.Errorf("S3ETag.ETag() after Reset got: %q, wanted %q", got, want)
+	}
+}
+
+func TestComputeS3ETagError(t *testing.T) {
+	boom := errors.New("boom")
+	if got, err := ComputeS3ETag(&errReader{[]byte("partial"), boom}, 4); err != boom || got != "" {
+		t.Errorf("ComputeS3ETag() with failing reader got: (%q, %v), wanted (\"\", %v)", got, err, boom)
+	}
+	if got, err := ComputeS3ETag(bytes.NewReader(nil), 4); err != nil || !strings.HasSuffix(got, "-1") {
+		t.Errorf("ComputeS3ETag(empty) got: (%q, %v), wanted one part", got, err)
+	}
+}
+
+func TestS3Checksum(t *testing.T) {
+	const data = "hello, world"
+	for _, tc := range []struct {
+		alg        S3ChecksumAlgorithm
+		header     string
+		single     string
+		multipart  string
+		partValues []string
+	}{
+		{S3CRC32, "x-amz-checksum-crc32", "/6tyOg==", "mx9N6Q==-3", []string{"NhCmhg==", "b/GDiw==", "wk6TFQ=="}},
+		{S3CRC32C, "x-amz-checksum-crc32c", "aZmkHw==", "X+6o8g==-3", []string{"mnG7TA==", "eegL5w==", "BD/0zQ=="}},
+		{S3SHA1, "x-amz-checksum-sha1", "t+I+wpryKwtOQdox6GjVciYSHIQ=", "q1wkTqAsdSR78t6nt8WxydQmBQI=-3",
+			[]string{"qvTGHdzF6KLavt4PO0gs2a6pQ00=", "BJQzNaVIaF6Roz/ZpmhZnk1xuF0=", "KTRSa3iKQZwVw1HIRip6fu9GM/s="}},
+		{S3SHA256, "x-amz-checksum-sha256", "Ccp+TqpuiunH0mEWcSkYSINkTQffuny/vEyKLgg2DVs=", "7OnWw/8V0MMv7x+dzTgYrNZqGdPW92qZALpDRpf1iwQ=-3",
+			[]string{"LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=", "INXhMIFEW9Z16L/ng0XU2X37XU/W8GSZyLK7p8dVLtI=", "5aCP/T11CcZueWQu29zY7YiSaacWTHGK/KVBMEGIQj0="}},
+	} {
+		got, err := ComputeS3Checksum(strings.NewReader(data), tc.alg, 0)
+		if err != nil || got != tc.single {
+			t.Errorf("ComputeS3Checksum(%s, single part) got: (%q, %v), wanted (%q, nil)", tc.alg, got, err, tc.single)
+		}
+
+		c, err := NewS3Checksum(tc.alg, 5)
+		if err != nil {
+			t.Fatalf("NewS3Checksum(%s): %v", tc.alg, err)
+		}
+		w := NewHashWriter(nil, map[string]hash.Hash{"s3": c})
+		w.Write([]byte(data))
+		if got := c.Value(); got != tc.multipart || !c.Matches(tc.multipart) {
+			t.Errorf("S3Checksum(%s).Value() got: %q, wanted %q", tc.alg, got, tc.multipart)
+		}
+		if got := c.PartValues(); strings.Join(got, ",") != strings.Join(tc.partValues, ",") {
+			t.Errorf("S3Checksum(%s).PartValues() got: %q, wanted %q", tc.alg, got, tc.partValues)
+		}
+		if got := c.Header(); got != tc.header {
+			t.Errorf("S3Checksum(%s).Header() got: %q, wanted %q", tc.alg, got, tc.header)
+		}
+		if got := w.Base64Hash("s3") + "-3"; got != tc.multipart {
+			t.Errorf("HashWriter.Base64Hash(s3) for %s got: %q, wanted %q", tc.alg, got, tc.multipart)
+		}
+	}
+
+	// The standard CRC-32C check value.
+	if got, _ := ComputeS3Checksum(strings.NewReader("123456789"), S3CRC32C, 0); got != "4waSgw==" {
+		t.Errorf("ComputeS3Checksum(CRC32C, %q) got: %q, wanted %q", "123456789", got, "4waSgw==")
+	}
+	if _, err := NewS3Checksum("MD5", 0); err == nil {
+		t.Errorf("NewS3Checksum(MD5) got no error")
+	}
+}
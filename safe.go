@@ -0,0 +1,207 @@
+package hashio
+
+import (
+	"io"
+	"sync"
+)
+
+// SafeHashReader wraps a HashReader so that its digests and counters can be
+// read by other goroutines while one goroutine reads the stream, for example
+// to report progress or intermediate digests. Calling the HashReader's own
+// methods directly is a data race in that situation.
+//
+// The lock is not held while the wrapped io.Reader blocks, so polling stays
+// responsive on slow sources. Read and Reset must still not be called
+// concurrently with each other.
+type SafeHashReader struct {
+	mu sync.Mutex
+	h  *HashReader
+}
+
+// NewSafeHashReader returns a SafeHashReader guarding h. h must not be used
+// directly afterwards.
+func NewSafeHashReader(h *HashReader) *SafeHashReader {
+	return &SafeHashReader{h: h}
+}
+
+// Read is like HashReader.Read.
+func (s *SafeHashReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	h := s.h
+	if h.finalized || len(h.pending) > 0 {
+		defer s.mu.Unlock()
+		return h.Read(p)
+	}
+	r := h.r
+	s.mu.Unlock()
+
+	n, err := r.Read(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h.hash(p[:n])
+	h.setErr(err)
+	return n, err
+}
+
+// Hash is like HashReader.Hash.
+func (s *SafeHashReader) Hash(name string, buf []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Hash(name, buf)
+}
+
+// HexHash is like HashReader.HexHash.
+func (s *SafeHashReader) HexHash(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.HexHash(name)
+}
+
+// LookupHash is like HashReader.LookupHash.
+func (s *SafeHashReader) LookupHash(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.LookupHash(name)
+}
+
+// Sums is like HashReader.Sums.
+func (s *SafeHashReader) Sums() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Sums()
+}
+
+// HexSums is like HashReader.HexSums.
+func (s *SafeHashReader) HexSums() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.HexSums()
+}
+
+// Names is like HashReader.Names.
+func (s *SafeHashReader) Names() []string {
+	return s.h.Names()
+}
+
+// BytesRead is like HashReader.BytesRead.
+func (s *SafeHashReader) BytesRead() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.BytesRead()
+}
+
+// Err is like HashReader.Err.
+func (s *SafeHashReader) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Err()
+}
+
+// Reset is like HashReader.Reset.
+func (s *SafeHashReader) Reset(r io.Reader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.h.Reset(r)
+}
+
+// SafeHashWriter is the HashWriter counterpart of SafeHashReader. The lock is
+// not held while the wrapped io.Writer blocks, and Write and Reset must not be
+// called concurrently with each other.
+type SafeHashWriter struct {
+	mu sync.Mutex
+	h  *HashWriter
+}
+
+// NewSafeHashWriter returns a SafeHashWriter guarding h. h must not be used
+// directly afterwards.
+func NewSafeHashWriter(h *HashWriter) *SafeHashWriter {
+	return &SafeHashWriter{h: h}
+}
+
+// Write is like HashWriter.Write.
+func (s *SafeHashWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	h := s.h
+	if h.finalized || h.dst == nil {
+		defer s.mu.Unlock()
+		return h.Write(p)
+	}
+	dst := h.dst
+	s.mu.Unlock()
+
+	n, err := dst.Write(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return h.accept(p, n, err)
+}
+
+// Hash is like HashWriter.Hash.
+func (s *SafeHashWriter) Hash(name string, buf []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Hash(name, buf)
+}
+
+// HexHash is like HashWriter.HexHash.
+func (s *SafeHashWriter) HexHash(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.HexHash(name)
+}
+
+// LookupHash is like HashWriter.LookupHash.
+func (s *SafeHashWriter) LookupHash(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.LookupHash(name)
+}
+
+// Sums is like HashWriter.Sums.
+func (s *SafeHashWriter) Sums() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Sums()
+}
+
+// HexSums is like HashWriter.HexSums.
+func (s *SafeHashWriter) HexSums() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.HexSums()
+}
+
+// Names is like HashWriter.Names.
+func (s *SafeHashWriter) Names() []string {
+	return s.h.Names()
+}
+
+// BytesWritten is like HashWriter.BytesWritten.
+func (s *SafeHashWriter) BytesWritten() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.BytesWritten()
+}
+
+// Err is like HashWriter.Err.
+func (s *SafeHashWriter) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Err()
+}
+
+// Flush is like HashWriter.Flush. The lock is held while the wrapped
+// io.Writer flushes.
+func (s *SafeHashWriter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Flush()
+}
+
+// Reset is like HashWriter.Reset.
+func (s *SafeHashWriter) Reset(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.h.Reset(w)
+}
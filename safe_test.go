@@ -0,0 +1,68 @@
+package hashio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+// TestSafeWrappers is most useful with -race.
+func TestSafeWrappers(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	sr := NewSafeHashReader(NewHashReader(&onlyReader{bytes.NewReader(contents)}, StdCryptoHashes()))
+	var buf bytes.Buffer
+	sw := NewSafeHashWriter(NewHashWriter(&buf, StdCryptoHashes()))
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			sr.HexHash(SHA256)
+			sr.BytesRead()
+			sw.Sums()
+			sw.BytesWritten()
+		}
+	}()
+
+	p := make([]byte, 7)
+	for {
+		n, err := sr.Read(p)
+		if _, werr := sw.Write(p[:n]); werr != nil {
+			t.Fatalf("SafeHashWriter.Write(): %v", werr)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SafeHashReader.Read(): %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if !bytes.Equal(buf.Bytes(), contents) {
+		t.Errorf("SafeHashWriter wrote %d bytes, wanted %d", buf.Len(), len(contents))
+	}
+	if got := sr.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("SafeHashReader.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+	if got := sw.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("SafeHashWriter.HexHash(sha256) got: %q, wanted %q", got, dataFileSHA256)
+	}
+	if sr.BytesRead() != int64(len(contents)) || sw.BytesWritten() != int64(len(contents)) {
+		t.Errorf("BytesRead() = %d, BytesWritten() = %d, wanted %d", sr.BytesRead(), sw.BytesWritten(), len(contents))
+	}
+}
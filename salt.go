@@ -0,0 +1,151 @@
+package hashio
+
+import (
+	"encoding"
+	"fmt"
+	"hash"
+)
+
+// WithPrefix makes the hash identified by name hash salt before the stream,
+// for domain separation or salted fingerprints. The prefix is hashed again
+// whenever the hash is Reset. It is not counted by BytesRead or BytesWritten.
+// Prefixes given for the same name are concatenated in order.
+//
+// name must be added by another option, before or after WithPrefix, or
+// NewReader and NewWriter panic.
+func WithPrefix(name string, salt []byte) Option {
+	salt = append([]byte(nil), salt...)
+	return func(c *config) {
+		c.salts = append(c.salts, saltConfig{name: name, prefix: salt})
+	}
+}
+
+// WithSuffix makes the hash identified by name hash salt after the stream:
+// every digest of that hash is taken as if salt was hashed after the data so
+// far, without changing its state, so hashing can carry on afterwards.
+// Suffixes given for the same name are concatenated in order.
+//
+// The hash must implement hash.Cloner, or both encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, as those of the standard library and the built
+// in algorithms do, and name must be added by another option, or NewReader
+// and NewWriter panic.
+func WithSuffix(name string, salt []byte) Option {
+	salt = append([]byte(nil), salt...)
+	return func(c *config) {
+		c.salts = append(c.salts, saltConfig{name: name, suffix: salt})
+	}
+}
+
+// saltConfig is a prefix or suffix set by WithPrefix or WithSuffix.
+type saltConfig struct {
+	name           string
+	prefix, suffix []byte
+}
+
+// applySalts wraps the hashes of c.hashers given a prefix or suffix in a
+// saltedHash.
+func (c *config) applySalts() {
+	for _, s := range c.salts {
+		h, ok := c.hashers[s.name]
+		if !ok {
+			panic(fmt.Sprintf("hashio: salt for unknown hash %q", s.name))
+		}
+		sh, ok := h.(*saltedHash)
+		if !ok {
+			sh = &saltedHash{Hash: h}
+			c.hashers[s.name] = sh
+		}
+		sh.prefix = append(sh.prefix, s.prefix...)
+		sh.suffix = append(sh.suffix, s.suffix...)
+		sh.Hash.Write(s.prefix)
+	}
+	for name, h := range c.hashers {
+		if sh, ok := h.(*saltedHash); ok && len(sh.suffix) > 0 && !restorable(sh.Hash) {
+			panic(fmt.Sprintf("hashio: the %s hash can't be cloned to add a suffix", name))
+		}
+	}
+}
+
+// restorable reports whether saltedHash.Sum can take a digest of h without
+// changing it.
+func restorable(h hash.Hash) bool {
+	if _, ok := h.(hash.Cloner); ok {
+		return true
+	}
+	_, m := h.(encoding.BinaryMarshaler)
+	_, u := h.(encoding.BinaryUnmarshaler)
+	return m && u
+}
+
+// saltedHash is a hash.Hash that hashes prefix first, and whose digests are
+// taken as if suffix was hashed last.
+type saltedHash struct {
+	hash.Hash
+	prefix, suffix []byte
+}
+
+func (s *saltedHash) Reset() {
+	s.Hash.Reset()
+	s.Hash.Write(s.prefix)
+}
+
+func (s *saltedHash) Sum(b []byte) []byte {
+	if len(s.suffix) == 0 {
+		return s.Hash.Sum(b)
+	}
+	if c, ok := s.Hash.(hash.Cloner); ok {
+		if clone, err := c.Clone(); err == nil {
+			clone.Write(s.suffix)
+			return clone.Sum(b)
+		}
+	}
+	// Save the state, hash the suffix and restore the state.
+	m, ok := s.Hash.(encoding.BinaryMarshaler)
+	if !ok {
+		panic(fmt.Sprintf("hashio: %T can't be cloned to add a suffix", s.Hash))
+	}
+	state, err := m.MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("hashio: saving a hash state to add a suffix: %v", err))
+	}
+	s.Hash.Write(s.suffix)
+	b = s.Hash.Sum(b)
+	if err := s.Hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic(fmt.Sprintf("hashio: restoring a hash state after adding a suffix: %v", err))
+	}
+	return b
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler if the salted hash does.
+// The state doesn't include the prefix and suffix, which are part of the
+// configuration.
+func (s *saltedHash) MarshalBinary() ([]byte, error) {
+	m, ok := s.Hash.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement encoding.BinaryMarshaler", s.Hash)
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler if the salted hash
+// does.
+func (s *saltedHash) UnmarshalBinary(data []byte) error {
+	u, ok := s.Hash.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("hashio: %T does not implement encoding.BinaryUnmarshaler", s.Hash)
+	}
+	return u.UnmarshalBinary(data)
+}
+
+// Clone implements hash.Cloner if the salted hash does.
+func (s *saltedHash) Clone() (hash.Cloner, error) {
+	c, ok := s.Hash.(hash.Cloner)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement hash.Cloner", s.Hash)
+	}
+	clone, err := c.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &saltedHash{Hash: clone, prefix: s.prefix, suffix: s.suffix}, nil
+}
@@ -0,0 +1,69 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithPrefixSuffix(t *testing.T) {
+	const (
+		salted = "47cd9bdf48ebd6f4030bd0776d4d880194d34236508777cfc1bce3229a03cb1e" // sha256("v1:hello:end")
+		md5    = "5d41402abc4b2a76b9719d911017c592"                                 // md5("hello")
+	)
+	r := NewReader(strings.NewReader("hello"),
+		WithPrefix(SHA256, []byte("v1")), WithSHA256(), WithMD5(),
+		WithPrefix(SHA256, []byte(":")), WithSuffix(SHA256, []byte(":end")))
+	io.ReadAll(r)
+	if got := r.HexHash(SHA256); got != salted {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, salted)
+	}
+	// Taking the digest doesn't hash the suffix into the state.
+	if got := r.HexHash(SHA256); got != salted {
+		t.Errorf("HashReader.HexHash(sha256) a second time got: %q, wanted %q", got, salted)
+	}
+	if got := r.HexHash(MD5); got != md5 {
+		t.Errorf("HashReader.HexHash(md5) got: %q, wanted %q", got, md5)
+	}
+	if got := r.BytesRead(); got != 5 {
+		t.Errorf("HashReader.BytesRead() got: %d, wanted 5", got)
+	}
+
+	state, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("HashReader.MarshalBinary(): %v", err)
+	}
+	r.Reset(strings.NewReader("hello"))
+	io.ReadAll(r)
+	if got := r.HexHash(SHA256); got != salted {
+		t.Errorf("HashReader.HexHash(sha256) after Reset got: %q, wanted %q", got, salted)
+	}
+	r.Reset(strings.NewReader(""))
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatalf("HashReader.UnmarshalBinary(): %v", err)
+	}
+	if got := r.Snapshot()[SHA256]; hex.EncodeToString(got) != salted {
+		t.Errorf("HashReader.Snapshot()[sha256] after UnmarshalBinary got: %x, wanted %s", got, salted)
+	}
+}
+
+func TestWithSuffixPipelined(t *testing.T) {
+	w := NewWriter(nil, WithSHA256(), WithSuffix(SHA256, []byte(":end")), WithPipelining())
+	w.WriteString("hello")
+	w.HexHash(SHA256)
+	w.WriteString(" world")
+	// sha256("hello world:end")
+	if got, want := w.HexHash(SHA256), "eaabc56c3cf84258f83d76520f76cbd7040af4ab5b0e958d360b2357f69638d3"; got != want {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, want)
+	}
+}
+
+func TestWithPrefixUnknownHash(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewWriter(WithPrefix(missing)) didn't panic")
+		}
+	}()
+	NewWriter(nil, WithSHA256(), WithPrefix("missing", []byte("salt")))
+}
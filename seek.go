@@ -0,0 +1,73 @@
+package hashio
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// ErrSeeked is recorded as a SeekableHashReader's error (see HashReader.Err)
+// when a seek makes its digests no longer describe a contiguous stream.
+var ErrSeeked = errors.New("hashio: seek invalidated the digests")
+
+// SeekableHashReader is a HashReader that also implements io.Seeker with
+// defined effects on its digests:
+//
+//   - Seek(0, io.SeekStart) rewinds the stream and resets every hash, so the
+//     data is hashed again from the beginning.
+//   - Seek(0, io.SeekCurrent) only reports the current offset.
+//   - Any other seek invalidates the digests by recording ErrSeeked, after
+//     which the digest accessors behave as after a read error, until the next
+//     Seek(0, io.SeekStart) or Reset.
+//
+// The digests always describe the data from offset zero if the wrapped
+// io.ReadSeeker starts there.
+type SeekableHashReader struct {
+	*HashReader
+	s io.Seeker
+}
+
+// NewSeekableHashReader is like NewHashReader for an io.ReadSeeker, such as an
+// *os.File, whose Seek method must stay available.
+func NewSeekableHashReader(rs io.ReadSeeker, hashers map[string]hash.Hash) *SeekableHashReader {
+	return &SeekableHashReader{
+		HashReader: NewHashReader(rs, hashers),
+		s:          rs,
+	}
+}
+
+// Seek implements io.Seeker. See SeekableHashReader for its effect on the
+// digests. Errors from the wrapped io.Seeker are returned without changing the
+// digests.
+func (s *SeekableHashReader) Seek(offset int64, whence int) (int64, error) {
+	// Bytes buffered by ReadRune have been read from the wrapped reader but
+	// not returned, so relative seeks must account for them.
+	rel := offset
+	if whence == io.SeekCurrent {
+		rel -= int64(len(s.pending))
+	}
+	pos, err := s.s.Seek(rel, whence)
+	if err != nil {
+		return pos, err
+	}
+	s.pending = nil
+
+	switch {
+	case offset == 0 && whence == io.SeekCurrent:
+	case offset == 0 && whence == io.SeekStart:
+		resetAll(s.hashers)
+		s.n = 0
+		s.err = nil
+	default:
+		if s.err == nil {
+			s.err = ErrSeeked
+		}
+	}
+	return pos, nil
+}
+
+// Reset is like HashReader.Reset but takes an io.ReadSeeker.
+func (s *SeekableHashReader) Reset(rs io.ReadSeeker) {
+	s.HashReader.Reset(rs)
+	s.s = rs
+}
@@ -0,0 +1,57 @@
+package hashio
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSeekableHashReader(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+	defer f.Close()
+
+	hr := NewSeekableHashReader(f, StdCryptoHashes())
+	if _, err := hr.Read(make([]byte, 10)); err != nil {
+		t.Fatalf("SeekableHashReader.Read: %v", err)
+	}
+	if pos, err := hr.Seek(0, io.SeekCurrent); pos != 10 || err != nil {
+		t.Errorf("SeekableHashReader.Seek(0, io.SeekCurrent) got: %d, %v, wanted 10, nil", pos, err)
+	}
+
+	// Rewinding starts the hashes over.
+	if _, err := hr.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("SeekableHashReader.Seek(0, io.SeekStart): %v", err)
+	}
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if hash := hr.HexHash(SHA256); hash != dataFileSHA256 {
+		t.Errorf("SeekableHashReader.HexHash(sha256) after rewinding got: %q, wanted %q", hash, dataFileSHA256)
+	}
+
+	// Any other seek invalidates the digests.
+	if _, err := hr.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("SeekableHashReader.Seek(5, io.SeekStart): %v", err)
+	}
+	if err := hr.Err(); err != ErrSeeked {
+		t.Errorf("SeekableHashReader.Err() got: %v, wanted ErrSeeked", err)
+	}
+	if _, err := hr.LookupHash(SHA256); !errors.Is(err, ErrSeeked) {
+		t.Errorf("SeekableHashReader.LookupHash(sha256) got error: %v, wanted one wrapping ErrSeeked", err)
+	}
+
+	if _, err := hr.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("SeekableHashReader.Seek(0, io.SeekStart): %v", err)
+	}
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+	if hash := hr.HexHash(MD5); hash != dataFileMD5 {
+		t.Errorf("SeekableHashReader.HexHash(md5) after rewinding got: %q, wanted %q", hash, dataFileMD5)
+	}
+}
@@ -0,0 +1,20 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"hash"
+)
+
+// newSHA256d returns the factory of NewSHA256d.
+var newSHA256d = Compose(sha256.New, sha256.New)
+
+// NewSHA256d returns a hash.Hash computing SHA256d, the SHA-256 digest of the
+// SHA-256 digest of the data, as used for block and transaction identifiers by
+// Bitcoin and related protocols. Digests are returned in hashing order;
+// Bitcoin displays identifiers with their bytes reversed.
+//
+// It is available under the name SHA256d, and its state can be marshaled like
+// that of SHA-256.
+func NewSHA256d() hash.Hash {
+	return newSHA256d()
+}
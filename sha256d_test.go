@@ -0,0 +1,41 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestSHA256d(t *testing.T) {
+	// The header of the Bitcoin genesis block, whose identifier is its
+	// SHA256d digest reversed.
+	header, _ := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	r, err := NewByNames(bytes.NewReader(header), SHA256d, SHA256)
+	if err != nil {
+		t.Fatalf("NewByNames(): %v", err)
+	}
+	io.ReadAll(r)
+	sum := r.Hash(SHA256d, nil)
+	slices.Reverse(sum)
+	if got, want := hex.EncodeToString(sum), "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"; got != want {
+		t.Errorf("reversed HashReader.Hash(sha256d) got: %q, wanted %q", got, want)
+	}
+
+	h := NewSHA256d()
+	h.Write([]byte("hel"))
+	clone, err := h.(hash.Cloner).Clone()
+	if err != nil {
+		t.Fatalf("Clone(): %v", err)
+	}
+	clone.Write([]byte("lo"))
+	if got, want := hex.EncodeToString(clone.Sum(nil)), "9595c9df90075148eb06860365df33584b75bff782a510c6cd4883a419833d50"; got != want {
+		t.Errorf("SHA256d of hello got: %q, wanted %q", got, want)
+	}
+	// The clone is independent of h.
+	if got, want := hex.EncodeToString(h.Sum(nil)), "7fc8e8d337c5a486405d983526243cc5a651c19a528a5b1f2f73c68ae351fb42"; got != want {
+		t.Errorf("SHA256d of hel got: %q, wanted %q", got, want)
+	}
+}
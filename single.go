@@ -0,0 +1,149 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// SingleHashReader is like HashReader for the common case of exactly one hash.
+// It passes data straight to its hash.Hash without the map lookups and
+// io.MultiWriter indirection HashReader needs to support many.
+type SingleHashReader struct {
+	r    io.Reader
+	name string
+	h    hash.Hash
+	n    int64
+	err  error
+}
+
+// NewSingleHashReader returns a SingleHashReader that reads from r and passes
+// all data read to h. name identifies h, see Name.
+func NewSingleHashReader(r io.Reader, name string, h hash.Hash) *SingleHashReader {
+	return &SingleHashReader{r: r, name: name, h: h}
+}
+
+// Read reads from the wrapped io.Reader and passes the data read to the hash.
+func (s *SingleHashReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if n > 0 {
+		s.h.Write(p[:n])
+		s.n += int64(n)
+	}
+	if err != nil && err != io.EOF && s.err == nil {
+		s.err = err
+	}
+	return n, err
+}
+
+// Name returns the name passed to NewSingleHashReader.
+func (s *SingleHashReader) Name() string {
+	return s.name
+}
+
+// Hash appends the hash to buf and returns the slice. buf can be nil. If any
+// call to Read returned an error (not including io.EOF), buf is returned
+// unchanged. See Err.
+func (s *SingleHashReader) Hash(buf []byte) []byte {
+	if s.err != nil {
+		return buf
+	}
+	return s.h.Sum(buf)
+}
+
+// HexHash returns the hash as a hex encoded ASCII string, or the empty string in
+// the cases where Hash returns buf unchanged.
+func (s *SingleHashReader) HexHash() string {
+	return hex.EncodeToString(s.Hash(nil))
+}
+
+// BytesRead returns the number of bytes read through s since it was created or
+// last Reset.
+func (s *SingleHashReader) BytesRead() int64 {
+	return s.n
+}
+
+// Err returns the first error other than io.EOF returned by the wrapped
+// io.Reader since s was created or last Reset.
+func (s *SingleHashReader) Err() error {
+	return s.err
+}
+
+// Reset resets the hash and makes s read from r.
+func (s *SingleHashReader) Reset(r io.Reader) {
+	s.h.Reset()
+	s.r = r
+	s.n = 0
+	s.err = nil
+}
+
+// SingleHashWriter is like HashWriter for the common case of exactly one hash.
+// It passes data straight to its hash.Hash without the map lookups and
+// io.MultiWriter indirection HashWriter needs to support many.
+type SingleHashWriter struct {
+	w    io.Writer
+	name string
+	h    hash.Hash
+	n    int64
+	err  error
+}
+
+// NewSingleHashWriter returns a SingleHashWriter that writes to w and passes all
+// data accepted by w to h. name identifies h, see Name.
+func NewSingleHashWriter(w io.Writer, name string, h hash.Hash) *SingleHashWriter {
+	return &SingleHashWriter{w: w, name: name, h: h}
+}
+
+// Write writes p to the wrapped io.Writer and passes the bytes it accepted to
+// the hash.
+func (s *SingleHashWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	if n > 0 {
+		s.h.Write(p[:n])
+		s.n += int64(n)
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	return n, err
+}
+
+// Name returns the name passed to NewSingleHashWriter.
+func (s *SingleHashWriter) Name() string {
+	return s.name
+}
+
+// Hash appends the hash to buf and returns the slice. buf can be nil. If any
+// call to Write returned an error, buf is returned unchanged. See Err.
+func (s *SingleHashWriter) Hash(buf []byte) []byte {
+	if s.err != nil {
+		return buf
+	}
+	return s.h.Sum(buf)
+}
+
+// HexHash returns the hash as a hex encoded ASCII string, or the empty string in
+// the cases where Hash returns buf unchanged.
+func (s *SingleHashWriter) HexHash() string {
+	return hex.EncodeToString(s.Hash(nil))
+}
+
+// BytesWritten returns the number of bytes the wrapped io.Writer accepted
+// through s since it was created or last Reset.
+func (s *SingleHashWriter) BytesWritten() int64 {
+	return s.n
+}
+
+// Err returns the first error returned by the wrapped io.Writer since s was
+// created or last Reset.
+func (s *SingleHashWriter) Err() error {
+	return s.err
+}
+
+// Reset resets the hash and makes s write to w.
+func (s *SingleHashWriter) Reset(w io.Writer) {
+	s.h.Reset()
+	s.w = w
+	s.n = 0
+	s.err = nil
+}
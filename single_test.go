@@ -0,0 +1,55 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSingleHash(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+	defer f.Close()
+
+	hr := NewSingleHashReader(f, SHA256, sha256.New())
+	hw := NewSingleHashWriter(ioutil.Discard, SHA256, sha256.New())
+	n, err := io.Copy(hw, hr)
+	if err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+
+	if hash := hr.HexHash(); hash != dataFileSHA256 {
+		t.Errorf("SingleHashReader.HexHash() got: %q, wanted %q", hash, dataFileSHA256)
+	}
+	if hash := hw.HexHash(); hash != dataFileSHA256 {
+		t.Errorf("SingleHashWriter.HexHash() got: %q, wanted %q", hash, dataFileSHA256)
+	}
+	if hr.BytesRead() != n || hw.BytesWritten() != n {
+		t.Errorf("BytesRead() = %d, BytesWritten() = %d, wanted %d", hr.BytesRead(), hw.BytesWritten(), n)
+	}
+	if hr.Name() != SHA256 || hw.Name() != SHA256 {
+		t.Errorf("Name() got: %q and %q, wanted %q", hr.Name(), hw.Name(), SHA256)
+	}
+}
+
+func BenchmarkSHA256HashWriter(b *testing.B) {
+	buf := make([]byte, 32<<10)
+	hw := NewWriter(ioutil.Discard, WithSHA256())
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		hw.Write(buf)
+	}
+}
+
+func BenchmarkSHA256SingleHashWriter(b *testing.B) {
+	buf := make([]byte, 32<<10)
+	hw := NewSingleHashWriter(ioutil.Discard, SHA256, sha256.New())
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		hw.Write(buf)
+	}
+}
@@ -0,0 +1,62 @@
+package hashio
+
+import "hash"
+
+// Snapshot returns the digest of every hash keyed by name, describing the data
+// read so far, while h keeps hashing: unlike Sums, Snapshot is meant to be
+// called mid-stream. It never finalizes h, even with WithStrictFinalize, and
+// each hash that implements hash.Cloner (as those of the standard library and
+// the built in algorithms do) is cloned before its digest is taken, so the
+// ongoing hash state isn't touched at all.
+//
+// If any call to Read returned an error (not including io.EOF), nil is
+// returned. See Err.
+func (h *HashReader) Snapshot() map[string][]byte {
+	if h.err != nil {
+		return nil
+	}
+	return snapshot(h.hashers)
+}
+
+// Snapshot returns the digest of every hash keyed by name, describing the data
+// written so far, while h keeps hashing: unlike Sums, Snapshot is meant to be
+// called mid-stream. It never finalizes h, even with WithStrictFinalize, and
+// each hash that implements hash.Cloner (as those of the standard library and
+// the built in algorithms do) is cloned before its digest is taken, so the
+// ongoing hash state isn't touched at all.
+//
+// If any call to Write returned an error, nil is returned. See Err.
+func (h *HashWriter) Snapshot() map[string][]byte {
+	if h.err != nil {
+		return nil
+	}
+	return snapshot(h.hashers)
+}
+
+// Snapshot is like HashReader.Snapshot.
+func (s *SafeHashReader) Snapshot() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Snapshot()
+}
+
+// Snapshot is like HashWriter.Snapshot.
+func (s *SafeHashWriter) Snapshot() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h.Snapshot()
+}
+
+func snapshot(hashers map[string]hash.Hash) map[string][]byte {
+	m := make(map[string][]byte, len(hashers))
+	for name, hh := range hashers {
+		hh = unwrapHash(hh)
+		if c, ok := hh.(hash.Cloner); ok {
+			if clone, err := c.Clone(); err == nil {
+				hh = clone
+			}
+		}
+		m[name] = hh.Sum(nil)
+	}
+	return m
+}
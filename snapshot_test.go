@@ -0,0 +1,74 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	half := contents[:len(contents)/2]
+	names := registered()
+	want, err := HashBytes(half, names...)
+	if err != nil {
+		t.Fatalf("HashBytes(): %v", err)
+	}
+
+	opts := []Option{WithStrictFinalize()}
+	for name, hh := range allHashers() {
+		opts = append(opts, WithHasher(name, hh))
+	}
+	w := NewWriter(nil, opts...)
+	w.Write(half)
+	snap := w.Snapshot()
+	for _, name := range names {
+		if !bytes.Equal(snap[name], want[name]) {
+			t.Errorf("HashWriter.Snapshot()[%s] got: %x, wanted %x", name, snap[name], want[name])
+		}
+	}
+
+	// The writer isn't finalized and its hashes carry on.
+	if _, err := w.Write(contents[len(half):]); err != nil {
+		t.Fatalf("HashWriter.Write() after Snapshot: %v", err)
+	}
+	if got := w.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashWriter.HexHash(sha256) after Snapshot got: %q, wanted %q", got, dataFileSHA256)
+	}
+}
+
+func TestSnapshotReader(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	etag := NewS3ETag(16)
+	r := NewReader(bytes.NewReader(contents), WithSHA256(), WithHasher("etag", etag), WithPipelining())
+	s := NewSafeHashReader(r)
+	p := make([]byte, 40)
+	s.Read(p)
+
+	// S3ETag isn't a hash.Cloner, so its digest is taken in place.
+	want := NewS3ETag(16)
+	want.Write(contents[:40])
+	if snap := s.Snapshot(); !bytes.Equal(snap["etag"], want.Sum(nil)) {
+		t.Errorf("SafeHashReader.Snapshot()[etag] got: %x, wanted %x", snap["etag"], want.Sum(nil))
+	}
+	if _, err := ioutil.ReadAll(s); err != nil {
+		t.Fatalf("ioutil.ReadAll(): %v", err)
+	}
+	if got := r.HexHash(SHA256); got != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) after Snapshot got: %q, wanted %q", got, dataFileSHA256)
+	}
+
+	boom := errors.New("boom")
+	r = NewHashReader(&errReader{[]byte("partial"), boom}, StdCryptoHashes())
+	ioutil.ReadAll(r)
+	if snap := r.Snapshot(); snap != nil {
+		t.Errorf("HashReader.Snapshot() after error got: %v, wanted nil", snap)
+	}
+}
@@ -0,0 +1,77 @@
+package hashio
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sriAlgorithms are the hash algorithms allowed by the W3C Subresource
+// Integrity specification.
+var sriAlgorithms = map[string]bool{
+	SHA256: true,
+	SHA384: true,
+	SHA512: true,
+}
+
+// SRI returns the hash identified by name in the W3C Subresource Integrity form
+// "<name>-<base64>", e.g. "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC",
+// suitable for an HTML integrity attribute. Browsers only accept the names
+// "sha256", "sha384" and "sha512". It panics and returns the empty string in
+// the same cases as HexHash.
+func (h *HashReader) SRI(name string) string {
+	return formatSRI(name, h.Hash(name, nil))
+}
+
+// SRI returns the hash identified by name in the W3C Subresource Integrity form
+// "<name>-<base64>", e.g. "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC",
+// suitable for an HTML integrity attribute. Browsers only accept the names
+// "sha256", "sha384" and "sha512". It panics and returns the empty string in
+// the same cases as HexHash.
+func (h *HashWriter) SRI(name string) string {
+	return formatSRI(name, h.Hash(name, nil))
+}
+
+func formatSRI(name string, sum []byte) string {
+	if sum == nil {
+		return ""
+	}
+	return name + "-" + base64.StdEncoding.EncodeToString(sum)
+}
+
+// ParseSRI parses the value of an HTML integrity attribute, which holds one or
+// more whitespace separated "<algorithm>-<base64>[?options]" hash expressions,
+// and returns a Digest for each. Options are ignored, as the specification
+// requires. So are, as its "parse metadata" algorithm requires, expressions
+// using algorithms other than sha256, sha384 and sha512, such as those added
+// by later versions of the specification, and malformed ones, including
+// those whose digest isn't valid base64 of the size of the algorithm's. An
+// error is returned if no expression remains.
+func ParseSRI(s string) ([]Digest, error) {
+	var ds []Digest
+	for _, expr := range strings.Fields(s) {
+		expr, _, _ = strings.Cut(expr, "?")
+		alg, b64, ok := strings.Cut(expr, "-")
+		if !ok || !sriAlgorithms[alg] {
+			continue
+		}
+
+		sum, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			continue
+		}
+		h, err := newHash(alg)
+		if err != nil {
+			return nil, fmt.Errorf("hashio: SRI hash expression %q: %w", expr, err)
+		}
+		if h.Size() != len(sum) {
+			continue
+		}
+
+		ds = append(ds, Digest{Algorithm: alg, Sum: sum})
+	}
+	if len(ds) == 0 {
+		return nil, fmt.Errorf("hashio: no valid SRI hash expressions in %q", s)
+	}
+	return ds, nil
+}
@@ -0,0 +1,51 @@
+package hashio
+
+import (
+	"crypto/md5"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSRI(t *testing.T) {
+	// Example from the W3C Subresource Integrity specification.
+	const script = "alert('Hello, world.');"
+	const want = "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO"
+
+	hw := NewHashWriter(ioutil.Discard, map[string]hash.Hash{SHA384: sha512.New384()})
+	hw.Write([]byte(script))
+	if got := hw.SRI(SHA384); got != want {
+		t.Errorf("HashWriter.SRI(sha384) got: %q, wanted %q", got, want)
+	}
+
+	// Unknown algorithms and malformed expressions are skipped.
+	ds, err := ParseSRI("  " + want + "?ct=application/javascript sha1-qvTGHdzF6KLavt4PO0gs2a6pQ00= sha999-abc sha512-!!! sha256-" + "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	if err != nil {
+		t.Fatalf("ParseSRI: %v", err)
+	}
+	if len(ds) != 2 {
+		t.Fatalf("ParseSRI returned %d digests, wanted 2", len(ds))
+	}
+	if ok, _ := hw.Verify(SHA384, ds[0].Sum); !ok || ds[0].Algorithm != SHA384 {
+		t.Errorf("ParseSRI()[0] got: %v, wanted the sha384 digest of the script", ds[0])
+	}
+	if ds[1].Algorithm != SHA256 {
+		t.Errorf("ParseSRI()[1].Algorithm got: %q, wanted %q", ds[1].Algorithm, SHA256)
+	}
+
+	for _, bad := range []string{"", "md5-Me4NkgUGI2MZrl4QBfSORQ==", "sha256-!!!", "sha256-Me4NkgUGI2MZrl4QBfSORQ==", "sha256 sha384"} {
+		if _, err := ParseSRI(bad); err == nil {
+			t.Errorf("ParseSRI(%q) got: nil error, wanted an error", bad)
+		}
+	}
+
+	// newHash fails for a sha256 that isn't SHA-256 in FIPS-only mode.
+	replaceForTest(t, SHA256, md5.New)
+	SetFIPSOnly(true)
+	t.Cleanup(func() { SetFIPSOnly(false) })
+	if _, err := ParseSRI("sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="); !errors.Is(err, ErrNotFIPSApproved) {
+		t.Errorf("ParseSRI(sha256) in FIPS-only mode after Register(sha256, md5.New) got: %v, wanted %v", err, ErrNotFIPSApproved)
+	}
+}
@@ -0,0 +1,57 @@
+package hashio
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// sshFingerprintNames are the names OpenSSH prefixes fingerprints with.
+var sshFingerprintNames = map[string]string{
+	MD5:    "MD5",
+	SHA1:   "SHA1",
+	SHA256: "SHA256",
+	SHA384: "SHA384",
+	SHA512: "SHA512",
+}
+
+// SSHFingerprint formats sum, the digest of a public key blob computed with
+// alg, the way ssh and ssh-keygen display key fingerprints: base64 without
+// padding, e.g. "SHA256:ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0", or, for
+// MD5, the legacy colon separated hex form, e.g. "MD5:90:01:50:98:...". It
+// returns the empty string if sum is nil or alg is not one of MD5, SHA1,
+// SHA256, SHA384 and SHA512.
+func SSHFingerprint(alg Algorithm, sum []byte) string {
+	prefix, ok := sshFingerprintNames[alg]
+	if !ok || sum == nil {
+		return ""
+	}
+	if alg != MD5 {
+		return prefix + ":" + base64.RawStdEncoding.EncodeToString(sum)
+	}
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, c := range sum {
+		b.WriteByte(':')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xf])
+	}
+	return b.String()
+}
+
+// SSHFingerprint returns the hash identified by name formatted as an OpenSSH
+// key fingerprint by the package level SSHFingerprint, for a HashReader that
+// read a public key blob. It panics and returns the empty string in the same
+// cases as HexHash, and also returns the empty string if name is not one of
+// MD5, SHA1, SHA256, SHA384 and SHA512.
+func (h *HashReader) SSHFingerprint(name string) string {
+	return SSHFingerprint(name, h.Hash(name, nil))
+}
+
+// SSHFingerprint returns the hash identified by name formatted as an OpenSSH
+// key fingerprint by the package level SSHFingerprint, for a HashWriter that
+// wrote a public key blob. It panics and returns the empty string in the same
+// cases as HexHash, and also returns the empty string if name is not one of
+// MD5, SHA1, SHA256, SHA384 and SHA512.
+func (h *HashWriter) SSHFingerprint(name string) string {
+	return SSHFingerprint(name, h.Hash(name, nil))
+}
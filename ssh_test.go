@@ -0,0 +1,39 @@
+package hashio
+
+import (
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSSHFingerprint(t *testing.T) {
+	// The blob of an ed25519 public key and its fingerprints as printed by
+	// ssh-keygen -l.
+	const pub = "AAAAC3NzaC1lZDI1NTE5AAAAIDYWlxCgunIUWi20znVRCHYr+Z3Z69IPs0zW4vRbmLJ4"
+	r := NewReader(base64.NewDecoder(base64.StdEncoding, strings.NewReader(pub)), WithSHA256(), WithMD5(), WithSHA1())
+	io.ReadAll(r)
+	for name, want := range map[string]string{
+		SHA256: "SHA256:wyLUzcsszsMn0HzmRqYBl6iynKX8BOn0hoQmknSt6nw",
+		MD5:    "MD5:54:fd:23:33:e7:04:e1:39:dd:8c:7d:0a:37:40:e7:6b",
+	} {
+		if got := r.SSHFingerprint(name); got != want {
+			t.Errorf("HashReader.SSHFingerprint(%s) got: %q, wanted %q", name, got, want)
+		}
+	}
+	if got := r.SSHFingerprint(SHA1); !strings.HasPrefix(got, "SHA1:") || strings.HasSuffix(got, "=") {
+		t.Errorf("HashReader.SSHFingerprint(sha1) got: %q, wanted SHA1:<base64 without padding>", got)
+	}
+
+	w := NewWriter(nil, WithSHA256(), WithHasher(BLAKE3, newBLAKE3()))
+	w.WriteString("abc")
+	if got, want := w.SSHFingerprint(SHA256), "SHA256:ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0"; got != want {
+		t.Errorf("HashWriter.SSHFingerprint(sha256) got: %q, wanted %q", got, want)
+	}
+	if got := w.SSHFingerprint(BLAKE3); got != "" {
+		t.Errorf("HashWriter.SSHFingerprint(blake3) got: %q, wanted \"\"", got)
+	}
+	if got := SSHFingerprint(SHA256, nil); got != "" {
+		t.Errorf("SSHFingerprint(sha256, nil) got: %q, wanted \"\"", got)
+	}
+}
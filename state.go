@@ -0,0 +1,186 @@
+package hashio
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+)
+
+// ErrInvalidState is returned by UnmarshalBinary when the state it is given
+// is malformed or was saved from a wrapper with different hashes.
+var ErrInvalidState = errors.New("hashio: invalid hash state")
+
+// stateMagic starts every state produced by MarshalBinary.
+const stateMagic = "hashio\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the state of
+// every hash.Hash in the HashReader and the number of bytes read so far, which
+// UnmarshalBinary restores into a HashReader with the same hashes, for
+// instance to resume hashing a large file after a process restart:
+//
+//	state, err := h.MarshalBinary()
+//	// Later, possibly in another process:
+//	r := hashio.NewHashReader(f, hashio.StdCryptoHashes())
+//	err = r.UnmarshalBinary(state)
+//	_, err = f.Seek(r.BytesRead(), io.SeekStart)
+//
+// Every hash must implement encoding.BinaryMarshaler, as those of the standard
+// library and the built in algorithms do. The state of a HashReader whose
+// stream failed can't be saved; the returned error then wraps
+// ErrStreamFailed.
+func (h *HashReader) MarshalBinary() ([]byte, error) {
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	return marshalState(h.hashers, h.n)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores a state
+// saved by MarshalBinary into h, whose hashes must have the same names and
+// algorithms as those of the saved HashReader. On success h continues from
+// the saved byte count: the wrapped io.Reader must continue from the same
+// offset. On failure the state of h is undefined until Reset.
+func (h *HashReader) UnmarshalBinary(data []byte) error {
+	n, err := unmarshalState(h.hashers, data)
+	if err != nil {
+		return err
+	}
+	h.n, h.err, h.finalized, h.pending = n, nil, false, nil
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the state of
+// every hash.Hash in the HashWriter and the number of bytes written so far,
+// which UnmarshalBinary restores into a HashWriter with the same hashes. Every
+// hash must implement encoding.BinaryMarshaler, as those of the standard
+// library and the built in algorithms do. The state of a HashWriter whose
+// stream failed can't be saved; the returned error then wraps
+// ErrStreamFailed.
+func (h *HashWriter) MarshalBinary() ([]byte, error) {
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	return marshalState(h.hashers, h.n)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores a state
+// saved by MarshalBinary into h, whose hashes must have the same names and
+// algorithms as those of the saved HashWriter. On success h continues from the
+// saved byte count. On failure the state of h is undefined until Reset.
+func (h *HashWriter) UnmarshalBinary(data []byte) error {
+	n, err := unmarshalState(h.hashers, data)
+	if err != nil {
+		return err
+	}
+	h.n, h.err, h.finalized = n, nil, false
+	return nil
+}
+
+// unwrapHash returns the hash.Hash wrapped by the pipeline, once it has
+// caught up, or h itself.
+func unwrapHash(h hash.Hash) hash.Hash {
+	if p, ok := h.(pipelinedHash); ok {
+		p.p.wait()
+		return p.Hash
+	}
+	return h
+}
+
+// marshalState encodes the state of hashers and the byte count n as:
+//
+//	stateMagic
+//	n as a big-endian uint64
+//	the number of hashes as a uvarint
+//	for each hash, sorted by name:
+//		the name, prefixed by its length as a uvarint
+//		its state, prefixed by its length as a uvarint
+func marshalState(hashers map[string]hash.Hash, n int64) ([]byte, error) {
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	b := append([]byte(stateMagic), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(b[len(stateMagic):], uint64(n))
+	b = binary.AppendUvarint(b, uint64(len(names)))
+	for _, name := range names {
+		m, ok := unwrapHash(hashers[name]).(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, fmt.Errorf("hashio: the %s hash does not implement encoding.BinaryMarshaler", name)
+		}
+		state, err := m.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("hashio: saving the %s hash: %w", name, err)
+		}
+		b = binary.AppendUvarint(b, uint64(len(name)))
+		b = append(b, name...)
+		b = binary.AppendUvarint(b, uint64(len(state)))
+		b = append(b, state...)
+	}
+	return b, nil
+}
+
+// unmarshalState restores the states encoded by marshalState into hashers and
+// returns the byte count.
+func unmarshalState(hashers map[string]hash.Hash, b []byte) (int64, error) {
+	if len(b) < len(stateMagic)+8 || string(b[:len(stateMagic)]) != stateMagic {
+		return 0, ErrInvalidState
+	}
+	n := binary.BigEndian.Uint64(b[len(stateMagic):])
+	b = b[len(stateMagic)+8:]
+	count, b, ok := readUvarint(b)
+	if !ok || count != uint64(len(hashers)) || n > 1<<63-1 {
+		return 0, ErrInvalidState
+	}
+	var prev []byte
+	for ; count > 0; count-- {
+		var name, state []byte
+		// Names are sorted, so a repeated name is caught here.
+		if name, b, ok = readBytes(b); !ok || (prev != nil && string(name) <= string(prev)) {
+			return 0, ErrInvalidState
+		}
+		prev = name
+		if state, b, ok = readBytes(b); !ok {
+			return 0, ErrInvalidState
+		}
+		hh, found := hashers[string(name)]
+		if !found {
+			return 0, fmt.Errorf("%w: unexpected %s hash", ErrInvalidState, name)
+		}
+		u, ok := unwrapHash(hh).(encoding.BinaryUnmarshaler)
+		if !ok {
+			return 0, fmt.Errorf("hashio: the %s hash does not implement encoding.BinaryUnmarshaler", name)
+		}
+		if err := u.UnmarshalBinary(state); err != nil {
+			return 0, fmt.Errorf("%w: restoring the %s hash: %w", ErrInvalidState, name, err)
+		}
+	}
+	if len(b) != 0 {
+		return 0, ErrInvalidState
+	}
+	return int64(n), nil
+}
+
+// readUvarint decodes a uvarint from the start of b and returns it along with
+// the rest of b.
+func readUvarint(b []byte) (uint64, []byte, bool) {
+	v, k := binary.Uvarint(b)
+	if k <= 0 {
+		return 0, nil, false
+	}
+	return v, b[k:], true
+}
+
+// readBytes decodes a slice prefixed by its length as a uvarint from the start
+// of b and returns it along with the rest of b.
+func readBytes(b []byte) ([]byte, []byte, bool) {
+	n, b, ok := readUvarint(b)
+	if !ok || n > uint64(len(b)) {
+		return nil, nil, false
+	}
+	return b[:n], b[n:], true
+}
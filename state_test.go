@@ -0,0 +1,114 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+	"testing"
+)
+
+// allHashers returns a fresh hash.Hash for every built in algorithm.
+func allHashers() map[string]hash.Hash {
+	hashers, _ := hashersByName(registered())
+	return hashers
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data := make([]byte, 3<<20+12345)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	want := NewHasher(allHashers())
+	want.Write(data)
+
+	for _, split := range []int{0, 1, 1023, 1 << 20, 3<<20/2 + 7, len(data)} {
+		// Hash the first part, save the state and resume in a new reader.
+		hr := NewHashReader(bytes.NewReader(data[:split]), allHashers())
+		if _, err := io.Copy(io.Discard, hr); err != nil {
+			t.Fatalf("io.Copy(): %v", err)
+		}
+		state, err := hr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("HashReader.MarshalBinary() at %d: %v", split, err)
+		}
+
+		resumed := NewHashReader(bytes.NewReader(data[split:]), allHashers())
+		if err := resumed.UnmarshalBinary(state); err != nil {
+			t.Fatalf("HashReader.UnmarshalBinary() at %d: %v", split, err)
+		}
+		if got := resumed.BytesRead(); got != int64(split) {
+			t.Errorf("HashReader.BytesRead() after UnmarshalBinary got: %d, wanted %d", got, split)
+		}
+		if _, err := io.Copy(io.Discard, resumed); err != nil {
+			t.Fatalf("io.Copy(): %v", err)
+		}
+		for _, name := range registered() {
+			if got, w := resumed.HexHash(name), want.HexHash(name); got != w {
+				t.Errorf("resumed at %d, HashReader.HexHash(%s) got: %q, wanted %q", split, name, got, w)
+			}
+		}
+
+		// The same through a pipelined HashWriter.
+		hw := NewWriter(nil, WithHasher(SHA256, NewTreeHash()), WithPipelining())
+		hw.Write(data[:split])
+		if state, err = hw.MarshalBinary(); err != nil {
+			t.Fatalf("HashWriter.MarshalBinary() at %d: %v", split, err)
+		}
+		hw = NewWriter(nil, WithHasher(SHA256, NewTreeHash()), WithPipelining())
+		if err := hw.UnmarshalBinary(state); err != nil {
+			t.Fatalf("HashWriter.UnmarshalBinary() at %d: %v", split, err)
+		}
+		hw.Write(data[split:])
+		if got, w := hw.HexHash(SHA256), want.HexHash(SHA256Tree); got != w || hw.BytesWritten() != int64(len(data)) {
+			t.Errorf("resumed at %d, HashWriter.HexHash() got: %q after %d bytes, wanted %q", split, got, hw.BytesWritten(), w)
+		}
+	}
+}
+
+func TestMarshalBinaryErrors(t *testing.T) {
+	boom := errors.New("boom")
+	hr := NewHashReader(&errReader{[]byte("partial"), boom}, StdCryptoHashes())
+	io.Copy(io.Discard, hr)
+	if _, err := hr.MarshalBinary(); !errors.Is(err, ErrStreamFailed) || !errors.Is(err, boom) {
+		t.Errorf("HashReader.MarshalBinary() after error got: %v, wanted %v wrapping %v", err, ErrStreamFailed, boom)
+	}
+
+	hw := NewHasher(map[string]hash.Hash{"etag": NewS3ETag(0)})
+	if _, err := hw.MarshalBinary(); err == nil {
+		t.Errorf("HashWriter.MarshalBinary() with an S3ETag got no error")
+	}
+
+	hw = NewHasher(StdCryptoHashes())
+	hw.WriteString("hello")
+	state, err := hw.MarshalBinary()
+	if err != nil {
+		t.Fatalf("HashWriter.MarshalBinary(): %v", err)
+	}
+	for _, tc := range []struct {
+		desc    string
+		hashers map[string]hash.Hash
+		state   []byte
+	}{
+		{"fewer hashes", map[string]hash.Hash{SHA256: newHash256()}, state},
+		{"other names", map[string]hash.Hash{"a": newHash256(), "b": newHash256(), "c": newHash256()}, state},
+		{"truncated", StdCryptoHashes(), state[:len(state)-1]},
+		{"trailing data", StdCryptoHashes(), append(bytes.Clone(state), 0)},
+		{"garbage", StdCryptoHashes(), []byte("garbage")},
+	} {
+		if err := NewHasher(tc.hashers).UnmarshalBinary(tc.state); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("HashWriter.UnmarshalBinary(%s) got: %v, wanted %v", tc.desc, err, ErrInvalidState)
+		}
+	}
+
+	// Swapped algorithms are caught by the hashes themselves.
+	swapped := map[string]hash.Hash{MD5: newHash256(), SHA1: newHash256(), SHA256: newHash256()}
+	if err := NewHasher(swapped).UnmarshalBinary(state); err == nil {
+		t.Errorf("HashWriter.UnmarshalBinary() into other algorithms got no error")
+	}
+}
+
+func newHash256() hash.Hash {
+	h, _ := newHash(SHA256)
+	return h
+}
@@ -0,0 +1,46 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// summary formats n and the digests of hashers for String. Once the stream has
+// failed the error is shown instead of the digests.
+func summary(n int64, hashers map[string]hash.Hash, err error) string {
+	var b strings.Builder
+	b.WriteString("bytes=")
+	b.WriteString(strconv.FormatInt(n, 10))
+
+	if err != nil {
+		b.WriteString(" error=")
+		b.WriteString(strconv.Quote(err.Error()))
+		return b.String()
+	}
+
+	for _, name := range sortedNames(hashers) {
+		b.WriteByte(' ')
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(hex.EncodeToString(hashers[name].Sum(nil)))
+	}
+	return b.String()
+}
+
+// String returns the number of bytes read and every hex encoded digest,
+// ordered by name, in the form "bytes=110 md5=31ee... sha256=535a...". If the
+// wrapped io.Reader returned an error other than io.EOF, the error is included
+// instead of the digests.
+func (h *HashReader) String() string {
+	return summary(h.n, h.hashers, h.err)
+}
+
+// String returns the number of bytes written and every hex encoded digest,
+// ordered by name, in the form "bytes=110 md5=31ee... sha256=535a...". If the
+// wrapped io.Writer returned an error, the error is included instead of the
+// digests.
+func (h *HashWriter) String() string {
+	return summary(h.n, h.hashers, h.err)
+}
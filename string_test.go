@@ -0,0 +1,28 @@
+package hashio
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write(contents)
+	want := fmt.Sprintf("bytes=%d md5=%s sha1=%s sha256=%s", len(contents), dataFileMD5, dataFileSHA1, dataFileSHA256)
+	if got := fmt.Sprint(hw); got != want {
+		t.Errorf("HashWriter.String() got: %q, wanted %q", got, want)
+	}
+
+	hr := NewHashReader(&errReader{[]byte("abc"), errors.New("boom")}, StdCryptoHashes())
+	ioutil.ReadAll(hr)
+	if got, want := hr.String(), `bytes=3 error="boom"`; got != want {
+		t.Errorf("HashReader.String() after an error got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,169 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// treeHashLeafSize is the size of the leaves of a tree hash, 1 MiB.
+const treeHashLeafSize = 1 << 20
+
+// TreeHash is a hash.Hash computing the SHA-256 tree hash used by Amazon S3
+// Glacier to verify archive uploads (the x-amz-sha256-tree-hash header): the
+// data is split into 1 MiB leaves which are hashed with SHA-256, then
+// adjacent digests are concatenated and hashed pairwise, level by level, an
+// odd digest at the end of a level moving up unchanged, until one digest
+// remains. Data of at most 1 MiB has its plain SHA-256 digest as tree hash.
+//
+// It is available under the name SHA256Tree, so it can be computed alongside
+// the linear SHA-256 digest Glacier also requires.
+type TreeHash struct {
+	leaf  hash.Hash // SHA-256 of the current leaf
+	n     int       // bytes written to the current leaf
+	stack [][sha256.Size]byte
+	count uint64 // number of completed leaves
+}
+
+// NewTreeHash returns a new TreeHash.
+func NewTreeHash() *TreeHash {
+	return &TreeHash{leaf: sha256.New()}
+}
+
+func newTreeHash() hash.Hash { return NewTreeHash() }
+
+// Write adds p to the running tree hash. It never returns an error.
+func (t *TreeHash) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		k := min(len(p), treeHashLeafSize-t.n)
+		t.leaf.Write(p[:k])
+		t.n += k
+		p = p[k:]
+		if t.n == treeHashLeafSize {
+			var sum [sha256.Size]byte
+			t.leaf.Sum(sum[:0])
+			t.push(sum)
+			t.leaf.Reset()
+			t.n = 0
+		}
+	}
+	return written, nil
+}
+
+// push adds the digest of a completed leaf, combining every pair of subtrees
+// it completes. Combining complete subtrees as soon as possible gives the same
+// tree as combining whole levels pairwise.
+func (t *TreeHash) push(sum [sha256.Size]byte) {
+	t.count++
+	for c := t.count; c&1 == 0; c >>= 1 {
+		sum = treeNode(t.stack[len(t.stack)-1], sum)
+		t.stack = t.stack[:len(t.stack)-1]
+	}
+	t.stack = append(t.stack, sum)
+}
+
+// treeNode returns the digest of the node with children l and r.
+func treeNode(l, r [sha256.Size]byte) [sha256.Size]byte {
+	var b [2 * sha256.Size]byte
+	copy(b[:], l[:])
+	copy(b[sha256.Size:], r[:])
+	return sha256.Sum256(b[:])
+}
+
+// Sum appends the tree hash of the data written so far to b and returns the
+// resulting slice. It does not change the underlying hash state.
+func (t *TreeHash) Sum(b []byte) []byte {
+	var sum [sha256.Size]byte
+	n := len(t.stack)
+	if t.n > 0 || t.count == 0 {
+		// The incomplete leaf is the rightmost subtree.
+		t.leaf.Sum(sum[:0])
+	} else {
+		n--
+		sum = t.stack[n]
+	}
+	sum = t.fold(sum, n)
+	return append(b, sum[:]...)
+}
+
+// fold combines sum, the rightmost subtree, with the first n subtrees on the
+// stack, from right to left.
+func (t *TreeHash) fold(sum [sha256.Size]byte, n int) [sha256.Size]byte {
+	for i := n - 1; i >= 0; i-- {
+		sum = treeNode(t.stack[i], sum)
+	}
+	return sum
+}
+
+// Reset resets the TreeHash to its initial state.
+func (t *TreeHash) Reset() {
+	t.leaf.Reset()
+	t.n = 0
+	t.stack = t.stack[:0]
+	t.count = 0
+}
+
+// Size returns the number of bytes Sum appends, sha256.Size.
+func (t *TreeHash) Size() int { return sha256.Size }
+
+// BlockSize returns the SHA-256 block size.
+func (t *TreeHash) BlockSize() int { return sha256.BlockSize }
+
+const treeHashMagic = "treehash\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler, saving the state of t so
+// hashing can be resumed later with UnmarshalBinary.
+func (t *TreeHash) MarshalBinary() ([]byte, error) {
+	leaf, err := t.leaf.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b := append([]byte(treeHashMagic), make([]byte, 16)...)
+	binary.BigEndian.PutUint64(b[len(treeHashMagic):], t.count)
+	binary.BigEndian.PutUint64(b[len(treeHashMagic)+8:], uint64(t.n))
+	for _, sum := range t.stack {
+		b = append(b, sum[:]...)
+	}
+	return append(b, leaf...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a state
+// saved by MarshalBinary.
+func (t *TreeHash) UnmarshalBinary(b []byte) error {
+	if len(b) < len(treeHashMagic)+16 || string(b[:len(treeHashMagic)]) != treeHashMagic {
+		return ErrInvalidState
+	}
+	b = b[len(treeHashMagic):]
+	count, n := binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])
+	b = b[16:]
+	// The stack holds one complete subtree per bit set in count.
+	depth := bits.OnesCount64(count)
+	if n >= treeHashLeafSize || len(b) < depth*sha256.Size {
+		return ErrInvalidState
+	}
+	stack := make([][sha256.Size]byte, depth)
+	for i := range stack {
+		b = b[copy(stack[i][:], b):]
+	}
+	if err := t.leaf.(encoding.BinaryUnmarshaler).UnmarshalBinary(b); err != nil {
+		return err
+	}
+	t.count, t.n, t.stack = count, int(n), stack
+	return nil
+}
+
+// Clone implements hash.Cloner, returning a TreeHash with an independent copy
+// of the state of t.
+func (t *TreeHash) Clone() (hash.Cloner, error) {
+	leaf, err := t.leaf.(hash.Cloner).Clone()
+	if err != nil {
+		return nil, err
+	}
+	c := *t
+	c.leaf = leaf
+	c.stack = append([][sha256.Size]byte(nil), t.stack...)
+	return &c, nil
+}
@@ -0,0 +1,61 @@
+package hashio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func TestTreeHash(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want string
+	}{
+		{0, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{1, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"},
+		{1 << 20, "631b84027d6b9e52b539c4e8373622d23032dfadc64d60af87339c9037e4f769"},
+		{1<<20 + 1, "a9c574ce937d2371daf87cdd0e75396b096d7f74a66c764e4c656bd12b2e7bf9"},
+		{3 << 20, "2e7d51c0ffe06ce95fe74beed9a4ab35d18837f4ab2a9f4f066a60359eb999a1"},
+		{5 << 20, "1eccd0ca741241ce114bde6d24a8de044ccee139ef6651bec9006d2f558751ef"},
+		{7<<20 + 5, "2a51a69421e27f227c8524ae06fb6c3e42e0f0d4ffed17f1fc6cf12c8978e4e6"},
+	} {
+		data := make([]byte, tc.n)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+
+		h := NewHasher(map[string]hash.Hash{SHA256: sha256.New(), SHA256Tree: NewTreeHash()})
+		// Write in uneven pieces to cross leaf boundaries.
+		for p := data; len(p) > 0; {
+			k := min(len(p), 300000)
+			h.Write(p[:k])
+			p = p[k:]
+		}
+		if got := h.HexHash(SHA256Tree); got != tc.want {
+			t.Errorf("HexHash(sha256-tree) of %d bytes got: %q, wanted %q", tc.n, got, tc.want)
+		}
+		if tc.n <= 1<<20 && h.HexHash(SHA256) != tc.want {
+			t.Errorf("HexHash(sha256-tree) of %d bytes differs from the SHA-256 digest", tc.n)
+		}
+	}
+}
+
+func TestTreeHashByName(t *testing.T) {
+	sums, err := HashBytes([]byte("abc"), SHA256Tree)
+	if err != nil {
+		t.Fatalf("HashBytes(sha256-tree): %v", err)
+	}
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := hex.EncodeToString(sums[SHA256Tree]); got != want {
+		t.Errorf("HashBytes(sha256-tree) got: %q, wanted %q", got, want)
+	}
+
+	tr := NewTreeHash()
+	tr.Write(make([]byte, 3<<20))
+	tr.Reset()
+	tr.Write([]byte("abc"))
+	if got := hex.EncodeToString(tr.Sum(nil)); got != want {
+		t.Errorf("TreeHash.Sum() after Reset got: %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package hashio
+
+import (
+	"encoding"
+	"fmt"
+	"hash"
+)
+
+// WithTruncation truncates every digest of the hash identified by name to its
+// first n bytes, as in SHA-256/128 identifiers, so that Hash, HexHash, Sums,
+// Verify and the other accessors all agree on the truncated form. Size of the
+// hash reports n. An n no smaller than the size of the digest has no effect.
+//
+// name must be added by another option, before or after WithTruncation, and
+// n must be positive, or NewReader and NewWriter panic. The digest is
+// truncated after any suffix set by WithSuffix was hashed.
+func WithTruncation(name string, n int) Option {
+	return func(c *config) {
+		if c.truncations == nil {
+			c.truncations = make(map[string]int)
+		}
+		c.truncations[name] = n
+	}
+}
+
+// applyTruncations wraps the hashes of c.hashers given a truncation in a
+// truncatedHash.
+func (c *config) applyTruncations() {
+	for name, n := range c.truncations {
+		h, ok := c.hashers[name]
+		if !ok {
+			panic(fmt.Sprintf("hashio: truncation of unknown hash %q", name))
+		}
+		if n <= 0 {
+			panic(fmt.Sprintf("hashio: truncation of the %s hash to %d bytes", name, n))
+		}
+		if n < h.Size() {
+			c.hashers[name] = &truncatedHash{Hash: h, n: n}
+		}
+	}
+}
+
+// truncatedHash is a hash.Hash whose digests are truncated to n bytes.
+type truncatedHash struct {
+	hash.Hash
+	n int
+}
+
+func (t *truncatedHash) Sum(b []byte) []byte {
+	return t.Hash.Sum(b)[:len(b)+t.n]
+}
+
+func (t *truncatedHash) Size() int { return t.n }
+
+// MarshalBinary implements encoding.BinaryMarshaler if the truncated hash
+// does.
+func (t *truncatedHash) MarshalBinary() ([]byte, error) {
+	m, ok := t.Hash.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement encoding.BinaryMarshaler", t.Hash)
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler if the truncated hash
+// does.
+func (t *truncatedHash) UnmarshalBinary(data []byte) error {
+	u, ok := t.Hash.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("hashio: %T does not implement encoding.BinaryUnmarshaler", t.Hash)
+	}
+	return u.UnmarshalBinary(data)
+}
+
+// Clone implements hash.Cloner if the truncated hash does.
+func (t *truncatedHash) Clone() (hash.Cloner, error) {
+	c, ok := t.Hash.(hash.Cloner)
+	if !ok {
+		return nil, fmt.Errorf("hashio: %T does not implement hash.Cloner", t.Hash)
+	}
+	clone, err := c.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &truncatedHash{Hash: clone, n: t.n}, nil
+}
@@ -0,0 +1,70 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithTruncation(t *testing.T) {
+	const (
+		truncated = "2cf24dba5fb0a30e26e83b2ac5b9e29e" // first 16 bytes of sha256("hello")
+		md5       = "5d41402abc4b2a76b9719d911017c592" // md5("hello")
+	)
+	r := NewReader(strings.NewReader("hello"), WithTruncation(SHA256, 16), WithSHA256(), WithMD5(), WithTruncation(MD5, 32))
+	io.ReadAll(r)
+	if got := r.HexHash(SHA256); got != truncated {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", got, truncated)
+	}
+	if got := hex.EncodeToString(r.Hash(SHA256, []byte("x"))); got != "78"+truncated {
+		t.Errorf("HashReader.Hash(sha256, x) got: %q, wanted %q", got, "78"+truncated)
+	}
+	if got := r.HexHash(MD5); got != md5 {
+		t.Errorf("HashReader.HexHash(md5) got: %q, wanted %q", got, md5)
+	}
+	if got := r.HexSums()[SHA256]; got != truncated {
+		t.Errorf("HashReader.HexSums()[sha256] got: %q, wanted %q", got, truncated)
+	}
+	if ok, err := r.VerifyHex(SHA256, truncated); !ok || err != nil {
+		t.Errorf("HashReader.VerifyHex(sha256, %q) got: %v, %v, wanted true, nil", truncated, ok, err)
+	}
+	full := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if ok, _ := r.VerifyHex(SHA256, full); ok {
+		t.Errorf("HashReader.VerifyHex(sha256, %q) got: true, wanted false", full)
+	}
+
+	state, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("HashReader.MarshalBinary(): %v", err)
+	}
+	r.Reset(strings.NewReader(""))
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatalf("HashReader.UnmarshalBinary(): %v", err)
+	}
+	if got := r.Snapshot()[SHA256]; hex.EncodeToString(got) != truncated {
+		t.Errorf("HashReader.Snapshot()[sha256] after UnmarshalBinary got: %x, wanted %s", got, truncated)
+	}
+}
+
+func TestWithTruncationSalted(t *testing.T) {
+	w := NewWriter(nil, WithSHA256(), WithPrefix(SHA256, []byte("v1")), WithTruncation(SHA256, 10), WithPipelining())
+	w.WriteString("hello")
+	// First 10 bytes of sha256("v1hello").
+	if got, want := w.HexHash(SHA256), "43b023cea99aa9a167f5"; got != want {
+		t.Errorf("HashWriter.HexHash(sha256) got: %q, wanted %q", got, want)
+	}
+}
+
+func TestWithTruncationInvalid(t *testing.T) {
+	for _, opt := range []Option{WithTruncation("missing", 4), WithTruncation(SHA256, 0)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewWriter(WithTruncation) didn't panic")
+				}
+			}()
+			NewWriter(nil, WithSHA256(), opt)
+		}()
+	}
+}
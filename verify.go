@@ -0,0 +1,58 @@
+package hashio
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// verifyHex decodes expectedHex and compares it with sum in constant time.
+func verifyHex(sum []byte, expectedHex string) (bool, error) {
+	expected, err := hex.DecodeString(expectedHex)
+	if err != nil {
+		return false, fmt.Errorf("hashio: invalid hex digest: %w", err)
+	}
+	return subtle.ConstantTimeCompare(sum, expected) == 1, nil
+}
+
+// Verify reports whether the hash identified by name equals expected. The
+// comparison is done in constant time. An error is returned, along with false,
+// in the same cases LookupHash returns one.
+func (h *HashReader) Verify(name string, expected []byte) (bool, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(sum, expected) == 1, nil
+}
+
+// VerifyHex is like Verify but takes the expected digest as a hex encoded
+// string. Both upper and lower case hex are accepted.
+func (h *HashReader) VerifyHex(name, expectedHex string) (bool, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return false, err
+	}
+	return verifyHex(sum, expectedHex)
+}
+
+// Verify reports whether the hash identified by name equals expected. The
+// comparison is done in constant time. An error is returned, along with false,
+// in the same cases LookupHash returns one.
+func (h *HashWriter) Verify(name string, expected []byte) (bool, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(sum, expected) == 1, nil
+}
+
+// VerifyHex is like Verify but takes the expected digest as a hex encoded
+// string. Both upper and lower case hex are accepted.
+func (h *HashWriter) VerifyHex(name, expectedHex string) (bool, error) {
+	sum, err := h.LookupHash(name)
+	if err != nil {
+		return false, err
+	}
+	return verifyHex(sum, expectedHex)
+}
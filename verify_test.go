@@ -0,0 +1,50 @@
+package hashio
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+	defer f.Close()
+
+	hr := NewHashReader(f, StdCryptoHashes())
+	if _, err := ioutil.ReadAll(hr); err != nil {
+		t.Fatalf("ioutil.ReadAll([from: %q]): %v", dataFile, err)
+	}
+
+	sum, _ := hex.DecodeString(dataFileSHA1)
+	if ok, err := hr.Verify(SHA1, sum); !ok || err != nil {
+		t.Errorf("HashReader.Verify(sha1) got: %v, %v, wanted true, nil", ok, err)
+	}
+	if ok, err := hr.Verify(SHA1, sum[1:]); ok || err != nil {
+		t.Errorf("HashReader.Verify(sha1, truncated) got: %v, %v, wanted false, nil", ok, err)
+	}
+	if ok, err := hr.VerifyHex(SHA256, strings.ToUpper(dataFileSHA256)); !ok || err != nil {
+		t.Errorf("HashReader.VerifyHex(sha256) got: %v, %v, wanted true, nil", ok, err)
+	}
+	if ok, err := hr.VerifyHex(SHA256, dataFileMD5); ok || err != nil {
+		t.Errorf("HashReader.VerifyHex(sha256, md5 digest) got: %v, %v, wanted false, nil", ok, err)
+	}
+	if _, err := hr.VerifyHex(SHA256, "not hex"); err == nil {
+		t.Errorf("HashReader.VerifyHex(sha256, \"not hex\") got: nil error, wanted an error")
+	}
+
+	hw := NewHashWriter(ioutil.Discard, StdCryptoHashes())
+	hw.Write([]byte("hello I am happy"))
+	if ok, err := hw.VerifyHex(SHA256, "1963f25b4f1f410e5702a9bcb2d44a44a43aaea0ef4f946ddb24c1472155a13a"); !ok || err != nil {
+		t.Errorf("HashWriter.VerifyHex(sha256) got: %v, %v, wanted true, nil", ok, err)
+	}
+	var unknown *UnknownHashError
+	if _, err := hw.Verify("sha-256", nil); !errors.As(err, &unknown) {
+		t.Errorf("HashWriter.Verify(sha-256) got error: %v, wanted *UnknownHashError", err)
+	}
+}
@@ -0,0 +1,166 @@
+package hashio
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ErrDigestMismatch is matched by errors.Is for every *DigestMismatchError.
+var ErrDigestMismatch = errors.New("hashio: digest mismatch")
+
+// DigestMismatchError describes a digest that did not have its expected value.
+type DigestMismatchError struct {
+	Name     string
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("hashio: %s digest mismatch: got %x, expected %x", e.Name, e.Actual, e.Expected)
+}
+
+// Is reports whether target is ErrDigestMismatch.
+func (e *DigestMismatchError) Is(target error) bool {
+	return target == ErrDigestMismatch
+}
+
+// VerifyingReader is a HashReader that checks its digests against expected
+// values when the wrapped io.Reader reaches io.EOF.
+type VerifyingReader struct {
+	*HashReader
+	expected map[string][]byte
+	names    []string
+	done     error // the error returned at EOF once it has been determined
+}
+
+// NewVerifyingReader returns a VerifyingReader that reads from r and hashes the
+// data with the algorithms named by the keys of expected, such as "sha256".
+// When r returns io.EOF, Read instead returns a *DigestMismatchError if any
+// digest does not equal its expected value, so that copying the data with
+// io.Copy or similar fails without any further checks by the caller.
+//
+// An *UnknownHashError is returned if a key of expected is not a known
+// algorithm, and an error if expected is empty, since nothing would be
+// verified.
+func NewVerifyingReader(r io.Reader, expected map[string][]byte) (*VerifyingReader, error) {
+	if len(expected) == 0 {
+		return nil, errors.New("hashio: no expected digests to verify")
+	}
+	hashers := make(map[string]hash.Hash, len(expected))
+	for name := range expected {
+		h, err := newHash(name)
+		if err != nil {
+			return nil, err
+		}
+		hashers[name] = h
+	}
+
+	return &VerifyingReader{
+		HashReader: NewHashReader(r, hashers),
+		expected:   expected,
+		names:      sortedNames(hashers),
+	}, nil
+}
+
+// Read reads from the wrapped io.Reader like HashReader.Read. When the wrapped
+// reader returns io.EOF, the digests are checked and a *DigestMismatchError for
+// the first mismatching digest (in name order) is returned in place of io.EOF.
+// Subsequent calls return the same error.
+func (v *VerifyingReader) Read(p []byte) (int, error) {
+	if v.done != nil {
+		return 0, v.done
+	}
+
+	n, err := v.HashReader.Read(p)
+	if err == io.EOF {
+		v.done = v.check()
+		err = v.done
+	}
+	return n, err
+}
+
+// WriteTo implements io.WriterTo like HashReader.WriteTo, but checks the
+// digests once the wrapped io.Reader is exhausted and returns a
+// *DigestMismatchError if any digest doesn't match.
+func (v *VerifyingReader) WriteTo(w io.Writer) (int64, error) {
+	if v.done != nil {
+		if v.done == io.EOF {
+			return 0, nil
+		}
+		return 0, v.done
+	}
+
+	n, err := v.HashReader.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	v.done = v.check()
+	if v.done != io.EOF {
+		return n, v.done
+	}
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader like HashReader.ReadByte, but returns a
+// *DigestMismatchError in place of io.EOF like Read, so that consumers such as
+// compress/flate, which read byte at a time through io.ByteReader, see it.
+func (v *VerifyingReader) ReadByte() (byte, error) {
+	if v.done != nil {
+		return 0, v.done
+	}
+
+	b, err := v.HashReader.ReadByte()
+	if err == io.EOF {
+		v.done = v.check()
+		err = v.done
+	}
+	return b, err
+}
+
+// ReadRune implements io.RuneReader like HashReader.ReadRune, but returns a
+// *DigestMismatchError in place of io.EOF like Read.
+func (v *VerifyingReader) ReadRune() (rune, int, error) {
+	if v.done != nil {
+		return 0, 0, v.done
+	}
+
+	r, size, err := v.HashReader.ReadRune()
+	if err == io.EOF {
+		v.done = v.check()
+		err = v.done
+	}
+	return r, size, err
+}
+
+// Reset resets v like HashReader.Reset to verify the data read from r against
+// the same expected digests.
+func (v *VerifyingReader) Reset(r io.Reader) {
+	v.HashReader.Reset(r)
+	v.done = nil
+}
+
+// AddHasher returns an error: the hashes of v are those of its expected
+// digests.
+func (v *VerifyingReader) AddHasher(name string, hasher hash.Hash) error {
+	return fmt.Errorf("hashio: can't attach hash %q to a VerifyingReader", name)
+}
+
+// RemoveHasher returns an error: the hashes of v are needed to check its
+// expected digests at EOF.
+func (v *VerifyingReader) RemoveHasher(name string) ([]byte, error) {
+	return nil, fmt.Errorf("hashio: can't detach hash %q from a VerifyingReader", name)
+}
+
+// check compares every digest with its expected value.
+func (v *VerifyingReader) check() error {
+	for _, name := range v.names {
+		sum := v.hashers[name].Sum(nil)
+		if subtle.ConstantTimeCompare(sum, v.expected[name]) != 1 {
+			return &DigestMismatchError{Name: name, Expected: v.expected[name], Actual: sum}
+		}
+	}
+	return io.EOF
+}
@@ -0,0 +1,117 @@
+package hashio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVerifyingReader(t *testing.T) {
+	sha256Sum, _ := hex.DecodeString(dataFileSHA256)
+	md5Sum, _ := hex.DecodeString(dataFileMD5)
+
+	f, err := os.Open(dataFile)
+	if err != nil {
+		t.Fatalf("Unable to open %q: %v", dataFile, err)
+	}
+	defer f.Close()
+
+	vr, err := NewVerifyingReader(f, map[string][]byte{SHA256: sha256Sum, MD5: md5Sum})
+	if err != nil {
+		t.Fatalf("NewVerifyingReader: %v", err)
+	}
+	if _, err := io.Copy(ioutil.Discard, vr); err != nil {
+		t.Errorf("io.Copy from VerifyingReader with correct digests: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+	contents[0] ^= 0xff
+
+	vr, err = NewVerifyingReader(bytes.NewReader(contents), map[string][]byte{SHA256: sha256Sum, MD5: md5Sum})
+	if err != nil {
+		t.Fatalf("NewVerifyingReader: %v", err)
+	}
+	_, err = io.Copy(ioutil.Discard, vr)
+	var mismatch *DigestMismatchError
+	if !errors.Is(err, ErrDigestMismatch) || !errors.As(err, &mismatch) {
+		t.Fatalf("io.Copy from VerifyingReader with corrupted data got error: %v, wanted *DigestMismatchError", err)
+	}
+	if mismatch.Name != MD5 {
+		t.Errorf("DigestMismatchError.Name got: %q, wanted %q", mismatch.Name, MD5)
+	}
+	if _, err := vr.Read(make([]byte, 1)); err != mismatch {
+		t.Errorf("VerifyingReader.Read after mismatch got error: %v, wanted the same mismatch", err)
+	}
+
+	if _, err := NewVerifyingReader(f, map[string][]byte{"sha-256": sha256Sum}); err == nil {
+		t.Errorf("NewVerifyingReader(sha-256) got: nil error, wanted an error")
+	}
+	if _, err := vr.RemoveHasher(MD5); err == nil {
+		t.Errorf("VerifyingReader.RemoveHasher(md5) got: nil error, wanted an error")
+	}
+	if err := vr.AddHasher(SHA1, sha1.New()); err == nil {
+		t.Errorf("VerifyingReader.AddHasher(sha1) got: nil error, wanted an error")
+	}
+	if _, err := NewVerifyingReader(f, nil); err == nil {
+		t.Errorf("NewVerifyingReader(nil) got: nil error, wanted an error")
+	}
+}
+
+func TestVerifyingReaderByteReader(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("hello world"))
+	zw.Close()
+	compressed := buf.Bytes()
+	wrong := make([]byte, 32)
+
+	// gzip reads through VerifyingReader.ReadByte, since it's an io.ByteReader.
+	vr, err := NewVerifyingReader(bytes.NewReader(compressed), map[string][]byte{SHA256: wrong})
+	if err != nil {
+		t.Fatalf("NewVerifyingReader: %v", err)
+	}
+	zr, err := gzip.NewReader(vr)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	if _, err := io.ReadAll(zr); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("io.ReadAll from gzip.Reader over VerifyingReader got error: %v, wanted ErrDigestMismatch", err)
+	}
+
+	vr.Reset(strings.NewReader("héllo"))
+	var runes []rune
+	for {
+		r, _, err := vr.ReadRune()
+		if err != nil {
+			if !errors.Is(err, ErrDigestMismatch) {
+				t.Errorf("VerifyingReader.ReadRune() at EOF got error: %v, wanted ErrDigestMismatch", err)
+			}
+			break
+		}
+		runes = append(runes, r)
+	}
+	if string(runes) != "héllo" {
+		t.Errorf("VerifyingReader.ReadRune() after Reset got: %q, wanted %q", string(runes), "héllo")
+	}
+	if _, err := vr.ReadByte(); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("VerifyingReader.ReadByte() after mismatch got error: %v, wanted ErrDigestMismatch", err)
+	}
+
+	sum := sha256.Sum256(compressed)
+	vr, _ = NewVerifyingReader(bytes.NewReader(compressed), map[string][]byte{SHA256: sum[:]})
+	zr, _ = gzip.NewReader(vr)
+	if got, err := io.ReadAll(zr); err != nil || string(got) != "hello world" {
+		t.Errorf("io.ReadAll from gzip.Reader over VerifyingReader with correct digest got: %q, %v, wanted %q, nil", got, err, "hello world")
+	}
+}
@@ -0,0 +1,95 @@
+package hashio
+
+import "io"
+
+// WriteTo implements io.WriterTo so that io.Copy from a HashReader keeps the
+// wrapped io.Reader's own WriteTo fast path (e.g. *bytes.Reader or
+// *bufio.Reader) when it has one. The data is still passed to every hash as it
+// flows to w. Readers without WriteTo are copied through a pooled buffer whose
+// size can be set with WithChunkSize or SetBufferSize.
+//
+// Data consumed from the wrapped reader is hashed even if w fails to accept
+// it, as it would be by Read. When the wrapped reader's WriteTo is used, only
+// the bytes w accepts are hashed, since the wrapped reader keeps the rest for
+// the next read; the same goes for bytes peeked by ReadRune. Errors from w
+// are returned but, unlike errors from the wrapped reader, are not recorded
+// by Err.
+func (h *HashReader) WriteTo(w io.Writer) (int64, error) {
+	if h.finalized {
+		return 0, ErrFinalized
+	}
+
+	var written int64
+	if len(h.pending) > 0 {
+		p := h.pending
+		n, err := w.Write(p)
+		n, err = clampWrite(n, len(p), err)
+		h.hash(p[:n])
+		h.pending = p[n:]
+		if len(h.pending) == 0 {
+			h.pending = nil
+		}
+		written = int64(n)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+
+	wt, ok := h.r.(io.WriterTo)
+	if !ok {
+		// Hide h's own WriteTo so io.CopyBuffer doesn't call back into it.
+		size := h.chunk
+		if size <= 0 {
+			size = copyBufSize(defaultBufSize)
+		}
+		buf := getBuffer(size)
+		defer putBuffer(buf)
+		n, err := io.CopyBuffer(w, struct{ io.Reader }{h}, *buf)
+		return written + n, err
+	}
+
+	tw := &teeWriter{w: w, h: h}
+	n, err := wt.WriteTo(tw)
+	if err != tw.err {
+		h.setErr(err)
+	}
+	return written + n, err
+}
+
+// teeWriter writes to w and passes everything it is given to the hashes of h.
+type teeWriter struct {
+	w   io.Writer
+	h   *HashReader
+	err error // the last error returned by w
+}
+
+// Write hashes the bytes w accepted only: the wrapped reader advances past
+// those alone, and passes the others again on a later call. A short write is
+// reported as io.ErrShortWrite, so that the wrapped reader returns the error
+// of w.
+func (t *teeWriter) Write(p []byte) (int, error) {
+	n, err := t.w.Write(p)
+	n, err = clampWrite(n, len(p), err)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	t.h.hash(p[:n])
+	t.err = err
+	return n, err
+}
+
+// clampWrite returns the result n, err of a write of size bytes, with n
+// clamped to 0..size as HashWriter does, and errInvalidWrite if it was out of
+// range without an error.
+func clampWrite(n, size int, err error) (int, error) {
+	if n < 0 || n > size {
+		n = 0
+		if err == nil {
+			err = errInvalidWrite
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,117 @@
+package hashio
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"io/ioutil"
+	"testing"
+	"unicode/utf8"
+)
+
+// writeToCounter is a *bytes.Reader that records whether WriteTo was used.
+type writeToCounter struct {
+	*bytes.Reader
+	calls int
+}
+
+func (w *writeToCounter) WriteTo(dst io.Writer) (int64, error) {
+	w.calls++
+	return w.Reader.WriteTo(dst)
+}
+
+func TestHashReaderWriteTo(t *testing.T) {
+	contents, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile(%q): %v", dataFile, err)
+	}
+
+	src := &writeToCounter{Reader: bytes.NewReader(contents)}
+	hr := NewHashReader(src, StdCryptoHashes())
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, hr)
+	if err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	if src.calls != 1 {
+		t.Errorf("wrapped reader's WriteTo called %d times, wanted 1", src.calls)
+	}
+	if n != int64(len(contents)) || hr.BytesRead() != n || !bytes.Equal(buf.Bytes(), contents) {
+		t.Errorf("io.Copy copied %d bytes (BytesRead %d), wanted %d", n, hr.BytesRead(), len(contents))
+	}
+	if hash := hr.HexHash(SHA256); hash != dataFileSHA256 {
+		t.Errorf("HashReader.HexHash(sha256) got: %q, wanted %q", hash, dataFileSHA256)
+	}
+
+	// Readers without WriteTo fall back to Read.
+	hr = NewHashReader(&errReader{contents, io.EOF}, StdCryptoHashes())
+	if _, err := hr.WriteTo(ioutil.Discard); err != nil {
+		t.Fatalf("HashReader.WriteTo: %v", err)
+	}
+	if hash := hr.HexHash(SHA1); hash != dataFileSHA1 {
+		t.Errorf("HashReader.HexHash(sha1) got: %q, wanted %q", hash, dataFileSHA1)
+	}
+
+	// Destination errors are returned but don't poison the digests.
+	boom := errors.New("boom")
+	hr = NewHashReader(bufio.NewReader(bytes.NewReader(contents)), StdCryptoHashes())
+	if _, err := hr.WriteTo(&errWriter{10, boom}); err != boom {
+		t.Errorf("HashReader.WriteTo got error: %v, wanted %v", err, boom)
+	}
+	if err := hr.Err(); err != nil {
+		t.Errorf("HashReader.Err() after a destination error got: %v, wanted nil", err)
+	}
+}
+
+// limitedWriter writes at most n bytes per call to w, without an error,
+// breaking the io.Writer contract like shortWriter.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		p = p[:l.n]
+	}
+	return l.w.Write(p)
+}
+
+func TestHashReaderWriteToShortWrite(t *testing.T) {
+	data := []byte("\xe2\x82xabcdefghijklmnopqrstuvw")
+	want := sha256.Sum256(data)
+	for _, tc := range []struct {
+		name string
+		r    io.Reader
+		rune bool
+	}{
+		{"bytes.Reader", bytes.NewReader(data), false},
+		{"bufio.Reader", bufio.NewReader(bytes.NewReader(data)), false},
+		// ReadRune leaves the bytes after the invalid \xe2 pending.
+		{"pending", bytes.NewReader(data), true},
+	} {
+		hr := NewHashReader(tc.r, map[string]hash.Hash{SHA256: sha256.New()})
+		var buf bytes.Buffer
+		if tc.rune {
+			if r, size, err := hr.ReadRune(); r != utf8.RuneError || size != 1 || err != nil {
+				t.Fatalf("%s: HashReader.ReadRune() got: %q, %d, %v, wanted an invalid rune of size 1", tc.name, r, size, err)
+			}
+			buf.WriteByte(data[0])
+		}
+		if _, err := hr.WriteTo(limitedWriter{&buf, 1}); err != io.ErrShortWrite {
+			t.Errorf("%s: HashReader.WriteTo() to a short writer got: %v, wanted %v", tc.name, err, io.ErrShortWrite)
+		}
+		if _, err := io.Copy(&buf, hr); err != nil {
+			t.Fatalf("%s: io.Copy(): %v", tc.name, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) || hr.BytesRead() != int64(len(data)) {
+			t.Errorf("%s: copied %q (BytesRead %d), wanted %q", tc.name, buf.Bytes(), hr.BytesRead(), data)
+		}
+		if got := hr.Sums()[SHA256]; !bytes.Equal(got, want[:]) {
+			t.Errorf("%s: sha256 got: %x, wanted %x", tc.name, got, want)
+		}
+	}
+}
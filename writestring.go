@@ -0,0 +1,35 @@
+package hashio
+
+import (
+	"io"
+	"unsafe"
+)
+
+// WriteString implements io.StringWriter. s is passed to the wrapped
+// io.Writer's WriteString method when it has one, and is fed to the hashes
+// without being copied into a new []byte.
+//
+// As with Write, only the bytes the wrapped io.Writer accepted are hashed, and
+// errors are recorded as by Write.
+func (h *HashWriter) WriteString(s string) (int, error) {
+	if h.finalized {
+		return 0, ErrFinalized
+	}
+
+	// hash.Hash.Write must not modify or retain p, so a read-only view of s's
+	// bytes is safe to hand to the hashes.
+	p := unsafe.Slice(unsafe.StringData(s), len(s))
+
+	if h.dst == nil {
+		return h.accept(p, len(p), nil)
+	}
+	var n int
+	var err error
+	if sw, ok := h.dst.(io.StringWriter); ok {
+		n, err = sw.WriteString(s)
+	} else {
+		// The wrapped io.Writer is under no such obligation.
+		n, err = h.dst.Write([]byte(s))
+	}
+	return h.accept(p, n, err)
+}
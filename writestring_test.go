@@ -0,0 +1,69 @@
+package hashio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// onlyWriter hides every method of the wrapped io.Writer except Write.
+type onlyWriter struct {
+	io.Writer
+}
+
+func TestHashWriterWriteString(t *testing.T) {
+	const input = "The quick brown fox jumps over the lazy dog"
+	const want = "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592"
+
+	for _, tc := range []struct {
+		desc string
+		dst  func(*bytes.Buffer) io.Writer
+	}{
+		{"StringWriter", func(b *bytes.Buffer) io.Writer { return b }},
+		{"Writer", func(b *bytes.Buffer) io.Writer { return onlyWriter{b} }},
+	} {
+		var buf bytes.Buffer
+		hw := NewWriter(tc.dst(&buf), WithSHA256())
+		for _, s := range strings.SplitAfter(input, " ") {
+			if _, err := hw.WriteString(s); err != nil {
+				t.Fatalf("%s: HashWriter.WriteString(%q): %v", tc.desc, s, err)
+			}
+		}
+		if got := buf.String(); got != input {
+			t.Errorf("%s: wrapped writer got: %q, wanted %q", tc.desc, got, input)
+		}
+		if got := hw.HexHash(SHA256); got != want {
+			t.Errorf("%s: HashWriter.HexHash(sha256) got: %q, wanted %q", tc.desc, got, want)
+		}
+		if got := hw.BytesWritten(); got != int64(len(input)) {
+			t.Errorf("%s: HashWriter.BytesWritten() got: %d, wanted %d", tc.desc, got, len(input))
+		}
+	}
+}
+
+func TestHashWriterWriteStringError(t *testing.T) {
+	wantErr := errors.New("disk full")
+	hw := NewWriter(&errWriter{limit: 3, err: wantErr}, WithSHA256())
+	n, err := hw.WriteString("abcdef")
+	if !errors.Is(err, wantErr) {
+		t.Errorf("HashWriter.WriteString() got error: %v, wanted %v", err, wantErr)
+	}
+	if n != 3 || hw.BytesWritten() != 3 {
+		t.Errorf("HashWriter.WriteString() got: %d (BytesWritten %d), wanted 3", n, hw.BytesWritten())
+	}
+	if !errors.Is(hw.Err(), wantErr) {
+		t.Errorf("HashWriter.Err() got: %v, wanted %v", hw.Err(), wantErr)
+	}
+}
+
+func BenchmarkHashWriterWriteString(b *testing.B) {
+	s := strings.Repeat("x", 4096)
+	hw := NewWriter(io.Discard, WithSHA256())
+	b.ReportAllocs()
+	b.SetBytes(int64(len(s)))
+	for i := 0; i < b.N; i++ {
+		hw.WriteString(s)
+	}
+}
@@ -0,0 +1,182 @@
+package hashio
+
+import (
+	"crypto/sha3"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/mikewiacek/hashio/blake3"
+)
+
+// XOF is a hash.Hash with an extendable output function, such as SHAKE128,
+// SHAKE256 and BLAKE3, whose output can be as long as needed. Sum appends a fixed size
+// default output; SumN any length.
+type XOF interface {
+	hash.Hash
+	// SumN appends n bytes of output for the data written so far to b and
+	// returns the resulting slice. It does not change the underlying hash
+	// state. Shorter outputs are prefixes of longer ones. A negative n
+	// appends nothing.
+	SumN(b []byte, n int) []byte
+}
+
+// shake adapts a sha3.SHAKE to the XOF interface.
+type shake struct {
+	s    *sha3.SHAKE
+	ctor func() *sha3.SHAKE
+	size int
+}
+
+// NewSHAKE128 returns a SHAKE128 XOF whose Sum appends 32 bytes, its output
+// length for 128-bit security.
+func NewSHAKE128() XOF {
+	return &shake{sha3.NewSHAKE128(), sha3.NewSHAKE128, 32}
+}
+
+// NewSHAKE256 returns a SHAKE256 XOF whose Sum appends 64 bytes, its output
+// length for 256-bit security.
+func NewSHAKE256() XOF {
+	return &shake{sha3.NewSHAKE256(), sha3.NewSHAKE256, 64}
+}
+
+func newSHAKE128() hash.Hash { return NewSHAKE128() }
+
+func newSHAKE256() hash.Hash { return NewSHAKE256() }
+
+func (s *shake) Write(p []byte) (int, error) { return s.s.Write(p) }
+
+func (s *shake) Sum(b []byte) []byte { return s.SumN(b, s.size) }
+
+func (s *shake) SumN(b []byte, n int) []byte {
+	if n <= 0 {
+		return b
+	}
+	// Reading squeezes the sponge, so read from a copy.
+	c, _ := s.clone()
+	b = append(b, make([]byte, n)...)
+	c.s.Read(b[len(b)-n:])
+	return b
+}
+
+func (s *shake) Reset()         { s.s.Reset() }
+func (s *shake) Size() int      { return s.size }
+func (s *shake) BlockSize() int { return s.s.BlockSize() }
+
+func (s *shake) MarshalBinary() ([]byte, error)    { return s.s.MarshalBinary() }
+func (s *shake) UnmarshalBinary(data []byte) error { return s.s.UnmarshalBinary(data) }
+
+func (s *shake) Clone() (hash.Cloner, error) { return s.clone() }
+
+// reader returns a reader of the output of s, from a copy of its state.
+func (s *shake) reader() (io.Reader, error) {
+	c, err := s.clone()
+	if err != nil {
+		return nil, err
+	}
+	return c.s, nil
+}
+
+func (s *shake) clone() (*shake, error) {
+	state, err := s.s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	c := &shake{s.ctor(), s.ctor, s.size}
+	if err := c.s.UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// XOFHash returns n bytes of output of the extendable output function
+// identified by name, such as SHAKE128, SHAKE256 or BLAKE3. It returns nil if name
+// doesn't identify an XOF in the hashers map passed to NewHashReader, or if n is
+// negative.
+//
+// If any call to Read returned an error (not including io.EOF), nil is
+// returned. See Err.
+func (h *HashReader) XOFHash(name string, n int) []byte {
+	if h.err != nil || n < 0 {
+		return nil
+	}
+	x, ok := unwrapHash(h.hashers[name]).(XOF)
+	if !ok {
+		return nil
+	}
+	h.finalize()
+	return x.SumN(nil, n)
+}
+
+// XOFHash returns n bytes of output of the extendable output function
+// identified by name, such as SHAKE128, SHAKE256 or BLAKE3. It returns nil if name
+// doesn't identify an XOF in the hashers map passed to NewHashWriter, or if n is
+// negative.
+//
+// If any call to Write returned an error, nil is returned. See Err.
+func (h *HashWriter) XOFHash(name string, n int) []byte {
+	if h.err != nil || n < 0 {
+		return nil
+	}
+	x, ok := unwrapHash(h.hashers[name]).(XOF)
+	if !ok {
+		return nil
+	}
+	h.finalize()
+	return x.SumN(nil, n)
+}
+
+// DigestReader returns an io.Reader streaming the output of the extendable
+// output function identified by name, SHAKE128, SHAKE256 or BLAKE3, for the
+// data read so far, to derive keys or identifiers of any length. Its first
+// bytes are the digest; it never runs out or fails. Later reads through h
+// don't change its output.
+//
+// An *UnknownHashError is returned if name isn't in the hashers map passed to
+// NewHashReader, and an error wrapping ErrStreamFailed if any call to Read
+// returned an error (not including io.EOF). An error is also returned if the
+// hash isn't one of those above.
+func (h *HashReader) DigestReader(name string) (io.Reader, error) {
+	hh, err := lookup(h.hashers, name)
+	if err != nil {
+		return nil, err
+	}
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	h.finalize()
+	return digestReader(name, hh)
+}
+
+// DigestReader returns an io.Reader streaming the output of the extendable
+// output function identified by name, SHAKE128, SHAKE256 or BLAKE3, for the
+// data written so far, to derive keys or identifiers of any length. Its first
+// bytes are the digest; it never runs out or fails. Later writes through h
+// don't change its output.
+//
+// An *UnknownHashError is returned if name isn't in the hashers map passed to
+// NewHashWriter, and an error wrapping ErrStreamFailed if any call to Write
+// returned an error. An error is also returned if the hash isn't one of those
+// above.
+func (h *HashWriter) DigestReader(name string) (io.Reader, error) {
+	hh, err := lookup(h.hashers, name)
+	if err != nil {
+		return nil, err
+	}
+	if h.err != nil {
+		return nil, streamFailed(h.err)
+	}
+	h.finalize()
+	return digestReader(name, hh)
+}
+
+// digestReader returns a reader of the output of h, identified by name.
+func digestReader(name string, h hash.Hash) (io.Reader, error) {
+	switch x := unwrapHash(h).(type) {
+	case *shake:
+		return x.reader()
+	case *blake3.Hasher:
+		return x.XOF(), nil
+	}
+	return nil, fmt.Errorf("hashio: %s is not an extendable output function", name)
+}
@@ -0,0 +1,120 @@
+package hashio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mikewiacek/hashio/blake3"
+)
+
+func TestSHA3(t *testing.T) {
+	want := map[string]string{
+		SHA3_384: "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25",
+		SHA3_512: "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+		SHAKE128: "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8",
+		SHAKE256: "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4",
+	}
+	w, err := NewWriterByNames(nil, SHA3_384, SHA3_512, SHAKE128, SHAKE256)
+	if err != nil {
+		t.Fatalf("NewWriterByNames(): %v", err)
+	}
+	w.WriteString("abc")
+	for name, sum := range want {
+		if got := w.HexHash(name); got != sum {
+			t.Errorf("HashWriter.HexHash(%s) got: %q, wanted %q", name, got, sum)
+		}
+	}
+}
+
+func TestXOFHash(t *testing.T) {
+	const want = "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc844c50af32acd3f2cdd066568706f509bc1bdde58295dae3f891a9a0fca5783789a41f8611214ce612394df286a62d1a2252aa94db9c538956c717dc2bed4f232a0294c85"
+
+	w := NewWriter(nil, WithHasher(SHAKE128, NewSHAKE128()), WithSHA256(), WithPipelining())
+	w.WriteString("ab")
+	// Taking an output must not disturb the hash.
+	w.XOFHash(SHAKE128, 10)
+	w.WriteString("c")
+	if got := hex.EncodeToString(w.XOFHash(SHAKE128, 100)); got != want {
+		t.Errorf("HashWriter.XOFHash(shake128, 100) got: %q, wanted %q", got, want)
+	}
+	if got := hex.EncodeToString(w.XOFHash(SHAKE128, 7)); got != want[:14] {
+		t.Errorf("HashWriter.XOFHash(shake128, 7) got: %q, wanted %q", got, want[:14])
+	}
+	if got := w.XOFHash(SHA256, 7); got != nil {
+		t.Errorf("HashWriter.XOFHash(sha256) got: %x, wanted nil", got)
+	}
+	if got := w.XOFHash("missing", 7); got != nil {
+		t.Errorf("HashWriter.XOFHash(missing) got: %x, wanted nil", got)
+	}
+	if got := w.XOFHash(SHAKE128, -1); got != nil {
+		t.Errorf("HashWriter.XOFHash(shake128, -1) got: %x, wanted nil", got)
+	}
+	for _, x := range []XOF{NewSHAKE128(), blake3.New()} {
+		if got := x.SumN([]byte("b"), -1); string(got) != "b" {
+			t.Errorf("%T.SumN(b, -1) got: %q, wanted %q", x, got, "b")
+		}
+	}
+
+	boom := errors.New("boom")
+	r := NewReader(&errReader{[]byte("abc"), boom}, WithHasher(SHAKE256, NewSHAKE256()))
+	ioutil.ReadAll(r)
+	if got := r.XOFHash(SHAKE256, 16); got != nil {
+		t.Errorf("HashReader.XOFHash() after error got: %x, wanted nil", got)
+	}
+}
+
+func TestDigestReader(t *testing.T) {
+	const want = "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e41385141204f329979fd3047a13c5657724ada64d2470157b3cdc288620944d78dbcddbd9"
+
+	w, err := NewWriterByNames(nil, SHAKE256, BLAKE3, SHA256)
+	if err != nil {
+		t.Fatalf("NewWriterByNames(): %v", err)
+	}
+	w.WriteString("abc")
+	shake, err := w.DigestReader(SHAKE256)
+	if err != nil {
+		t.Fatalf("HashWriter.DigestReader(shake256): %v", err)
+	}
+	b3, err := w.DigestReader(BLAKE3)
+	if err != nil {
+		t.Fatalf("HashWriter.DigestReader(blake3): %v", err)
+	}
+	b3Want := w.XOFHash(BLAKE3, 100)
+	// Later writes don't change the output of the readers.
+	w.WriteString("def")
+
+	out := make([]byte, 100)
+	for i := 0; i < len(out); i += 30 {
+		if _, err := io.ReadFull(shake, out[i:min(i+30, len(out))]); err != nil {
+			t.Fatalf("reading the shake256 digest: %v", err)
+		}
+	}
+	if got := hex.EncodeToString(out); got != want {
+		t.Errorf("HashWriter.DigestReader(shake256) read: %q, wanted %q", got, want)
+	}
+	if _, err := io.ReadFull(b3, out); err != nil {
+		t.Fatalf("reading the blake3 digest: %v", err)
+	}
+	if !bytes.Equal(out, b3Want) {
+		t.Errorf("HashWriter.DigestReader(blake3) read: %x, wanted %x", out, b3Want)
+	}
+
+	if _, err := w.DigestReader(SHA256); err == nil {
+		t.Errorf("HashWriter.DigestReader(sha256) got: nil error, wanted one")
+	}
+	var unknown *UnknownHashError
+	if _, err := w.DigestReader("missing"); !errors.As(err, &unknown) {
+		t.Errorf("HashWriter.DigestReader(missing) got: %v, wanted an *UnknownHashError", err)
+	}
+
+	boom := errors.New("boom")
+	r := NewReader(&errReader{[]byte("abc"), boom}, WithHasher(SHAKE256, NewSHAKE256()))
+	ioutil.ReadAll(r)
+	if _, err := r.DigestReader(SHAKE256); !errors.Is(err, ErrStreamFailed) {
+		t.Errorf("HashReader.DigestReader() after error got: %v, wanted %v", err, ErrStreamFailed)
+	}
+}
@@ -0,0 +1,287 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	stripeLen  = 64
+	secretLen  = 192
+	stripes    = (secretLen - stripeLen) / 8 // stripes per block
+	blockLen   = stripes * stripeLen
+	midSizeMax = 240
+
+	prime32_1 uint64 = 2654435761
+	prime32_2 uint64 = 2246822519
+	prime32_3 uint64 = 3266489917
+)
+
+// secret is the default XXH3 secret.
+var secret = [secretLen]byte{
+	0xb8, 0xfe, 0x6c, 0x39, 0x23, 0xa4, 0x4b, 0xbe, 0x7c, 0x01, 0x81, 0x2c, 0xf7, 0x21, 0xad, 0x1c,
+	0xde, 0xd4, 0x6d, 0xe9, 0x83, 0x90, 0x97, 0xdb, 0x72, 0x40, 0xa4, 0xa4, 0xb7, 0xb3, 0x67, 0x1f,
+	0xcb, 0x79, 0xe6, 0x4e, 0xcc, 0xc0, 0xe5, 0x78, 0x82, 0x5a, 0xd0, 0x7d, 0xcc, 0xff, 0x72, 0x21,
+	0xb8, 0x08, 0x46, 0x74, 0xf7, 0x43, 0x24, 0x8e, 0xe0, 0x35, 0x90, 0xe6, 0x81, 0x3a, 0x26, 0x4c,
+	0x3c, 0x28, 0x52, 0xbb, 0x91, 0xc3, 0x00, 0xcb, 0x88, 0xd0, 0x65, 0x8b, 0x1b, 0x53, 0x2e, 0xa3,
+	0x71, 0x64, 0x48, 0x97, 0xa2, 0x0d, 0xf9, 0x4e, 0x38, 0x19, 0xef, 0x46, 0xa9, 0xde, 0xac, 0xd8,
+	0xa8, 0xfa, 0x76, 0x3f, 0xe3, 0x9c, 0x34, 0x3f, 0xf9, 0xdc, 0xbb, 0xc7, 0xc7, 0x0b, 0x4f, 0x1d,
+	0x8a, 0x51, 0xe0, 0x4b, 0xcd, 0xb4, 0x59, 0x31, 0xc8, 0x9f, 0x7e, 0xc9, 0xd9, 0x78, 0x73, 0x64,
+	0xea, 0xc5, 0xac, 0x83, 0x34, 0xd3, 0xeb, 0xc3, 0xc5, 0x81, 0xa0, 0xff, 0xfa, 0x13, 0x63, 0xeb,
+	0x17, 0x0d, 0xdd, 0x51, 0xb7, 0xf0, 0xda, 0x49, 0xd3, 0x16, 0x55, 0x26, 0x29, 0xd4, 0x68, 0x9e,
+	0x2b, 0x16, 0xbe, 0x58, 0x7d, 0x47, 0xa1, 0xfc, 0x8f, 0xf8, 0xb8, 0xd1, 0x7a, 0xd0, 0x31, 0xce,
+	0x45, 0xcb, 0x3a, 0x8f, 0x95, 0x16, 0x04, 0x28, 0xaf, 0xd7, 0xfb, 0xca, 0xbb, 0x4b, 0x40, 0x7e,
+}
+
+var initAcc = [8]uint64{prime32_3, prime1, prime2, prime3, prime4, prime32_2, prime5, prime32_1}
+
+// digest3 is an XXH3 64-bit hash. Input is buffered until more than a block
+// and a stripe arrived, since how the last stripe is hashed depends on the
+// total length.
+type digest3 struct {
+	acc    [8]uint64
+	blocks uint64 // blocks accumulated into acc
+	buf    [blockLen + stripeLen]byte
+	n      int // bytes in buf
+}
+
+// New3 returns an XXH3 64-bit hash with the default secret and a seed of
+// zero.
+func New3() hash.Hash64 {
+	d := &digest3{}
+	d.Reset()
+	return d
+}
+
+// Sum3 returns the XXH3 64-bit digest of b with the default secret and a seed
+// of zero.
+func Sum3(b []byte) uint64 {
+	switch n := len(b); {
+	case n <= 16:
+		return hashShort(b)
+	case n <= 128:
+		return hash128(b)
+	case n <= midSizeMax:
+		return hash240(b)
+	}
+	acc := initAcc
+	return finish(&acc, b, uint64(len(b)))
+}
+
+func (d *digest3) Reset() {
+	d.acc = initAcc
+	d.blocks = 0
+	d.n = 0
+}
+
+func (d *digest3) Size() int { return Size }
+
+func (d *digest3) BlockSize() int { return stripeLen }
+
+func (d *digest3) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		if d.n == len(d.buf) {
+			accumulateBlock(&d.acc, d.buf[:])
+			d.blocks++
+			// Keep the last stripe, which the last stripe of the input may
+			// overlap.
+			d.n = copy(d.buf[:], d.buf[blockLen:])
+		}
+		k := copy(d.buf[d.n:], p)
+		d.n += k
+		p = p[k:]
+	}
+	return written, nil
+}
+
+func (d *digest3) Sum64() uint64 {
+	if d.blocks == 0 {
+		return Sum3(d.buf[:d.n])
+	}
+	// buf holds at least a stripe once a block was accumulated, so the last
+	// stripe never needs bytes from an earlier block.
+	acc := d.acc
+	return finish(&acc, d.buf[:d.n], d.blocks*blockLen+uint64(d.n))
+}
+
+func (d *digest3) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, d.Sum64())
+}
+
+const magic3 = "xxh3\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (d *digest3) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magic3)+9*8+2+d.n)
+	b = append(b, magic3...)
+	for _, v := range d.acc {
+		b = binary.LittleEndian.AppendUint64(b, v)
+	}
+	b = binary.LittleEndian.AppendUint64(b, d.blocks)
+	b = binary.LittleEndian.AppendUint16(b, uint16(d.n))
+	return append(b, d.buf[:d.n]...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *digest3) UnmarshalBinary(b []byte) error {
+	const header = len(magic3) + 9*8 + 2
+	if len(b) < header || string(b[:len(magic3)]) != magic3 {
+		return errInvalidState
+	}
+	b = b[len(magic3):]
+	for i := range d.acc {
+		d.acc[i], b = binary.LittleEndian.Uint64(b), b[8:]
+	}
+	d.blocks = binary.LittleEndian.Uint64(b)
+	n := int(binary.LittleEndian.Uint16(b[8:]))
+	b = b[10:]
+	if n != len(b) || n > len(d.buf) || (d.blocks > 0 && n < stripeLen) {
+		return errInvalidState
+	}
+	d.n = copy(d.buf[:], b)
+	return nil
+}
+
+// Clone implements hash.Cloner.
+func (d *digest3) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
+
+func u64(b []byte, i int) uint64 { return binary.LittleEndian.Uint64(b[i:]) }
+
+func mulFold64(x, y uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	return hi ^ lo
+}
+
+func avalanche(h uint64) uint64 {
+	h ^= h >> 37
+	h *= 0x165667919e3779f9
+	return h ^ h>>32
+}
+
+// hashShort hashes inputs of up to 16 bytes.
+func hashShort(b []byte) uint64 {
+	n := len(b)
+	switch {
+	case n > 8:
+		lo := u64(b, 0) ^ (u64(secret[:], 24) ^ u64(secret[:], 32))
+		hi := u64(b, n-8) ^ (u64(secret[:], 40) ^ u64(secret[:], 48))
+		return avalanche(uint64(n) + bits.ReverseBytes64(lo) + hi + mulFold64(lo, hi))
+	case n >= 4:
+		in := uint64(binary.LittleEndian.Uint32(b[n-4:])) | uint64(binary.LittleEndian.Uint32(b))<<32
+		h := in ^ (u64(secret[:], 8) ^ u64(secret[:], 16))
+		h ^= bits.RotateLeft64(h, 49) ^ bits.RotateLeft64(h, 24)
+		h *= 0x9fb21c651e98df25
+		h ^= h>>35 + uint64(n)
+		h *= 0x9fb21c651e98df25
+		return h ^ h>>28
+	case n > 0:
+		c := uint64(b[0])<<16 | uint64(b[n>>1])<<24 | uint64(b[n-1]) | uint64(n)<<8
+		h := c ^ uint64(binary.LittleEndian.Uint32(secret[:])^binary.LittleEndian.Uint32(secret[4:]))
+		h ^= h >> 33
+		h *= prime2
+		h ^= h >> 29
+		h *= prime3
+		return h ^ h>>32
+	}
+	h := u64(secret[:], 56) ^ u64(secret[:], 64)
+	h ^= h >> 33
+	h *= prime2
+	h ^= h >> 29
+	h *= prime3
+	return h ^ h>>32
+}
+
+// mix16 mixes 16 bytes of b at i with 16 bytes of the secret at s.
+func mix16(b []byte, i, s int) uint64 {
+	return mulFold64(u64(b, i)^u64(secret[:], s), u64(b, i+8)^u64(secret[:], s+8))
+}
+
+// hash128 hashes inputs of 17 to 128 bytes.
+func hash128(b []byte) uint64 {
+	n := len(b)
+	acc := uint64(n) * prime1
+	if n > 32 {
+		if n > 64 {
+			if n > 96 {
+				acc += mix16(b, 48, 96)
+				acc += mix16(b, n-64, 112)
+			}
+			acc += mix16(b, 32, 64)
+			acc += mix16(b, n-48, 80)
+		}
+		acc += mix16(b, 16, 32)
+		acc += mix16(b, n-32, 48)
+	}
+	acc += mix16(b, 0, 0)
+	acc += mix16(b, n-16, 16)
+	return avalanche(acc)
+}
+
+// hash240 hashes inputs of 129 to 240 bytes.
+func hash240(b []byte) uint64 {
+	n := len(b)
+	acc := uint64(n) * prime1
+	for i := 0; i < 8; i++ {
+		acc += mix16(b, 16*i, 16*i)
+	}
+	acc = avalanche(acc)
+	for i := 8; i < n/16; i++ {
+		acc += mix16(b, 16*i, 16*(i-8)+3)
+	}
+	acc += mix16(b, n-16, 136-17)
+	return avalanche(acc)
+}
+
+// accumulate512 accumulates the stripe at the start of b with the secret at s.
+func accumulate512(acc *[8]uint64, b []byte, s int) {
+	for i := range acc {
+		v := u64(b, 8*i)
+		k := v ^ u64(secret[:], s+8*i)
+		acc[i^1] += v
+		acc[i] += (k & 0xffffffff) * (k >> 32)
+	}
+}
+
+// scramble scrambles the accumulators after every block.
+func scramble(acc *[8]uint64) {
+	for i := range acc {
+		a := acc[i]
+		a ^= a >> 47
+		a ^= u64(secret[:], secretLen-stripeLen+8*i)
+		acc[i] = a * prime32_1
+	}
+}
+
+// accumulateBlock accumulates the block at the start of b.
+func accumulateBlock(acc *[8]uint64, b []byte) {
+	for i := 0; i < stripes; i++ {
+		accumulate512(acc, b[i*stripeLen:], 8*i)
+	}
+	scramble(acc)
+}
+
+// finish accumulates the rest of the input, b, which must be at least a
+// stripe long, and returns the digest of the total bytes of input.
+func finish(acc *[8]uint64, b []byte, total uint64) uint64 {
+	off := 0
+	for ; len(b)-off > blockLen; off += blockLen {
+		accumulateBlock(acc, b[off:])
+	}
+	for i := 0; i < (len(b)-off-1)/stripeLen; i++ {
+		accumulate512(acc, b[off+i*stripeLen:], 8*i)
+	}
+	// The last stripe ends with the input, overlapping the stripes before it
+	// unless the input ends on a stripe boundary.
+	accumulate512(acc, b[len(b)-stripeLen:], secretLen-stripeLen-7)
+
+	h := total * prime1
+	for i := 0; i < 4; i++ {
+		h += mulFold64(acc[2*i]^u64(secret[:], 11+16*i), acc[2*i+1]^u64(secret[:], 19+16*i))
+	}
+	return avalanche(h)
+}
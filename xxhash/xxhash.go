@@ -0,0 +1,186 @@
+// Package xxhash implements the 64-bit xxHash algorithms, XXH64 and XXH3, in
+// pure Go.
+//
+// xxHash is not a cryptographic hash: it is meant for hash tables, sharding
+// keys and detecting accidental corruption, where it is much faster than
+// SHA-256. Sum appends the digest big-endian, the canonical representation
+// printed by the xxhsum tool; Sum64 returns it as an integer.
+package xxhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of the digests in bytes.
+const Size = 8
+
+const (
+	prime1 uint64 = 11400714785074694791
+	prime2 uint64 = 14029467366897019727
+	prime3 uint64 = 1609587929392839161
+	prime4 uint64 = 9650029242287828579
+	prime5 uint64 = 2870177450012600261
+)
+
+var errInvalidState = errors.New("xxhash: invalid hash state")
+
+// digest64 is an XXH64 hash.
+type digest64 struct {
+	seed  uint64
+	v     [4]uint64
+	total uint64
+	mem   [32]byte
+	n     int // bytes in mem
+}
+
+// New64 returns an XXH64 hash with a seed of zero.
+func New64() hash.Hash64 { return New64WithSeed(0) }
+
+// New64WithSeed returns an XXH64 hash with the given seed.
+func New64WithSeed(seed uint64) hash.Hash64 {
+	d := &digest64{seed: seed}
+	d.Reset()
+	return d
+}
+
+// Sum64 returns the XXH64 digest of b with a seed of zero.
+func Sum64(b []byte) uint64 {
+	d := digest64{}
+	d.Reset()
+	d.Write(b)
+	return d.Sum64()
+}
+
+func (d *digest64) Reset() {
+	d.v = [4]uint64{d.seed + prime1 + prime2, d.seed + prime2, d.seed, d.seed - prime1}
+	d.total = 0
+	d.n = 0
+}
+
+func (d *digest64) Size() int { return Size }
+
+func (d *digest64) BlockSize() int { return 32 }
+
+func round(acc, input uint64) uint64 {
+	acc += input * prime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * prime1
+}
+
+func mergeRound(acc, v uint64) uint64 {
+	acc ^= round(0, v)
+	return acc*prime1 + prime4
+}
+
+// stripes consumes the 32 byte stripes at the start of p and returns the rest.
+func (d *digest64) stripes(p []byte) []byte {
+	for ; len(p) >= 32; p = p[32:] {
+		for i := range d.v {
+			d.v[i] = round(d.v[i], binary.LittleEndian.Uint64(p[8*i:]))
+		}
+	}
+	return p
+}
+
+func (d *digest64) Write(p []byte) (int, error) {
+	written := len(p)
+	d.total += uint64(len(p))
+	if d.n > 0 {
+		k := copy(d.mem[d.n:], p)
+		d.n += k
+		p = p[k:]
+		if d.n < len(d.mem) {
+			return written, nil
+		}
+		d.stripes(d.mem[:])
+		d.n = 0
+	}
+	p = d.stripes(p)
+	d.n = copy(d.mem[:], p)
+	return written, nil
+}
+
+func (d *digest64) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		v := d.v
+		h = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) +
+			bits.RotateLeft64(v[2], 12) + bits.RotateLeft64(v[3], 18)
+		for _, x := range v {
+			h = mergeRound(h, x)
+		}
+	} else {
+		h = d.seed + prime5
+	}
+	h += d.total
+
+	p := d.mem[:d.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= round(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*prime1 + prime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * prime1
+		h = bits.RotateLeft64(h, 23)*prime2 + prime3
+		p = p[4:]
+	}
+	for _, c := range p {
+		h ^= uint64(c) * prime5
+		h = bits.RotateLeft64(h, 11) * prime1
+	}
+
+	h ^= h >> 33
+	h *= prime2
+	h ^= h >> 29
+	h *= prime3
+	h ^= h >> 32
+	return h
+}
+
+func (d *digest64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, d.Sum64())
+}
+
+const magic64 = "xxh64\x01"
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (d *digest64) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(magic64)+6*8+len(d.mem)+1)
+	b = append(b, magic64...)
+	b = binary.LittleEndian.AppendUint64(b, d.seed)
+	for _, v := range d.v {
+		b = binary.LittleEndian.AppendUint64(b, v)
+	}
+	b = binary.LittleEndian.AppendUint64(b, d.total)
+	b = append(b, d.mem[:]...)
+	return append(b, byte(d.n)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The state must have
+// been saved from a hash with the same seed.
+func (d *digest64) UnmarshalBinary(b []byte) error {
+	if len(b) != len(magic64)+6*8+len(d.mem)+1 || string(b[:len(magic64)]) != magic64 {
+		return errInvalidState
+	}
+	b = b[len(magic64):]
+	if binary.LittleEndian.Uint64(b) != d.seed || int(b[len(b)-1]) >= len(d.mem) {
+		return errInvalidState
+	}
+	b = b[8:]
+	for i := range d.v {
+		d.v[i], b = binary.LittleEndian.Uint64(b), b[8:]
+	}
+	d.total = binary.LittleEndian.Uint64(b)
+	b = b[8+copy(d.mem[:], b[8:]):]
+	d.n = int(b[0])
+	return nil
+}
+
+// Clone implements hash.Cloner.
+func (d *digest64) Clone() (hash.Cloner, error) {
+	c := *d
+	return &c, nil
+}
@@ -0,0 +1,121 @@
+package xxhash
+
+import (
+	"encoding"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func testInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+// Vectors computed with the reference implementations. The lengths cover
+// every code path of XXH3.
+var testVectors = []struct {
+	n     int
+	xxh64 uint64
+	xxh3  uint64
+}{
+	{0, 0xef46db3751d8e999, 0x2d06800538d394c2},
+	{3, 0xe5c7bb4533bc65dd, 0x5f4299fc161c9cbb},
+	{9, 0x67d85784a7c78c5b, 0xe9612598145bb9dc},
+	{17, 0x5603e60c527599b6, 0x9ef341a99de37328},
+	{100, 0x6ac1e58032166597, 0x004e4f921a64bd1c},
+	{200, 0x50dc1079b99e879c, 0xf42a8864feaf0703},
+	{241, 0x8d643f23bf2808e1, 0x02e8cd95421c6d02},
+	{1024, 0x138e26c65048ce29, 0xe5d78bafa45b2aa5},
+	{1025, 0xcfd73aedd2d6a39d, 0xe95c42288f28186e},
+	{1088, 0xfa3e9309ff1f4ac3, 0x1a848d807034c403},
+	{1089, 0xf78a31f7e4ef0b85, 0xa51e35ec282cb1d8},
+	{5000, 0xa6833d648fd6a332, 0xb418500fc42320ee},
+}
+
+func TestVectors(t *testing.T) {
+	for _, tc := range testVectors {
+		data := testInput(tc.n)
+		if got := Sum64(data); got != tc.xxh64 {
+			t.Errorf("Sum64() of %d bytes got: %#x, wanted %#x", tc.n, got, tc.xxh64)
+		}
+		if got := Sum3(data); got != tc.xxh3 {
+			t.Errorf("Sum3() of %d bytes got: %#x, wanted %#x", tc.n, got, tc.xxh3)
+		}
+		for _, step := range []int{1, 31, 64, 1000} {
+			h64, h3 := New64(), New3()
+			for p := data; len(p) > 0; {
+				k := min(len(p), step)
+				h64.Write(p[:k])
+				h3.Write(p[:k])
+				p = p[k:]
+			}
+			if got := h64.Sum64(); got != tc.xxh64 {
+				t.Errorf("New64() of %d bytes written %d at a time got: %#x, wanted %#x", tc.n, step, got, tc.xxh64)
+			}
+			if got := h3.Sum64(); got != tc.xxh3 {
+				t.Errorf("New3() of %d bytes written %d at a time got: %#x, wanted %#x", tc.n, step, got, tc.xxh3)
+			}
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	h := New64WithSeed(42)
+	h.Write([]byte("abc"))
+	if got, want := hex.EncodeToString(h.Sum(nil)), "13c1d910702770e6"; got != want {
+		t.Errorf("New64WithSeed(42).Sum() got: %q, wanted %q", got, want)
+	}
+	h = New3()
+	h.Write([]byte("abc"))
+	if got, want := hex.EncodeToString(h.Sum(nil)), "78af5f94892f3950"; got != want {
+		t.Errorf("New3().Sum() got: %q, wanted %q", got, want)
+	}
+	h.Reset()
+	if got, want := h.Sum64(), uint64(0x2d06800538d394c2); got != want {
+		t.Errorf("New3().Sum64() after Reset got: %#x, wanted %#x", got, want)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, tc := range testVectors {
+		data := testInput(tc.n)
+		for _, split := range []int{0, tc.n / 3, tc.n} {
+			for _, f := range []struct {
+				desc string
+				new  func() hash.Hash64
+				want uint64
+			}{{"New64", New64, tc.xxh64}, {"New3", New3, tc.xxh3}} {
+				h := f.new()
+				h.Write(data[:split])
+				state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+				if err != nil {
+					t.Fatalf("%s().MarshalBinary(): %v", f.desc, err)
+				}
+				resumed := f.new()
+				if err := resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+					t.Fatalf("%s().UnmarshalBinary(): %v", f.desc, err)
+				}
+				resumed.Write(data[split:])
+				if got := resumed.Sum64(); got != f.want {
+					t.Errorf("%s() resumed at %d of %d bytes got: %#x, wanted %#x", f.desc, split, tc.n, got, f.want)
+				}
+
+				c, _ := h.(hash.Cloner).Clone()
+				clone := c.(hash.Hash64)
+				clone.Write(data[split:])
+				if got := clone.Sum64(); got != f.want {
+					t.Errorf("%s() cloned at %d of %d bytes got: %#x, wanted %#x", f.desc, split, tc.n, got, f.want)
+				}
+			}
+		}
+	}
+
+	state, _ := New64WithSeed(1).(encoding.BinaryMarshaler).MarshalBinary()
+	if err := New64().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("New64().UnmarshalBinary() of a state with another seed got no error")
+	}
+}